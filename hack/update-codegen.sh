@@ -18,7 +18,7 @@ set -x
 
 . "${CODEGEN_PKG}/kube_codegen.sh"
 
-for group in submarinerconfig submarinerdiagnoseconfig; do
+for group in submarinerconfig submarinerdiagnoseconfig clustersetconnectivity; do
    kube::codegen::gen_client \
      --output-dir "${outprefix}pkg/client/${group}" \
      --output-pkg "github.com/stolostron/submariner-addon/pkg/client/${group}" \
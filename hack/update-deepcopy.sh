@@ -20,3 +20,10 @@ GOFLAGS="" bash ${CODEGEN_PKG}/kube_codegen.sh "deepcopy" \
   "submarinerdiagnoseconfig:v1alpha1" \
   --go-header-file ${SCRIPT_ROOT}/hack/empty.txt \
   ${verify}
+
+GOFLAGS="" bash ${CODEGEN_PKG}/kube_codegen.sh "deepcopy" \
+  github.com/stolostron/submariner-addon/generated \
+  github.com/stolostron/submariner-addon/pkg/apis \
+  "clustersetconnectivity:v1alpha1" \
+  --go-header-file ${SCRIPT_ROOT}/hack/empty.txt \
+  ${verify}
@@ -0,0 +1,9 @@
+// Package v1alpha1 contains API Schema definitions for the clustersetconnectivity v1alpha1 API group
+// +k8s:deepcopy-gen=package,register
+// +k8s:conversion-gen=github.com/stolostron/submariner-addon/pkg/api/clustersetconnectivity
+// +k8s:defaulter-gen=TypeMeta
+// +k8s:openapi-gen=true
+
+// +kubebuilder:validation:Optional
+// +groupName=submarineraddon.open-cluster-management.io
+package v1alpha1
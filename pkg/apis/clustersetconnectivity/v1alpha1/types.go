@@ -0,0 +1,100 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:printcolumn:name="Healthy",type=integer,JSONPath=`.status.healthyLinks`
+// +kubebuilder:printcolumn:name="Degraded",type=integer,JSONPath=`.status.degradedLinks`
+// +kubebuilder:printcolumn:name="Missing",type=integer,JSONPath=`.status.missingLinks`
+
+// ClusterSetConnectivity represents the connectivity between the clusters of a ManagedClusterSet. It is named after
+// the ManagedClusterSet and created in its broker namespace.
+type ClusterSetConnectivity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Status represents the current connectivity between the clusters of the ManagedClusterSet.
+	// +optional
+	Status ClusterSetConnectivityStatus `json:"status,omitempty"`
+}
+
+// ClusterSetConnectivityStatus represents the connectivity between the clusters of a ManagedClusterSet.
+type ClusterSetConnectivityStatus struct {
+	// Clusters represents the names of the clusters of the ManagedClusterSet with submariner-addon installed.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Matrix represents the connection from every cluster to every other cluster, as reported by the active gateway
+	// of the source cluster.
+	// +optional
+	Matrix []ConnectivityRow `json:"matrix,omitempty"`
+
+	// AsymmetricPairs represents the pairs of clusters where the source cluster is connected to the destination
+	// cluster, but the destination cluster isn't connected to the source cluster.
+	// +optional
+	AsymmetricPairs []ClusterPair `json:"asymmetricPairs,omitempty"`
+
+	// HealthyLinks represents the number of pairs of clusters connected in both directions.
+	HealthyLinks int32 `json:"healthyLinks"`
+
+	// DegradedLinks represents the number of pairs of clusters with a connection which isn't connected in both
+	// directions.
+	DegradedLinks int32 `json:"degradedLinks"`
+
+	// MissingLinks represents the number of pairs of clusters without any connection.
+	MissingLinks int32 `json:"missingLinks"`
+}
+
+// ConnectivityRow represents the connections from a cluster to the other clusters of the ManagedClusterSet.
+type ConnectivityRow struct {
+	// Cluster represents the name of the source cluster.
+	Cluster string `json:"cluster"`
+
+	// Connections represents the connections from the source cluster to every other cluster.
+	// +optional
+	Connections []ConnectivityCell `json:"connections,omitempty"`
+}
+
+// ConnectivityCell represents the connection from a cluster to another cluster.
+type ConnectivityCell struct {
+	// Cluster represents the name of the destination cluster.
+	Cluster string `json:"cluster"`
+
+	// Status represents the status of the connection, connected, connecting, error or missing.
+	Status string `json:"status"`
+
+	// AverageRTT represents the average round trip time of the connection.
+	// +optional
+	AverageRTT string `json:"averageRTT,omitempty"`
+}
+
+// ClusterPair represents a connection from a source cluster to a destination cluster.
+type ClusterPair struct {
+	// Source represents the name of the source cluster.
+	Source string `json:"source"`
+
+	// Destination represents the name of the destination cluster.
+	Destination string `json:"destination"`
+}
+
+// ConnectionMissing is the status of a connection which isn't reported by the source cluster.
+const ConnectionMissing = "missing"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterSetConnectivityList is a collection of ClusterSetConnectivity.
+type ClusterSetConnectivityList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of ClusterSetConnectivity.
+	Items []ClusterSetConnectivity `json:"items"`
+}
@@ -0,0 +1,78 @@
+package v1alpha1
+
+// This file contains a collection of methods that can be used from go-restful to
+// generate Swagger API documentation for its models. Please read this PR for more
+// information on the implementation: https://github.com/emicklei/go-restful/pull/215
+//
+// TODOs are ignored from the parser (e.g. TODO(andronat):... || TODO:...) if and only if
+// they are on one line! For multiple line or blocks that you want to ignore use ---.
+// Any context after a --- is ignored.
+//
+// Those methods can be generated by using hack/update-swagger-docs.sh
+
+// AUTO-GENERATED FUNCTIONS START HERE
+var map_ClusterPair = map[string]string{
+	"":            "ClusterPair represents a connection from a source cluster to a destination cluster.",
+	"source":      "Source represents the name of the source cluster.",
+	"destination": "Destination represents the name of the destination cluster.",
+}
+
+func (ClusterPair) SwaggerDoc() map[string]string {
+	return map_ClusterPair
+}
+
+var map_ClusterSetConnectivity = map[string]string{
+	"":       "ClusterSetConnectivity represents the connectivity between the clusters of a ManagedClusterSet. It is named after the ManagedClusterSet and created in its broker namespace.",
+	"status": "Status represents the current connectivity between the clusters of the ManagedClusterSet.",
+}
+
+func (ClusterSetConnectivity) SwaggerDoc() map[string]string {
+	return map_ClusterSetConnectivity
+}
+
+var map_ClusterSetConnectivityList = map[string]string{
+	"":         "ClusterSetConnectivityList is a collection of ClusterSetConnectivity.",
+	"metadata": "Standard list metadata. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+	"items":    "Items is a list of ClusterSetConnectivity.",
+}
+
+func (ClusterSetConnectivityList) SwaggerDoc() map[string]string {
+	return map_ClusterSetConnectivityList
+}
+
+var map_ClusterSetConnectivityStatus = map[string]string{
+	"":                "ClusterSetConnectivityStatus represents the connectivity between the clusters of a ManagedClusterSet.",
+	"clusters":        "Clusters represents the names of the clusters of the ManagedClusterSet with submariner-addon installed.",
+	"matrix":          "Matrix represents the connection from every cluster to every other cluster, as reported by the active gateway of the source cluster.",
+	"asymmetricPairs": "AsymmetricPairs represents the pairs of clusters where the source cluster is connected to the destination cluster, but the destination cluster isn't connected to the source cluster.",
+	"healthyLinks":    "HealthyLinks represents the number of pairs of clusters connected in both directions.",
+	"degradedLinks":   "DegradedLinks represents the number of pairs of clusters with a connection which isn't connected in both directions.",
+	"missingLinks":    "MissingLinks represents the number of pairs of clusters without any connection.",
+}
+
+func (ClusterSetConnectivityStatus) SwaggerDoc() map[string]string {
+	return map_ClusterSetConnectivityStatus
+}
+
+var map_ConnectivityCell = map[string]string{
+	"":           "ConnectivityCell represents the connection from a cluster to another cluster.",
+	"cluster":    "Cluster represents the name of the destination cluster.",
+	"status":     "Status represents the status of the connection, connected, connecting, error or missing.",
+	"averageRTT": "AverageRTT represents the average round trip time of the connection.",
+}
+
+func (ConnectivityCell) SwaggerDoc() map[string]string {
+	return map_ConnectivityCell
+}
+
+var map_ConnectivityRow = map[string]string{
+	"":            "ConnectivityRow represents the connections from a cluster to the other clusters of the ManagedClusterSet.",
+	"cluster":     "Cluster represents the name of the source cluster.",
+	"connections": "Connections represents the connections from the source cluster to every other cluster.",
+}
+
+func (ConnectivityRow) SwaggerDoc() map[string]string {
+	return map_ConnectivityRow
+}
+
+// AUTO-GENERATED FUNCTIONS END HERE
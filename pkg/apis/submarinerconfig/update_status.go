@@ -44,6 +44,8 @@ func UpdateStatus(ctx context.Context, client configclient.SubmarinerConfigInter
 			return nil
 		}
 
+		updatePhase(newStatus)
+
 		config.Status = *newStatus
 		updatedConfig, err := client.UpdateStatus(ctx, config, metav1.UpdateOptions{})
 		if err != nil {
@@ -65,6 +67,15 @@ func UpdateConditionFn(cond *metav1.Condition) UpdateStatusFunc {
 	}
 }
 
+// UpdateObservedGenerationFn records the given generation as the generation of the configuration whose conditions were evaluated,
+// the phase is then determined for it. Only the writers which set the conditions for a generation use it, so that the other
+// status updates don't mark a generation as observed before it's evaluated.
+func UpdateObservedGenerationFn(generation int64) UpdateStatusFunc {
+	return func(oldStatus *configv1alpha1.SubmarinerConfigStatus) {
+		oldStatus.ObservedGeneration = generation
+	}
+}
+
 func UpdateStatusFn(cond *metav1.Condition,
 	managedClusterInfo *configv1alpha1.ManagedClusterInfo,
 ) UpdateStatusFunc {
@@ -76,3 +87,153 @@ func UpdateStatusFn(cond *metav1.Condition,
 		}
 	}
 }
+
+// UpdateGatewaysFn sets the status of the gateways, keeping the last HA status transition time of the gateways whose HA
+// status didn't change.
+func UpdateGatewaysFn(gateways []configv1alpha1.GatewayStatus) UpdateStatusFunc {
+	return func(oldStatus *configv1alpha1.SubmarinerConfigStatus) {
+		oldGateways := map[string]*configv1alpha1.GatewayStatus{}
+		for i := range oldStatus.Gateways {
+			oldGateways[oldStatus.Gateways[i].NodeName] = &oldStatus.Gateways[i]
+		}
+
+		newGateways := make([]configv1alpha1.GatewayStatus, len(gateways))
+		now := metav1.Now()
+
+		for i := range gateways {
+			gateways[i].DeepCopyInto(&newGateways[i])
+
+			newGateways[i].LastHAStatusTransitionTime = &now
+
+			if old, found := oldGateways[gateways[i].NodeName]; found && old.HAStatus == gateways[i].HAStatus {
+				newGateways[i].LastHAStatusTransitionTime = old.LastHAStatusTransitionTime
+			}
+		}
+
+		if len(newGateways) == 0 {
+			newGateways = nil
+		}
+
+		oldStatus.Gateways = newGateways
+	}
+}
+
+// UpdateAppliedManifestWorksFn sets the revisions of the applied ManifestWorks, keeping the last applied time of the ManifestWorks
+// whose content hash didn't change.
+func UpdateAppliedManifestWorksFn(works []configv1alpha1.AppliedManifestWork) UpdateStatusFunc {
+	return func(oldStatus *configv1alpha1.SubmarinerConfigStatus) {
+		oldWorks := map[string]*configv1alpha1.AppliedManifestWork{}
+		for i := range oldStatus.AppliedManifestWorks {
+			oldWorks[oldStatus.AppliedManifestWorks[i].Name] = &oldStatus.AppliedManifestWorks[i]
+		}
+
+		newWorks := make([]configv1alpha1.AppliedManifestWork, len(works))
+		now := metav1.Now()
+
+		for i := range works {
+			works[i].DeepCopyInto(&newWorks[i])
+
+			newWorks[i].LastAppliedTime = now
+
+			if old, found := oldWorks[works[i].Name]; found && old.ContentHash == works[i].ContentHash {
+				newWorks[i].LastAppliedTime = old.LastAppliedTime
+			}
+		}
+
+		oldStatus.AppliedManifestWorks = newWorks
+	}
+}
+
+// UpdatePlannedChangesFn sets the changes planned to prepare the Submariner cluster environment, or clears them if there are none.
+func UpdatePlannedChangesFn(changes []configv1alpha1.PlannedChange) UpdateStatusFunc {
+	return func(oldStatus *configv1alpha1.SubmarinerConfigStatus) {
+		if len(changes) == 0 {
+			changes = nil
+		}
+
+		oldStatus.PlannedChanges = changes
+	}
+}
+
+// UpdatePreparationStepsFn sets the steps of the last preparation of the Submariner cluster environment, keeping the last
+// MaxPreparationSteps ones.
+func UpdatePreparationStepsFn(steps []configv1alpha1.PreparationStep) UpdateStatusFunc {
+	return func(oldStatus *configv1alpha1.SubmarinerConfigStatus) {
+		if len(steps) == 0 {
+			steps = nil
+		}
+
+		if len(steps) > configv1alpha1.MaxPreparationSteps {
+			steps = steps[len(steps)-configv1alpha1.MaxPreparationSteps:]
+		}
+
+		oldStatus.PreparationSteps = steps
+	}
+}
+
+// updatePhase summarizes the rollout of the observed generation of the configuration from the conditions set for it by the hub
+// and the managed cluster, and records when the configuration entered the phase if it changed.
+func updatePhase(status *configv1alpha1.SubmarinerConfigStatus) {
+	if status.ObservedGeneration == 0 {
+		// No generation was evaluated yet.
+		return
+	}
+
+	phase := phaseOf(status.Conditions, status.ObservedGeneration)
+	if phase == status.Phase {
+		return
+	}
+
+	status.Phase = phase
+	now := metav1.Now()
+
+	switch phase {
+	case configv1alpha1.SubmarinerConfigPhasePending:
+		status.StageTimestamps.Pending = &now
+	case configv1alpha1.SubmarinerConfigPhasePreparing:
+		status.StageTimestamps.Preparing = &now
+	case configv1alpha1.SubmarinerConfigPhaseDeploying:
+		status.StageTimestamps.Deploying = &now
+	case configv1alpha1.SubmarinerConfigPhaseReady:
+		status.StageTimestamps.Ready = &now
+	case configv1alpha1.SubmarinerConfigPhaseFailed:
+		status.StageTimestamps.Failed = &now
+	}
+}
+
+// transitionalReasons are the reasons of the stage conditions which are false only until the managed cluster catches up, the
+// configuration is still in the phase of their stage rather than failed.
+var transitionalReasons = map[string]bool{
+	configv1alpha1.SubmarinerGatewaysLabeledReasonInsufficientNodes: true,
+	configv1alpha1.SubmarinerAgentDeployedReasonUnavailable:         true,
+}
+
+func phaseOf(conditions []metav1.Condition, generation int64) configv1alpha1.SubmarinerConfigPhase {
+	stages := []struct {
+		conditionType string
+		phase         configv1alpha1.SubmarinerConfigPhase
+	}{
+		{configv1alpha1.SubmarinerConfigConditionApplied, configv1alpha1.SubmarinerConfigPhasePending},
+		{configv1alpha1.SubmarinerConfigConditionEnvPrepared, configv1alpha1.SubmarinerConfigPhasePreparing},
+		{configv1alpha1.SubmarinerConfigConditionGatewaysLabeled, configv1alpha1.SubmarinerConfigPhaseDeploying},
+		{configv1alpha1.SubmarinerConfigConditionAgentDeployed, configv1alpha1.SubmarinerConfigPhaseDeploying},
+	}
+
+	phase := configv1alpha1.SubmarinerConfigPhaseReady
+
+	for i := len(stages) - 1; i >= 0; i-- {
+		condition := meta.FindStatusCondition(conditions, stages[i].conditionType)
+
+		switch {
+		case condition == nil || condition.ObservedGeneration != generation:
+			// The condition wasn't set for this generation yet, the configuration is at most in the phase of this stage.
+			phase = stages[i].phase
+		case condition.Status == metav1.ConditionFalse && !transitionalReasons[condition.Reason]:
+			return configv1alpha1.SubmarinerConfigPhaseFailed
+		case condition.Status != metav1.ConditionTrue:
+			phase = stages[i].phase
+		}
+	}
+
+	return phase
+}
@@ -2,6 +2,7 @@ package submarinerconfig_test
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -181,21 +182,247 @@ var _ = Describe("Update Status", func() {
 			})
 		})
 	})
+
+	When("the gateways are specified", func() {
+		lastTransitionTime := metav1.Time{Time: metav1.Now().Add(-10 * time.Minute).Truncate(time.Second)}
+
+		BeforeEach(func() {
+			t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+				Gateways: []configv1alpha1.GatewayStatus{
+					{NodeName: "node-1", HAStatus: "active", LastHAStatusTransitionTime: &lastTransitionTime},
+					{NodeName: "node-2", HAStatus: "passive", LastHAStatusTransitionTime: &lastTransitionTime},
+				},
+			}
+		})
+
+		It("should only update the transition time of the gateways whose HA status changed", func() {
+			updatedStatus, updated, err := t.doUpdateStatus(submarinerconfig.UpdateGatewaysFn([]configv1alpha1.GatewayStatus{
+				{NodeName: "node-1", HAStatus: "active", PublicIP: "1.2.3.4"},
+				{NodeName: "node-2", HAStatus: "active"},
+				{NodeName: "node-3", HAStatus: "passive"},
+			}))
+			Expect(err).To(Succeed())
+			Expect(updated).To(BeTrue())
+
+			Expect(updatedStatus.Gateways).To(HaveLen(3))
+			Expect(updatedStatus.Gateways[0].PublicIP).To(Equal("1.2.3.4"))
+			Expect(updatedStatus.Gateways[0].LastHAStatusTransitionTime.Time).To(Equal(lastTransitionTime.Time))
+			Expect(updatedStatus.Gateways[1].LastHAStatusTransitionTime.Time).To(BeTemporally(">", lastTransitionTime.Time))
+			Expect(updatedStatus.Gateways[2].LastHAStatusTransitionTime).ToNot(BeNil())
+		})
+
+		Context("and they didn't change", func() {
+			It("should not update them", func() {
+				_, updated, err := t.doUpdateStatus(submarinerconfig.UpdateGatewaysFn([]configv1alpha1.GatewayStatus{
+					{NodeName: "node-1", HAStatus: "active"},
+					{NodeName: "node-2", HAStatus: "passive"},
+				}))
+				Expect(err).To(Succeed())
+				Expect(updated).To(BeFalse())
+			})
+		})
+	})
+
+	When("more preparation steps than the maximum are specified", func() {
+		It("should only keep the last ones", func() {
+			steps := make([]configv1alpha1.PreparationStep, configv1alpha1.MaxPreparationSteps+5)
+			for i := range steps {
+				steps[i] = configv1alpha1.PreparationStep{
+					Name:      fmt.Sprintf("step-%d", i),
+					StartTime: metav1.Now(),
+					Outcome:   configv1alpha1.PreparationStepOutcomeSucceeded,
+				}
+			}
+
+			updatedStatus, updated, err := t.doUpdateStatus(submarinerconfig.UpdatePreparationStepsFn(steps))
+			Expect(err).To(Succeed())
+			Expect(updated).To(BeTrue())
+
+			Expect(updatedStatus.PreparationSteps).To(HaveLen(configv1alpha1.MaxPreparationSteps))
+			Expect(updatedStatus.PreparationSteps[0].Name).To(Equal("step-5"))
+			Expect(updatedStatus.PreparationSteps[configv1alpha1.MaxPreparationSteps-1].Name).To(Equal(
+				fmt.Sprintf("step-%d", len(steps)-1)))
+		})
+	})
+
+	When("the status is updated", func() {
+		BeforeEach(func() {
+			t.generation = 2
+		})
+
+		Context("and the hub applied the current generation", func() {
+			It("should set the phase to Preparing", func() {
+				updatedStatus := t.doUpdateStageCondition(newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied,
+					metav1.ConditionTrue, 2))
+
+				Expect(updatedStatus.ObservedGeneration).To(Equal(int64(2)))
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhasePreparing))
+				Expect(updatedStatus.StageTimestamps.Preparing).ToNot(BeNil())
+			})
+		})
+
+		Context("and all the stages succeeded for the current generation", func() {
+			BeforeEach(func() {
+				t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+					Conditions: []metav1.Condition{
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied, metav1.ConditionTrue, 2),
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionEnvPrepared, metav1.ConditionTrue, 2),
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionAgentDeployed, metav1.ConditionTrue, 2),
+					},
+				}
+			})
+
+			It("should set the phase to Ready", func() {
+				updatedStatus := t.doUpdateStageCondition(newStageCondition(configv1alpha1.SubmarinerConfigConditionGatewaysLabeled,
+					metav1.ConditionTrue, 2))
+
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhaseReady))
+				Expect(updatedStatus.StageTimestamps.Ready).ToNot(BeNil())
+			})
+		})
+
+		Context("and the gateways are labeled but the Submariner agent isn't available", func() {
+			BeforeEach(func() {
+				t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+					Conditions: []metav1.Condition{
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied, metav1.ConditionTrue, 2),
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionEnvPrepared, metav1.ConditionTrue, 2),
+					},
+				}
+			})
+
+			It("should set the phase to Deploying", func() {
+				updatedStatus := t.doUpdateStageCondition(newStageCondition(configv1alpha1.SubmarinerConfigConditionGatewaysLabeled,
+					metav1.ConditionTrue, 2))
+
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhaseDeploying))
+
+				condition := newStageCondition(configv1alpha1.SubmarinerConfigConditionAgentDeployed, metav1.ConditionFalse, 2)
+				condition.Reason = configv1alpha1.SubmarinerAgentDeployedReasonUnavailable
+
+				updatedStatus = t.doUpdateStageCondition(condition)
+
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhaseDeploying))
+				Expect(updatedStatus.StageTimestamps.Failed).To(BeNil())
+
+				updatedStatus = t.doUpdateStageCondition(newStageCondition(configv1alpha1.SubmarinerConfigConditionAgentDeployed,
+					metav1.ConditionTrue, 2))
+
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhaseReady))
+			})
+		})
+
+		Context("by a writer which doesn't evaluate the generation", func() {
+			BeforeEach(func() {
+				t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+					Conditions: []metav1.Condition{
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied, metav1.ConditionTrue, 1),
+					},
+					ObservedGeneration: 1,
+					Phase:              configv1alpha1.SubmarinerConfigPhasePreparing,
+				}
+			})
+
+			It("should keep the observed generation and the phase", func() {
+				updatedStatus, updated, err := t.doUpdateStatus(submarinerconfig.UpdateGatewaysFn([]configv1alpha1.GatewayStatus{
+					{NodeName: "node-1"},
+				}))
+				Expect(err).To(Succeed())
+				Expect(updated).To(BeTrue())
+
+				Expect(updatedStatus.ObservedGeneration).To(Equal(int64(1)))
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhasePreparing))
+			})
+		})
+
+		Context("and a stage failed for the current generation", func() {
+			BeforeEach(func() {
+				t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+					Conditions: []metav1.Condition{
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied, metav1.ConditionTrue, 2),
+					},
+				}
+			})
+
+			It("should set the phase to Failed", func() {
+				updatedStatus := t.doUpdateStageCondition(newStageCondition(configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+					metav1.ConditionFalse, 2))
+
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhaseFailed))
+				Expect(updatedStatus.StageTimestamps.Failed).ToNot(BeNil())
+			})
+		})
+
+		Context("and there aren't enough nodes to label the gateways yet", func() {
+			BeforeEach(func() {
+				t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+					Conditions: []metav1.Condition{
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied, metav1.ConditionTrue, 2),
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionEnvPrepared, metav1.ConditionTrue, 2),
+					},
+				}
+			})
+
+			It("should set the phase to Deploying", func() {
+				condition := newStageCondition(configv1alpha1.SubmarinerConfigConditionGatewaysLabeled, metav1.ConditionFalse, 2)
+				condition.Reason = configv1alpha1.SubmarinerGatewaysLabeledReasonInsufficientNodes
+
+				updatedStatus := t.doUpdateStageCondition(condition)
+
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhaseDeploying))
+				Expect(updatedStatus.StageTimestamps.Failed).To(BeNil())
+			})
+		})
+
+		Context("and the stages succeeded for a previous generation", func() {
+			readyTime := metav1.Time{Time: metav1.Now().Add(-10 * time.Minute).Truncate(time.Second)}
+
+			BeforeEach(func() {
+				t.initialStatus = configv1alpha1.SubmarinerConfigStatus{
+					Conditions: []metav1.Condition{
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied, metav1.ConditionTrue, 1),
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionEnvPrepared, metav1.ConditionFalse, 1),
+						*newStageCondition(configv1alpha1.SubmarinerConfigConditionGatewaysLabeled, metav1.ConditionTrue, 1),
+					},
+					ObservedGeneration: 1,
+					Phase:              configv1alpha1.SubmarinerConfigPhaseReady,
+					StageTimestamps:    configv1alpha1.StageTimestamps{Ready: &readyTime},
+				}
+			})
+
+			It("should only consider the conditions set for the current generation", func() {
+				updatedStatus := t.doUpdateStageCondition(newStageCondition(configv1alpha1.SubmarinerConfigConditionApplied,
+					metav1.ConditionTrue, 2))
+
+				Expect(updatedStatus.ObservedGeneration).To(Equal(int64(2)))
+				Expect(updatedStatus.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhasePreparing))
+				Expect(updatedStatus.StageTimestamps.Preparing).ToNot(BeNil())
+				Expect(updatedStatus.StageTimestamps.Ready.Time).To(Equal(readyTime.Time))
+			})
+		})
+	})
 })
 
 type updateStatusTestDriver struct {
 	initialStatus configv1alpha1.SubmarinerConfigStatus
+	generation    int64
 	client        *fakeconfigclient.Clientset
 }
 
 func newUpdateStatusTestDriver() *updateStatusTestDriver {
 	t := &updateStatusTestDriver{}
 
+	BeforeEach(func() {
+		t.initialStatus = configv1alpha1.SubmarinerConfigStatus{}
+		t.generation = 0
+	})
+
 	JustBeforeEach(func() {
 		t.client = fakeconfigclient.NewSimpleClientset(&configv1alpha1.SubmarinerConfig{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      configName,
-				Namespace: namespace,
+				Name:       configName,
+				Namespace:  namespace,
+				Generation: t.generation,
 			},
 
 			Status: t.initialStatus,
@@ -206,10 +433,10 @@ func newUpdateStatusTestDriver() *updateStatusTestDriver {
 }
 
 func (t *updateStatusTestDriver) doUpdateStatus(
-	updateFuncs submarinerconfig.UpdateStatusFunc,
+	updateFuncs ...submarinerconfig.UpdateStatusFunc,
 ) (*configv1alpha1.SubmarinerConfigStatus, bool, error) {
 	return submarinerconfig.UpdateStatus(context.TODO(), t.client.SubmarineraddonV1alpha1().SubmarinerConfigs(namespace),
-		configName, updateFuncs)
+		configName, updateFuncs...)
 }
 
 func (t *updateStatusTestDriver) doUpdateCondition(newCond *metav1.Condition) *configv1alpha1.SubmarinerConfigStatus {
@@ -220,6 +447,16 @@ func (t *updateStatusTestDriver) doUpdateCondition(newCond *metav1.Condition) *c
 	return t.assertStatusConditionUpdated(updatedStatus, newCond)
 }
 
+// doUpdateStageCondition updates the given condition as the writers which evaluate its generation do.
+func (t *updateStatusTestDriver) doUpdateStageCondition(newCond *metav1.Condition) *configv1alpha1.SubmarinerConfigStatus {
+	updatedStatus, updated, err := t.doUpdateStatus(submarinerconfig.UpdateConditionFn(newCond),
+		submarinerconfig.UpdateObservedGenerationFn(newCond.ObservedGeneration))
+	Expect(err).To(Succeed())
+	Expect(updated).To(BeTrue())
+
+	return t.assertStatusConditionUpdated(updatedStatus, newCond)
+}
+
 func (t *updateStatusTestDriver) assertStatusConditionUpdated(updatedStatus *configv1alpha1.SubmarinerConfigStatus,
 	expCond *metav1.Condition,
 ) *configv1alpha1.SubmarinerConfigStatus {
@@ -270,3 +507,13 @@ func newDefaultCondition() *metav1.Condition {
 		Message: "test message",
 	}
 }
+
+func newStageCondition(conditionType string, status metav1.ConditionStatus, generation int64) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             "TestReason",
+		Message:            "test message",
+		ObservedGeneration: generation,
+	}
+}
@@ -104,6 +104,21 @@ type SubmarinerConfigSpec struct {
 	// +optional
 	CredentialsSecret *corev1.LocalObjectReference `json:"credentialsSecret,omitempty"`
 
+	// DryRun plans the changes required to prepare the Submariner cluster environment with the cloud credentials, such as
+	// the security group rules, firewall rules and MachineSets to create or delete, without applying them. The planned
+	// changes are published in the status of the configuration.
+	// +optional
+	// +kubebuilder:default=false
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// InstallMode specifies how the submariner-operator is installed on the managed cluster.
+	// Available options are OLM, which installs the operator with an OperatorGroup and a Subscription,
+	// and Manifests, which deploys the operator Deployment, RBAC and CRDs directly for clusters without
+	// Operator Lifecycle Manager. If not specified, the mode is chosen from the managed cluster product.
+	// +optional
+	// +kubebuilder:validation:Enum=OLM;Manifests
+	InstallMode string `json:"installMode,omitempty"`
+
 	// SubscriptionConfig represents a Submariner subscription. SubscriptionConfig
 	// can be used to customize the Submariner subscription.
 	// +optional
@@ -117,6 +132,46 @@ type SubmarinerConfigSpec struct {
 	// GatewayConfig represents the gateways configuration of the Submariner.
 	// +optional
 	GatewayConfig `json:"gatewayConfig,omitempty"`
+
+	// ConnectionThresholds represents the latency thresholds above which a connection is reported as degraded even
+	// though it is connected.
+	// +optional
+	ConnectionThresholds *ConnectionThresholds `json:"connectionThresholds,omitempty"`
+
+	// DriftDetection represents the periodic verification that the Submariner cluster environment prepared with the cloud
+	// credentials wasn't changed since.
+	// +optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+}
+
+// DriftDetection contains the configuration of the verification of the prepared Submariner cluster environment.
+type DriftDetection struct {
+	// Interval represents how often the ports opened and the gateway MachineSets deployed in the cloud are verified once
+	// the Submariner cluster environment is prepared, at least one minute. The environment isn't verified if it's not set.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Remediate prepares the Submariner cluster environment again when a drift is detected.
+	// +optional
+	Remediate bool `json:"remediate,omitempty"`
+}
+
+// ConnectionThresholds contains the latency thresholds of the connections between the clusters.
+type ConnectionThresholds struct {
+	// MaxAverageRTT represents the maximum average round trip time of a connection, above which the connection is
+	// reported with the HighLatency reason.
+	// +optional
+	MaxAverageRTT *metav1.Duration `json:"maxAverageRTT,omitempty"`
+
+	// MaxRTTStdDev represents the maximum standard deviation of the round trip time of a connection, above which the
+	// connection is reported with the Unstable reason.
+	// +optional
+	MaxRTTStdDev *metav1.Duration `json:"maxRTTStdDev,omitempty"`
+
+	// SustainedFor represents how long a threshold must be exceeded before the connection is reported as degraded.
+	// The default value is 0, a connection is reported as soon as a threshold is exceeded.
+	// +optional
+	SustainedFor *metav1.Duration `json:"sustainedFor,omitempty"`
 }
 
 // SubscriptionConfig contains configuration specified for a submariner subscription.
@@ -147,6 +202,11 @@ type SubscriptionConfig struct {
 }
 
 type SubmarinerImagePullSpecs struct {
+	// SubmarinerOperatorImagePullSpec represents the desired image of the submariner operator. It is only used
+	// when the operator is installed with the Manifests install mode.
+	// +optional
+	SubmarinerOperatorImagePullSpec string `json:"submarinerOperatorImagePullSpec,omitempty"`
+
 	// SubmarinerImagePullSpec represents the desired image of submariner.
 	// +optional
 	SubmarinerImagePullSpec string `json:"submarinerImagePullSpec,omitempty"`
@@ -208,6 +268,41 @@ type GatewayConfig struct {
 	// +optional
 	// +kubebuilder:default=1
 	Gateways int `json:"gateways"`
+
+	// SelectionPolicy represents how the nodes labeled as gateways are selected when the gateways are not
+	// deployed on dedicated nodes, for example on vSphere and bare metal.
+	// +optional
+	SelectionPolicy *GatewaySelectionPolicy `json:"selectionPolicy,omitempty"`
+
+	// FailoverGracePeriod represents how long a node labeled as a gateway can be NotReady or cordoned before
+	// another node is labeled to replace it. It only applies when the gateways are not deployed on dedicated
+	// nodes. The default value is 5m.
+	// +optional
+	FailoverGracePeriod *metav1.Duration `json:"failoverGracePeriod,omitempty"`
+}
+
+type GatewaySelectionPolicy struct {
+	// NodeSelector restricts the gateway candidates to the nodes matching this label selector.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// ExcludeSelector excludes the nodes matching this label selector from the gateway candidates.
+	// +optional
+	ExcludeSelector *metav1.LabelSelector `json:"excludeSelector,omitempty"`
+
+	// ZoneTopologyKey represents the node label used to determine the zone of a node.
+	// The default value is `topology.kubernetes.io/zone`.
+	// +optional
+	ZoneTopologyKey string `json:"zoneTopologyKey,omitempty"`
+
+	// SpreadAcrossZones requires each gateway to be in a different zone. If there are fewer zones than the
+	// desired number of gateways, the desired number can't be satisfied.
+	// +optional
+	SpreadAcrossZones bool `json:"spreadAcrossZones,omitempty"`
+
+	// PreferredNodes represents the names of the nodes to select as gateways first, in order of preference.
+	// +optional
+	PreferredNodes []string `json:"preferredNodes,omitempty"`
 }
 
 type AWS struct {
@@ -217,6 +312,22 @@ type AWS struct {
 	// +optional
 	// +kubebuilder:default=m5n.large
 	InstanceType string `json:"instanceType,omitempty"`
+
+	// AssumeRoles represents a chain of IAM roles assumed in order, starting from the credentials in the credentials
+	// secret. The credentials of the last role are used to prepare the Submariner cluster environment.
+	// +optional
+	AssumeRoles []AWSAssumeRole `json:"assumeRoles,omitempty"`
+}
+
+// AWSAssumeRole represents an IAM role assumed with AWS STS.
+type AWSAssumeRole struct {
+	// RoleARN is the ARN of the IAM role to assume.
+	// +required
+	RoleARN string `json:"roleARN"`
+
+	// ExternalID is the external ID required by the trust policy of the IAM role.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
 }
 
 type GCP struct {
@@ -246,6 +357,14 @@ type Azure struct {
 	InstanceType string `json:"instanceType,omitempty"`
 }
 
+const (
+	// InstallModeOLM installs the submariner-operator through Operator Lifecycle Manager.
+	InstallModeOLM string = "OLM"
+
+	// InstallModeManifests installs the submariner-operator from plain manifests.
+	InstallModeManifests string = "Manifests"
+)
+
 const (
 	// SubmarinerConfigConditionApplied means the configuration has successfully
 	// applied.
@@ -254,15 +373,242 @@ const (
 	// SubmarinerConfigConditionEnvPrepared means the submariner cluster environment
 	// is prepared on a specfied cloud platform with the given cloud platform credentials.
 	SubmarinerConfigConditionEnvPrepared string = "SubmarinerClusterEnvironmentPrepared"
+
+	// SubmarinerConfigConditionGatewaysLabeled means the desired number of gateway nodes
+	// are labeled on the managed cluster.
+	SubmarinerConfigConditionGatewaysLabeled string = "SubmarinerGatewaysLabeled"
+
+	// SubmarinerConfigConditionAgentDeployed means the Submariner agent is deployed and available
+	// on the managed cluster.
+	SubmarinerConfigConditionAgentDeployed string = "SubmarinerAgentDeployed"
+
+	// SubmarinerConfigConditionCloudCredentialsValid means the cloud platform credentials
+	// have the permissions required to prepare the submariner cluster environment.
+	SubmarinerConfigConditionCloudCredentialsValid string = "CloudCredentialsValid"
+
+	// SubmarinerConfigConditionEnvInSync means the submariner cluster environment prepared on the cloud platform
+	// wasn't changed since, as last verified with the driftDetection configuration.
+	SubmarinerConfigConditionEnvInSync string = "SubmarinerClusterEnvironmentInSync"
+)
+
+const (
+	// SubmarinerGatewaysLabeledReasonInsufficientNodes means there aren't enough worker nodes to label the desired number
+	// of gateway nodes yet, e.g. while the gateway MachineSets scale up.
+	SubmarinerGatewaysLabeledReasonInsufficientNodes string = "InsufficientNodes"
+
+	// SubmarinerAgentDeployedReasonUnavailable means the Submariner agent isn't available on the managed cluster yet, or
+	// is degraded.
+	SubmarinerAgentDeployedReasonUnavailable string = "SubmarinerAgentUnavailable"
+)
+
+const (
+	// PlannedChangeActionCreate means the resource is created, or updated if it already exists.
+	PlannedChangeActionCreate string = "Create"
+
+	// PlannedChangeActionDelete means the resource is deleted.
+	PlannedChangeActionDelete string = "Delete"
+)
+
+const (
+	// PreparationStepOutcomeInProgress means the step hasn't ended yet.
+	PreparationStepOutcomeInProgress string = "InProgress"
+
+	// PreparationStepOutcomeSucceeded means the step succeeded.
+	PreparationStepOutcomeSucceeded string = "Succeeded"
+
+	// PreparationStepOutcomeFailed means the step failed.
+	PreparationStepOutcomeFailed string = "Failed"
+
+	// MaxPreparationSteps is the maximum number of preparation steps kept in the status.
+	MaxPreparationSteps = 20
+)
+
+// SubmarinerConfigPhase is a summary of the rollout of a generation of the configuration.
+type SubmarinerConfigPhase string
+
+const (
+	// SubmarinerConfigPhasePending means the hub hasn't applied the configuration yet.
+	SubmarinerConfigPhasePending SubmarinerConfigPhase = "Pending"
+
+	// SubmarinerConfigPhasePreparing means the hub applied the configuration, but the managed cluster
+	// hasn't prepared its environment for it yet.
+	SubmarinerConfigPhasePreparing SubmarinerConfigPhase = "Preparing"
+
+	// SubmarinerConfigPhaseDeploying means the managed cluster prepared its environment for the configuration,
+	// but hasn't labeled its gateway nodes yet or the Submariner agent isn't available yet.
+	SubmarinerConfigPhaseDeploying SubmarinerConfigPhase = "Deploying"
+
+	// SubmarinerConfigPhaseReady means the configuration is fully rolled out.
+	SubmarinerConfigPhaseReady SubmarinerConfigPhase = "Ready"
+
+	// SubmarinerConfigPhaseFailed means the hub or the managed cluster failed to apply the configuration, and won't
+	// succeed without a change of the configuration or of the environment.
+	SubmarinerConfigPhaseFailed SubmarinerConfigPhase = "Failed"
 )
 
 // SubmarinerConfigStatus represents the current status of submariner configuration.
 type SubmarinerConfigStatus struct {
-	// Conditions contain the different condition statuses for this configuration.
+	// Conditions contain the different condition statuses for this configuration. The observedGeneration of
+	// each condition is the generation of the configuration it was set for.
 	Conditions []metav1.Condition `json:"conditions"`
+	// ObservedGeneration is the generation of the configuration the phase was last determined for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase is a summary of the rollout of the observed generation of the configuration, one of
+	// Pending, Preparing, Deploying, Ready or Failed.
+	// +optional
+	// +kubebuilder:validation:Enum=Pending;Preparing;Deploying;Ready;Failed
+	Phase SubmarinerConfigPhase `json:"phase,omitempty"`
+	// StageTimestamps represents the last time the configuration entered each phase.
+	// +optional
+	StageTimestamps StageTimestamps `json:"stageTimestamps,omitempty"`
 	// ManagedClusterInfo represents the information of a managed cluster.
 	// +optional
 	ManagedClusterInfo ManagedClusterInfo `json:"managedClusterInfo,omitempty"`
+	// Gateways represents the status of the Submariner gateways of the managed cluster.
+	// +optional
+	Gateways []GatewayStatus `json:"gateways,omitempty"`
+	// AppliedManifestWorks represents the revisions of the ManifestWorks last applied on the hub to deploy Submariner on the
+	// managed cluster.
+	// +optional
+	AppliedManifestWorks []AppliedManifestWork `json:"appliedManifestWorks,omitempty"`
+	// PlannedChanges represents the changes required to prepare the Submariner cluster environment, planned when the
+	// configuration is a dry run.
+	// +optional
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+	// PreparationSteps represents the steps of the last preparation of the Submariner cluster environment, the most recent
+	// last. Only the last 20 steps are kept.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	PreparationSteps []PreparationStep `json:"preparationSteps,omitempty"`
+}
+
+// StageTimestamps represents the last time the configuration entered each phase.
+type StageTimestamps struct {
+	// Pending is the last time the configuration entered the Pending phase.
+	// +optional
+	Pending *metav1.Time `json:"pending,omitempty"`
+	// Preparing is the last time the configuration entered the Preparing phase.
+	// +optional
+	Preparing *metav1.Time `json:"preparing,omitempty"`
+	// Deploying is the last time the configuration entered the Deploying phase.
+	// +optional
+	Deploying *metav1.Time `json:"deploying,omitempty"`
+	// Ready is the last time the configuration entered the Ready phase.
+	// +optional
+	Ready *metav1.Time `json:"ready,omitempty"`
+	// Failed is the last time the configuration entered the Failed phase.
+	// +optional
+	Failed *metav1.Time `json:"failed,omitempty"`
+}
+
+// AppliedManifestWork represents the revision of a ManifestWork last applied on the hub.
+type AppliedManifestWork struct {
+	// Name is the name of the ManifestWork.
+	Name string `json:"name"`
+	// ContentHash is the hash of the content of the ManifestWork, as set in its
+	// submarineraddon.open-cluster-management.io/content-hash annotation.
+	ContentHash string `json:"contentHash"`
+	// Generation is the generation of the ManifestWork once applied.
+	Generation int64 `json:"generation"`
+	// LastAppliedTime is the last time the content of the ManifestWork changed.
+	LastAppliedTime metav1.Time `json:"lastAppliedTime"`
+}
+
+// PlannedChange represents a change of a resource required to prepare the Submariner cluster environment.
+type PlannedChange struct {
+	// Action is the action on the resource, Create or Delete.
+	// +kubebuilder:validation:Enum=Create;Delete
+	Action string `json:"action"`
+	// Kind is the kind of the resource, such as SecurityGroup, FirewallRule or MachineSet.
+	Kind string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Description describes the change.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// PreparationStep represents a step of a preparation of the Submariner cluster environment.
+type PreparationStep struct {
+	// Name describes the step.
+	Name string `json:"name"`
+	// StartTime is the time the step started.
+	StartTime metav1.Time `json:"startTime"`
+	// EndTime is the time the step ended, unset while it's in progress.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Outcome is the outcome of the step, InProgress, Succeeded or Failed.
+	// +kubebuilder:validation:Enum=InProgress;Succeeded;Failed
+	Outcome string `json:"outcome"`
+	// Message is the message reported at the end of the step.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GatewayStatus represents the status of a Submariner gateway.
+type GatewayStatus struct {
+	// NodeName is the name of the node running the gateway.
+	NodeName string `json:"nodeName"`
+	// HAStatus is the high availability status of the gateway, active or passive.
+	HAStatus string `json:"haStatus"`
+	// LastHAStatusTransitionTime is the last time the high availability status of the gateway changed.
+	// +optional
+	LastHAStatusTransitionTime *metav1.Time `json:"lastHAStatusTransitionTime,omitempty"`
+	// PublicIP is the public IP of the gateway.
+	// +optional
+	PublicIP string `json:"publicIP,omitempty"`
+	// PrivateIP is the private IP of the gateway.
+	// +optional
+	PrivateIP string `json:"privateIP,omitempty"`
+	// CableDriver is the cable driver used by the gateway.
+	// +optional
+	CableDriver string `json:"cableDriver,omitempty"`
+	// StatusFailure is the failure reported by the gateway, if any.
+	// +optional
+	StatusFailure string `json:"statusFailure,omitempty"`
+	// Connections represents the status of the connections of the gateway to the other clusters.
+	// +optional
+	Connections []GatewayConnectionStatus `json:"connections,omitempty"`
+}
+
+// GatewayConnectionStatus represents the status of a connection between a gateway and a remote cluster.
+type GatewayConnectionStatus struct {
+	// ClusterID is the ID of the remote cluster.
+	ClusterID string `json:"clusterID"`
+	// Hostname is the hostname of the remote gateway.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// Status is the status of the connection.
+	Status string `json:"status"`
+	// StatusMessage is the message describing the status of the connection.
+	// +optional
+	StatusMessage string `json:"statusMessage,omitempty"`
+	// UsingIP is the IP used to connect to the remote gateway.
+	// +optional
+	UsingIP string `json:"usingIP,omitempty"`
+	// LatencyRTT represents the round trip time statistics of the connection.
+	// +optional
+	LatencyRTT *LatencyRTTStats `json:"latencyRTT,omitempty"`
+}
+
+// LatencyRTTStats represents the round trip time statistics of a connection.
+type LatencyRTTStats struct {
+	// Last is the last measured round trip time.
+	// +optional
+	Last string `json:"last,omitempty"`
+	// Min is the minimum measured round trip time.
+	// +optional
+	Min string `json:"min,omitempty"`
+	// Average is the average measured round trip time.
+	// +optional
+	Average string `json:"average,omitempty"`
+	// Max is the maximum measured round trip time.
+	// +optional
+	Max string `json:"max,omitempty"`
+	// StdDev is the standard deviation of the measured round trip times.
+	// +optional
+	StdDev string `json:"stdDev,omitempty"`
 }
 
 type ManagedClusterInfo struct {
@@ -14,6 +14,11 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWS) DeepCopyInto(out *AWS) {
 	*out = *in
+	if in.AssumeRoles != nil {
+		in, out := &in.AssumeRoles, &out.AssumeRoles
+		*out = make([]AWSAssumeRole, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -27,6 +32,39 @@ func (in *AWS) DeepCopy() *AWS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAssumeRole) DeepCopyInto(out *AWSAssumeRole) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAssumeRole.
+func (in *AWSAssumeRole) DeepCopy() *AWSAssumeRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAssumeRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedManifestWork) DeepCopyInto(out *AppliedManifestWork) {
+	*out = *in
+	in.LastAppliedTime.DeepCopyInto(&out.LastAppliedTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedManifestWork.
+func (in *AppliedManifestWork) DeepCopy() *AppliedManifestWork {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedManifestWork)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Azure) DeepCopyInto(out *Azure) {
 	*out = *in
@@ -43,6 +81,58 @@ func (in *Azure) DeepCopy() *Azure {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionThresholds) DeepCopyInto(out *ConnectionThresholds) {
+	*out = *in
+	if in.MaxAverageRTT != nil {
+		in, out := &in.MaxAverageRTT, &out.MaxAverageRTT
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRTTStdDev != nil {
+		in, out := &in.MaxRTTStdDev, &out.MaxRTTStdDev
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SustainedFor != nil {
+		in, out := &in.SustainedFor, &out.SustainedFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionThresholds.
+func (in *ConnectionThresholds) DeepCopy() *ConnectionThresholds {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionThresholds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCP) DeepCopyInto(out *GCP) {
 	*out = *in
@@ -62,10 +152,20 @@ func (in *GCP) DeepCopy() *GCP {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
 	*out = *in
-	out.AWS = in.AWS
+	in.AWS.DeepCopyInto(&out.AWS)
 	out.GCP = in.GCP
 	out.Azure = in.Azure
 	out.RHOS = in.RHOS
+	if in.SelectionPolicy != nil {
+		in, out := &in.SelectionPolicy, &out.SelectionPolicy
+		*out = new(GatewaySelectionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverGracePeriod != nil {
+		in, out := &in.FailoverGracePeriod, &out.FailoverGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -79,6 +179,101 @@ func (in *GatewayConfig) DeepCopy() *GatewayConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayConnectionStatus) DeepCopyInto(out *GatewayConnectionStatus) {
+	*out = *in
+	if in.LatencyRTT != nil {
+		in, out := &in.LatencyRTT, &out.LatencyRTT
+		*out = new(LatencyRTTStats)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayConnectionStatus.
+func (in *GatewayConnectionStatus) DeepCopy() *GatewayConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySelectionPolicy) DeepCopyInto(out *GatewaySelectionPolicy) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludeSelector != nil {
+		in, out := &in.ExcludeSelector, &out.ExcludeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreferredNodes != nil {
+		in, out := &in.PreferredNodes, &out.PreferredNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySelectionPolicy.
+func (in *GatewaySelectionPolicy) DeepCopy() *GatewaySelectionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySelectionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
+	*out = *in
+	if in.LastHAStatusTransitionTime != nil {
+		in, out := &in.LastHAStatusTransitionTime, &out.LastHAStatusTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]GatewayConnectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayStatus.
+func (in *GatewayStatus) DeepCopy() *GatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyRTTStats) DeepCopyInto(out *LatencyRTTStats) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyRTTStats.
+func (in *LatencyRTTStats) DeepCopy() *LatencyRTTStats {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyRTTStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedClusterInfo) DeepCopyInto(out *ManagedClusterInfo) {
 	*out = *in
@@ -95,6 +290,43 @@ func (in *ManagedClusterInfo) DeepCopy() *ManagedClusterInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreparationStep) DeepCopyInto(out *PreparationStep) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreparationStep.
+func (in *PreparationStep) DeepCopy() *PreparationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PreparationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RHOS) DeepCopyInto(out *RHOS) {
 	*out = *in
@@ -111,6 +343,42 @@ func (in *RHOS) DeepCopy() *RHOS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageTimestamps) DeepCopyInto(out *StageTimestamps) {
+	*out = *in
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = (*in).DeepCopy()
+	}
+	if in.Preparing != nil {
+		in, out := &in.Preparing, &out.Preparing
+		*out = (*in).DeepCopy()
+	}
+	if in.Deploying != nil {
+		in, out := &in.Deploying, &out.Deploying
+		*out = (*in).DeepCopy()
+	}
+	if in.Ready != nil {
+		in, out := &in.Ready, &out.Ready
+		*out = (*in).DeepCopy()
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageTimestamps.
+func (in *StageTimestamps) DeepCopy() *StageTimestamps {
+	if in == nil {
+		return nil
+	}
+	out := new(StageTimestamps)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubmarinerConfig) DeepCopyInto(out *SubmarinerConfig) {
 	*out = *in
@@ -182,7 +450,17 @@ func (in *SubmarinerConfigSpec) DeepCopyInto(out *SubmarinerConfigSpec) {
 	}
 	out.SubscriptionConfig = in.SubscriptionConfig
 	out.ImagePullSpecs = in.ImagePullSpecs
-	out.GatewayConfig = in.GatewayConfig
+	in.GatewayConfig.DeepCopyInto(&out.GatewayConfig)
+	if in.ConnectionThresholds != nil {
+		in, out := &in.ConnectionThresholds, &out.ConnectionThresholds
+		*out = new(ConnectionThresholds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -206,7 +484,34 @@ func (in *SubmarinerConfigStatus) DeepCopyInto(out *SubmarinerConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.StageTimestamps.DeepCopyInto(&out.StageTimestamps)
 	out.ManagedClusterInfo = in.ManagedClusterInfo
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]GatewayStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedManifestWorks != nil {
+		in, out := &in.AppliedManifestWorks, &out.AppliedManifestWorks
+		*out = make([]AppliedManifestWork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreparationSteps != nil {
+		in, out := &in.PreparationSteps, &out.PreparationSteps
+		*out = make([]PreparationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
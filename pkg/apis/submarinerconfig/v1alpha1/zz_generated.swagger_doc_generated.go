@@ -13,12 +13,35 @@ package v1alpha1
 // AUTO-GENERATED FUNCTIONS START HERE
 var map_AWS = map[string]string{
 	"instanceType": "InstanceType represents the Amazon Web Services EC2 instance type of the gateway node that will be created on the managed cluster. The default value is `m5n.large`.",
+	"assumeRoles":  "AssumeRoles represents a chain of IAM roles assumed in order, starting from the credentials in the credentials secret. The credentials of the last role are used to prepare the Submariner cluster environment.",
 }
 
 func (AWS) SwaggerDoc() map[string]string {
 	return map_AWS
 }
 
+var map_AWSAssumeRole = map[string]string{
+	"":           "AWSAssumeRole represents an IAM role assumed with AWS STS.",
+	"roleARN":    "RoleARN is the ARN of the IAM role to assume.",
+	"externalID": "ExternalID is the external ID required by the trust policy of the IAM role.",
+}
+
+func (AWSAssumeRole) SwaggerDoc() map[string]string {
+	return map_AWSAssumeRole
+}
+
+var map_AppliedManifestWork = map[string]string{
+	"":                "AppliedManifestWork represents the revision of a ManifestWork last applied on the hub.",
+	"name":            "Name is the name of the ManifestWork.",
+	"contentHash":     "ContentHash is the hash of the content of the ManifestWork, as set in its submarineraddon.open-cluster-management.io/content-hash annotation.",
+	"generation":      "Generation is the generation of the ManifestWork once applied.",
+	"lastAppliedTime": "LastAppliedTime is the last time the content of the ManifestWork changed.",
+}
+
+func (AppliedManifestWork) SwaggerDoc() map[string]string {
+	return map_AppliedManifestWork
+}
+
 var map_Azure = map[string]string{
 	"instanceType": "InstanceType represents the Azure Cloud Platform instance type of the gateway node that will be created on the managed cluster. The default value is `Standard_F4s_v2`.",
 }
@@ -27,6 +50,27 @@ func (Azure) SwaggerDoc() map[string]string {
 	return map_Azure
 }
 
+var map_ConnectionThresholds = map[string]string{
+	"":              "ConnectionThresholds contains the latency thresholds of the connections between the clusters.",
+	"maxAverageRTT": "MaxAverageRTT represents the maximum average round trip time of a connection, above which the connection is reported with the HighLatency reason.",
+	"maxRTTStdDev":  "MaxRTTStdDev represents the maximum standard deviation of the round trip time of a connection, above which the connection is reported with the Unstable reason.",
+	"sustainedFor":  "SustainedFor represents how long a threshold must be exceeded before the connection is reported as degraded. The default value is 0, a connection is reported as soon as a threshold is exceeded.",
+}
+
+func (ConnectionThresholds) SwaggerDoc() map[string]string {
+	return map_ConnectionThresholds
+}
+
+var map_DriftDetection = map[string]string{
+	"":          "DriftDetection contains the configuration of the verification of the prepared Submariner cluster environment.",
+	"interval":  "Interval represents how often the ports opened and the gateway MachineSets deployed in the cloud are verified once the Submariner cluster environment is prepared, at least one minute. The environment isn't verified if it's not set.",
+	"remediate": "Remediate prepares the Submariner cluster environment again when a drift is detected.",
+}
+
+func (DriftDetection) SwaggerDoc() map[string]string {
+	return map_DriftDetection
+}
+
 var map_GCP = map[string]string{
 	"instanceType": "InstanceType represents the Google Cloud Platform instance type of the gateway node that will be created on the managed cluster. The default value is `n1-standard-4`.",
 }
@@ -36,17 +80,74 @@ func (GCP) SwaggerDoc() map[string]string {
 }
 
 var map_GatewayConfig = map[string]string{
-	"aws":      "AWS represents the configuration for Amazon Web Services. If the platform of managed cluster is not Amazon Web Services, this field will be ignored.",
-	"gcp":      "GCP represents the configuration for Google Cloud Platform. If the platform of managed cluster is not Google Cloud Platform, this field will be ignored.",
-	"azure":    "Azure represents the configuration for Azure Cloud Platform. If the platform of managed cluster is not Azure Cloud Platform, this field will be ignored.",
-	"rhos":     "RHOS represents the configuration for Redhat Openstack Platform. If the platform of managed cluster is not Redhat Openstack Platform, this field will be ignored.",
-	"gateways": "Gateways represents the count of worker nodes that will be used to deploy the Submariner gateway component on the managed cluster. The default value is 1, if the value is greater than 1, the Submariner gateway HA will be enabled automatically.",
+	"aws":                 "AWS represents the configuration for Amazon Web Services. If the platform of managed cluster is not Amazon Web Services, this field will be ignored.",
+	"gcp":                 "GCP represents the configuration for Google Cloud Platform. If the platform of managed cluster is not Google Cloud Platform, this field will be ignored.",
+	"azure":               "Azure represents the configuration for Azure Cloud Platform. If the platform of managed cluster is not Azure Cloud Platform, this field will be ignored.",
+	"rhos":                "RHOS represents the configuration for Redhat Openstack Platform. If the platform of managed cluster is not Redhat Openstack Platform, this field will be ignored.",
+	"gateways":            "Gateways represents the count of worker nodes that will be used to deploy the Submariner gateway component on the managed cluster. The default value is 1, if the value is greater than 1, the Submariner gateway HA will be enabled automatically.",
+	"selectionPolicy":     "SelectionPolicy represents how the nodes labeled as gateways are selected when the gateways are not deployed on dedicated nodes, for example on vSphere and bare metal.",
+	"failoverGracePeriod": "FailoverGracePeriod represents how long a node labeled as a gateway can be NotReady or cordoned before another node is labeled to replace it. It only applies when the gateways are not deployed on dedicated nodes. The default value is 5m.",
 }
 
 func (GatewayConfig) SwaggerDoc() map[string]string {
 	return map_GatewayConfig
 }
 
+var map_GatewayConnectionStatus = map[string]string{
+	"":              "GatewayConnectionStatus represents the status of a connection between a gateway and a remote cluster.",
+	"clusterID":     "ClusterID is the ID of the remote cluster.",
+	"hostname":      "Hostname is the hostname of the remote gateway.",
+	"status":        "Status is the status of the connection.",
+	"statusMessage": "StatusMessage is the message describing the status of the connection.",
+	"usingIP":       "UsingIP is the IP used to connect to the remote gateway.",
+	"latencyRTT":    "LatencyRTT represents the round trip time statistics of the connection.",
+}
+
+func (GatewayConnectionStatus) SwaggerDoc() map[string]string {
+	return map_GatewayConnectionStatus
+}
+
+var map_GatewaySelectionPolicy = map[string]string{
+	"nodeSelector":      "NodeSelector restricts the gateway candidates to the nodes matching this label selector.",
+	"excludeSelector":   "ExcludeSelector excludes the nodes matching this label selector from the gateway candidates.",
+	"zoneTopologyKey":   "ZoneTopologyKey represents the node label used to determine the zone of a node. The default value is `topology.kubernetes.io/zone`.",
+	"spreadAcrossZones": "SpreadAcrossZones requires each gateway to be in a different zone. If there are fewer zones than the desired number of gateways, the desired number can't be satisfied.",
+	"preferredNodes":    "PreferredNodes represents the names of the nodes to select as gateways first, in order of preference.",
+}
+
+func (GatewaySelectionPolicy) SwaggerDoc() map[string]string {
+	return map_GatewaySelectionPolicy
+}
+
+var map_GatewayStatus = map[string]string{
+	"":                           "GatewayStatus represents the status of a Submariner gateway.",
+	"nodeName":                   "NodeName is the name of the node running the gateway.",
+	"haStatus":                   "HAStatus is the high availability status of the gateway, active or passive.",
+	"lastHAStatusTransitionTime": "LastHAStatusTransitionTime is the last time the high availability status of the gateway changed.",
+	"publicIP":                   "PublicIP is the public IP of the gateway.",
+	"privateIP":                  "PrivateIP is the private IP of the gateway.",
+	"cableDriver":                "CableDriver is the cable driver used by the gateway.",
+	"statusFailure":              "StatusFailure is the failure reported by the gateway, if any.",
+	"connections":                "Connections represents the status of the connections of the gateway to the other clusters.",
+}
+
+func (GatewayStatus) SwaggerDoc() map[string]string {
+	return map_GatewayStatus
+}
+
+var map_LatencyRTTStats = map[string]string{
+	"":        "LatencyRTTStats represents the round trip time statistics of a connection.",
+	"last":    "Last is the last measured round trip time.",
+	"min":     "Min is the minimum measured round trip time.",
+	"average": "Average is the average measured round trip time.",
+	"max":     "Max is the maximum measured round trip time.",
+	"stdDev":  "StdDev is the standard deviation of the measured round trip times.",
+}
+
+func (LatencyRTTStats) SwaggerDoc() map[string]string {
+	return map_LatencyRTTStats
+}
+
 var map_ManagedClusterInfo = map[string]string{
 	"clusterName":   "ClusterName represents the name of the managed cluster.",
 	"vendor":        "Vendor represents the kubernetes vendor of the managed cluster.",
@@ -61,6 +162,31 @@ func (ManagedClusterInfo) SwaggerDoc() map[string]string {
 	return map_ManagedClusterInfo
 }
 
+var map_PlannedChange = map[string]string{
+	"":            "PlannedChange represents a change of a resource required to prepare the Submariner cluster environment.",
+	"action":      "Action is the action on the resource, Create or Delete.",
+	"kind":        "Kind is the kind of the resource, such as SecurityGroup, FirewallRule or MachineSet.",
+	"name":        "Name is the name of the resource.",
+	"description": "Description describes the change.",
+}
+
+func (PlannedChange) SwaggerDoc() map[string]string {
+	return map_PlannedChange
+}
+
+var map_PreparationStep = map[string]string{
+	"":          "PreparationStep represents a step of a preparation of the Submariner cluster environment.",
+	"name":      "Name describes the step.",
+	"startTime": "StartTime is the time the step started.",
+	"endTime":   "EndTime is the time the step ended, unset while it's in progress.",
+	"outcome":   "Outcome is the outcome of the step, InProgress, Succeeded or Failed.",
+	"message":   "Message is the message reported at the end of the step.",
+}
+
+func (PreparationStep) SwaggerDoc() map[string]string {
+	return map_PreparationStep
+}
+
 var map_RHOS = map[string]string{
 	"instanceType": "InstanceType represents the Redhat Openstack instance type of the gateway node that will be created on the managed cluster. The default value is `PnTAE.CPU_4_Memory_8192_Disk_50`.",
 }
@@ -69,6 +195,19 @@ func (RHOS) SwaggerDoc() map[string]string {
 	return map_RHOS
 }
 
+var map_StageTimestamps = map[string]string{
+	"":          "StageTimestamps represents the last time the configuration entered each phase.",
+	"pending":   "Pending is the last time the configuration entered the Pending phase.",
+	"preparing": "Preparing is the last time the configuration entered the Preparing phase.",
+	"deploying": "Deploying is the last time the configuration entered the Deploying phase.",
+	"ready":     "Ready is the last time the configuration entered the Ready phase.",
+	"failed":    "Failed is the last time the configuration entered the Failed phase.",
+}
+
+func (StageTimestamps) SwaggerDoc() map[string]string {
+	return map_StageTimestamps
+}
+
 var map_SubmarinerConfig = map[string]string{
 	"":       "SubmarinerConfig represents the configuration for Submariner, the submariner-addon will use it to configure the Submariner.",
 	"spec":   "Spec defines the configuration of the Submariner",
@@ -105,9 +244,13 @@ var map_SubmarinerConfigSpec = map[string]string{
 	"forceUDPEncaps":           "ForceUDPEncaps forces UDP Encapsulation for IPSec.",
 	"Debug":                    "Debug enables Submariner debugging (in the logs).",
 	"credentialsSecret":        "CredentialsSecret is a reference to the secret with a certain cloud platform credentials, the supported platform includes AWS, GCP, Azure, ROKS and OSD. The submariner-addon will use these credentials to prepare Submariner cluster environment. If the submariner cluster environment requires submariner-addon preparation, this field should be specified.",
+	"dryRun":                   "DryRun plans the changes required to prepare the Submariner cluster environment with the cloud credentials, such as the security group rules, firewall rules and MachineSets to create or delete, without applying them. The planned changes are published in the status of the configuration.",
+	"installMode":              "InstallMode specifies how the submariner-operator is installed on the managed cluster. Available options are OLM, which installs the operator with an OperatorGroup and a Subscription, and Manifests, which deploys the operator Deployment, RBAC and CRDs directly for clusters without Operator Lifecycle Manager. If not specified, the mode is chosen from the managed cluster product.",
 	"subscriptionConfig":       "SubscriptionConfig represents a Submariner subscription. SubscriptionConfig can be used to customize the Submariner subscription.",
 	"imagePullSpecs":           "ImagePullSpecs represents the desired images of submariner components installed on the managed cluster. If not specified, the default submariner images that was defined by submariner operator will be used.",
 	"gatewayConfig":            "GatewayConfig represents the gateways configuration of the Submariner.",
+	"connectionThresholds":     "ConnectionThresholds represents the latency thresholds above which a connection is reported as degraded even though it is connected.",
+	"driftDetection":           "DriftDetection represents the periodic verification that the Submariner cluster environment prepared with the cloud credentials wasn't changed since.",
 }
 
 func (SubmarinerConfigSpec) SwaggerDoc() map[string]string {
@@ -115,9 +258,16 @@ func (SubmarinerConfigSpec) SwaggerDoc() map[string]string {
 }
 
 var map_SubmarinerConfigStatus = map[string]string{
-	"":                   "SubmarinerConfigStatus represents the current status of submariner configuration.",
-	"conditions":         "Conditions contain the different condition statuses for this configuration.",
-	"managedClusterInfo": "ManagedClusterInfo represents the information of a managed cluster.",
+	"":                     "SubmarinerConfigStatus represents the current status of submariner configuration.",
+	"conditions":           "Conditions contain the different condition statuses for this configuration. The observedGeneration of each condition is the generation of the configuration it was set for.",
+	"observedGeneration":   "ObservedGeneration is the generation of the configuration the phase was last determined for.",
+	"phase":                "Phase is a summary of the rollout of the observed generation of the configuration, one of Pending, Preparing, Deploying, Ready or Failed.",
+	"stageTimestamps":      "StageTimestamps represents the last time the configuration entered each phase.",
+	"managedClusterInfo":   "ManagedClusterInfo represents the information of a managed cluster.",
+	"gateways":             "Gateways represents the status of the Submariner gateways of the managed cluster.",
+	"appliedManifestWorks": "AppliedManifestWorks represents the revisions of the ManifestWorks last applied on the hub to deploy Submariner on the managed cluster.",
+	"plannedChanges":       "PlannedChanges represents the changes required to prepare the Submariner cluster environment, planned when the configuration is a dry run.",
+	"preparationSteps":     "PreparationSteps represents the steps of the last preparation of the Submariner cluster environment, the most recent last. Only the last 20 steps are kept.",
 }
 
 func (SubmarinerConfigStatus) SwaggerDoc() map[string]string {
@@ -125,6 +275,7 @@ func (SubmarinerConfigStatus) SwaggerDoc() map[string]string {
 }
 
 var map_SubmarinerImagePullSpecs = map[string]string{
+	"submarinerOperatorImagePullSpec":            "SubmarinerOperatorImagePullSpec represents the desired image of the submariner operator. It is only used when the operator is installed with the Manifests install mode.",
 	"submarinerImagePullSpec":                    "SubmarinerImagePullSpec represents the desired image of submariner.",
 	"lighthouseAgentImagePullSpec":               "LighthouseAgentImagePullSpec represents the desired image of the lighthouse agent.",
 	"lighthouseCoreDNSImagePullSpec":             "LighthouseCoreDNSImagePullSpec represents the desired image of lighthouse coredns.",
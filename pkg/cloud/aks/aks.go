@@ -0,0 +1,400 @@
+package aks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/azure"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// The NSG rule priorities must be unique within an NSG, the Submariner rules use free priorities in a range unlikely to
+	// be used by others.
+	basePriority    = 3900
+	maxPriority     = 4096
+	anyAddress      = "*"
+	internalAddress = "VirtualNetwork"
+)
+
+// NSGAPI is the subset of the Azure network API used to open the Submariner ports to the gateways on the node security groups.
+type NSGAPI interface {
+	ListSecurityGroups(ctx context.Context, resourceGroup string) ([]string, error)
+	ListRules(ctx context.Context, resourceGroup, nsgName string) ([]*armnetwork.SecurityRule, error)
+	CreateOrUpdateRule(ctx context.Context, resourceGroup, nsgName string, rule *armnetwork.SecurityRule) error
+	DeleteRule(ctx context.Context, resourceGroup, nsgName, ruleName string) error
+}
+
+type nsgClient struct {
+	groups *armnetwork.SecurityGroupsClient
+	rules  *armnetwork.SecurityRulesClient
+}
+
+type aroFirewall struct{}
+
+type firewall struct {
+	client      NSGAPI
+	kubeClient  kubernetes.Interface
+	clusterName string
+}
+
+// NewFirewall returns a Firewall for the AKS or ARO cluster described by the given info, using its service principal.
+func NewFirewall(info *provider.Info) (managed.Firewall, error) {
+	credential, subscriptionID, err := azure.NewCredential(info.CredentialsSecret, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newNSGClient(subscriptionID, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFirewallWithClient(client, info.KubeClient, info.ClusterName), nil
+}
+
+// NewAROFirewall returns a Firewall for the ARO cluster described by the given info. The node resource group of an ARO
+// cluster is protected by a deny assignment, only the ARO resource provider can modify its network security groups: the
+// ports can't be opened, they must be opened by the user on the network security groups of the cluster subnets.
+func NewAROFirewall(_ *provider.Info) (managed.Firewall, error) {
+	return aroFirewall{}, nil
+}
+
+func (aroFirewall) OpenPorts(_ context.Context, _ []corev1.Node, ports managed.Ports) error {
+	opened := []string{}
+	for _, port := range append(append([]cpapi.PortSpec{}, ports.Public...), ports.Internal...) {
+		opened = append(opened, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+	}
+
+	return fmt.Errorf("%w: the network security groups of the node resource group of ARO clusters can't be modified, "+
+		"open the ports %s on the network security groups of the cluster subnets", provider.ErrUnsupported,
+		strings.Join(opened, ", "))
+}
+
+// ClosePorts has nothing to delete, no rule is created on ARO.
+func (aroFirewall) ClosePorts(_ context.Context, _ []corev1.Node, _ managed.Ports) error {
+	return nil
+}
+
+// NewFirewallWithClient returns a Firewall which adds Submariner rules, named after the given cluster, to the network
+// security groups of the node resource groups of the cluster, using the given client. The rules only allow the traffic to
+// the addresses of the gateway nodes, the node security groups are shared by all the nodes of the cluster.
+func NewFirewallWithClient(client NSGAPI, kubeClient kubernetes.Interface, clusterName string) managed.Firewall {
+	return &firewall{client: client, kubeClient: kubeClient, clusterName: clusterName}
+}
+
+// OpenPorts creates or updates the rules for the given ports and gateway addresses, and deletes the rules of the cluster
+// which are no longer desired.
+func (f *firewall) OpenPorts(ctx context.Context, gateways []corev1.Node, ports managed.Ports) error {
+	addresses, err := addressesOf(gateways)
+	if err != nil {
+		return err
+	}
+
+	return f.forEachSecurityGroup(ctx, func(resourceGroup, nsgName string) error {
+		existing, err := f.client.ListRules(ctx, resourceGroup, nsgName)
+		if err != nil {
+			return errors.Wrapf(err, "error listing the rules in security group %q", nsgName)
+		}
+
+		desired := f.rulesFor(ports, addresses)
+		desiredNames := sets.New[string]()
+
+		for _, rule := range desired {
+			desiredNames.Insert(*rule.Name)
+		}
+
+		// The stale rules are deleted first, to free their priorities.
+		usedPriorities := sets.New[int32]()
+		priorities := map[string]int32{}
+
+		for _, rule := range existing {
+			name := ptr.Deref(rule.Name, "")
+			priority := int32(0)
+
+			if rule.Properties != nil {
+				priority = ptr.Deref(rule.Properties.Priority, 0)
+			}
+
+			if f.isOwnRule(name) && !desiredNames.Has(name) {
+				if err := f.client.DeleteRule(ctx, resourceGroup, nsgName, name); err != nil {
+					return errors.Wrapf(err, "error deleting rule %q in security group %q", name, nsgName)
+				}
+
+				continue
+			}
+
+			usedPriorities.Insert(priority)
+			priorities[name] = priority
+		}
+
+		for _, rule := range desired {
+			priority, found := priorities[*rule.Name]
+			if !found {
+				priority, err = nextFreePriority(usedPriorities)
+				if err != nil {
+					return errors.Wrapf(err, "unable to create rule %q in security group %q", *rule.Name, nsgName)
+				}
+
+				usedPriorities.Insert(priority)
+			}
+
+			rule.Properties.Priority = ptr.To(priority)
+
+			if err := f.client.CreateOrUpdateRule(ctx, resourceGroup, nsgName, rule); err != nil {
+				return errors.Wrapf(err, "error creating rule %q in security group %q", *rule.Name, nsgName)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ClosePorts deletes all the rules of the cluster, found by their name in the node resource groups, even if no node is
+// labeled as gateway anymore.
+func (f *firewall) ClosePorts(ctx context.Context, _ []corev1.Node, _ managed.Ports) error {
+	return f.forEachSecurityGroup(ctx, func(resourceGroup, nsgName string) error {
+		existing, err := f.client.ListRules(ctx, resourceGroup, nsgName)
+		if err != nil {
+			return errors.Wrapf(err, "error listing the rules in security group %q", nsgName)
+		}
+
+		for _, rule := range existing {
+			name := ptr.Deref(rule.Name, "")
+			if !f.isOwnRule(name) {
+				continue
+			}
+
+			if err := f.client.DeleteRule(ctx, resourceGroup, nsgName, name); err != nil {
+				return errors.Wrapf(err, "error deleting rule %q in security group %q", name, nsgName)
+			}
+		}
+
+		return nil
+	})
+}
+
+// forEachSecurityGroup calls the given function for each network security group of the node resource groups of the cluster.
+func (f *firewall) forEachSecurityGroup(ctx context.Context, fn func(resourceGroup, nsgName string) error) error {
+	nodes, err := f.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "error listing the nodes")
+	}
+
+	resourceGroups := sets.New[string]()
+
+	for i := range nodes.Items {
+		resourceGroup, err := resourceGroupFor(&nodes.Items[i])
+		if err != nil {
+			return err
+		}
+
+		resourceGroups.Insert(resourceGroup)
+	}
+
+	for _, resourceGroup := range sets.List(resourceGroups) {
+		nsgNames, err := f.client.ListSecurityGroups(ctx, resourceGroup)
+		if err != nil {
+			return errors.Wrapf(err, "error listing the security groups in resource group %q", resourceGroup)
+		}
+
+		for _, nsgName := range nsgNames {
+			if err := fn(resourceGroup, nsgName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resourceGroupFor extracts the node resource group from a node provider ID of the form
+// azure:///subscriptions/<id>/resourceGroups/<group>/providers/...
+func resourceGroupFor(node *corev1.Node) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(node.Spec.ProviderID, "azure://"), "/")
+
+	for i := range parts {
+		if strings.EqualFold(parts[i], "resourceGroups") && i+1 < len(parts) {
+			return parts[i+1], nil
+		}
+	}
+
+	return "", fmt.Errorf("node %q has an unexpected provider ID %q", node.Name, node.Spec.ProviderID)
+}
+
+// addressesOf returns the internal addresses of the given gateway nodes, the addresses of their network interfaces.
+func addressesOf(gateways []corev1.Node) ([]string, error) {
+	addresses := sets.New[string]()
+
+	for i := range gateways {
+		found := false
+
+		for _, address := range gateways[i].Status.Addresses {
+			if address.Type == corev1.NodeInternalIP {
+				addresses.Insert(address.Address)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("node %q doesn't have an internal address", gateways[i].Name)
+		}
+	}
+
+	return sets.List(addresses), nil
+}
+
+func (f *firewall) rulesFor(ports managed.Ports, destinations []string) []*armnetwork.SecurityRule {
+	rules := []*armnetwork.SecurityRule{}
+
+	if len(destinations) == 0 {
+		return rules
+	}
+
+	for _, port := range ports.Public {
+		rules = append(rules, f.ruleFor(port, anyAddress, destinations))
+	}
+
+	for _, port := range ports.Internal {
+		rules = append(rules, f.ruleFor(port, internalAddress, destinations))
+	}
+
+	return rules
+}
+
+func (f *firewall) ruleFor(port cpapi.PortSpec, source string, destinations []string) *armnetwork.SecurityRule {
+	return &armnetwork.SecurityRule{
+		Name: to.Ptr(fmt.Sprintf("%s%s-%d", f.rulePrefix(), port.Protocol, port.Port)),
+		Properties: &armnetwork.SecurityRulePropertiesFormat{
+			Protocol:                   to.Ptr(protocolFor(port.Protocol)),
+			SourcePortRange:            to.Ptr(anyAddress),
+			SourceAddressPrefix:        to.Ptr(source),
+			DestinationPortRange:       to.Ptr(strconv.Itoa(int(port.Port))),
+			DestinationAddressPrefixes: to.SliceOfPtrs(destinations...),
+			Access:                     to.Ptr(armnetwork.SecurityRuleAccessAllow),
+			Direction:                  to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+		},
+	}
+}
+
+func (f *firewall) rulePrefix() string {
+	return fmt.Sprintf("submariner-%s-", f.clusterName)
+}
+
+// isOwnRule returns whether the given rule is a rule of the cluster, named <prefix><protocol>-<port>: the rules of another
+// cluster whose name starts with the name of the cluster can't match.
+func (f *firewall) isOwnRule(name string) bool {
+	rest, found := strings.CutPrefix(name, f.rulePrefix())
+	if !found {
+		return false
+	}
+
+	_, port, found := strings.Cut(rest, "-")
+	_, err := strconv.Atoi(port)
+
+	return found && err == nil
+}
+
+func nextFreePriority(used sets.Set[int32]) (int32, error) {
+	for priority := int32(basePriority); priority <= maxPriority; priority++ {
+		if !used.Has(priority) {
+			return priority, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no priority is free between %d and %d", basePriority, maxPriority)
+}
+
+func protocolFor(protocol string) armnetwork.SecurityRuleProtocol {
+	switch strings.ToLower(protocol) {
+	case "udp":
+		return armnetwork.SecurityRuleProtocolUDP
+	case "tcp":
+		return armnetwork.SecurityRuleProtocolTCP
+	}
+
+	return armnetwork.SecurityRuleProtocolAsterisk
+}
+
+func newNSGClient(subscriptionID string, credential azcore.TokenCredential) (*nsgClient, error) {
+	groups, err := armnetwork.NewSecurityGroupsClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the security groups client")
+	}
+
+	rules, err := armnetwork.NewSecurityRulesClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the security rules client")
+	}
+
+	return &nsgClient{groups: groups, rules: rules}, nil
+}
+
+func (c *nsgClient) ListSecurityGroups(ctx context.Context, resourceGroup string) ([]string, error) {
+	names := []string{}
+	pager := c.groups.NewListPager(resourceGroup, nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range page.Value {
+			names = append(names, *group.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (c *nsgClient) ListRules(ctx context.Context, resourceGroup, nsgName string) ([]*armnetwork.SecurityRule, error) {
+	rules := []*armnetwork.SecurityRule{}
+	pager := c.rules.NewListPager(resourceGroup, nsgName, nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, page.Value...)
+	}
+
+	return rules, nil
+}
+
+func (c *nsgClient) CreateOrUpdateRule(ctx context.Context, resourceGroup, nsgName string, rule *armnetwork.SecurityRule) error {
+	poller, err := c.rules.BeginCreateOrUpdate(ctx, resourceGroup, nsgName, *rule.Name, *rule, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+
+	return err
+}
+
+func (c *nsgClient) DeleteRule(ctx context.Context, resourceGroup, nsgName, ruleName string) error {
+	poller, err := c.rules.BeginDelete(ctx, resourceGroup, nsgName, ruleName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+
+	return err
+}
@@ -0,0 +1,13 @@
+package aks_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAKS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AKS Cloud Provider Suite")
+}
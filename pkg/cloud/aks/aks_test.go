@@ -0,0 +1,253 @@
+package aks_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/aks"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeFake "k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	resourceGroup = "MC_test_test-cluster_eastus"
+	nsgName       = "aks-agentpool-nsg"
+	clusterName   = "test-cluster"
+)
+
+type fakeNSG struct {
+	securityGroups map[string][]string
+	rules          map[string][]*armnetwork.SecurityRule
+	createErr      error
+}
+
+func (f *fakeNSG) ListSecurityGroups(_ context.Context, group string) ([]string, error) {
+	return f.securityGroups[group], nil
+}
+
+func (f *fakeNSG) ListRules(_ context.Context, group, nsg string) ([]*armnetwork.SecurityRule, error) {
+	return f.rules[group+"/"+nsg], nil
+}
+
+func (f *fakeNSG) CreateOrUpdateRule(_ context.Context, group, nsg string, rule *armnetwork.SecurityRule) error {
+	if f.createErr != nil {
+		return f.createErr
+	}
+
+	key := group + "/" + nsg
+
+	for _, existing := range f.rules[key] {
+		if *existing.Name != *rule.Name && *existing.Properties.Priority == *rule.Properties.Priority {
+			return errors.New("the priority is already used")
+		}
+	}
+
+	f.deleteRule(key, *rule.Name)
+	f.rules[key] = append(f.rules[key], rule)
+
+	return nil
+}
+
+func (f *fakeNSG) DeleteRule(_ context.Context, group, nsg, ruleName string) error {
+	f.deleteRule(group+"/"+nsg, ruleName)
+	return nil
+}
+
+func (f *fakeNSG) deleteRule(key, ruleName string) {
+	rules := []*armnetwork.SecurityRule{}
+
+	for _, rule := range f.rules[key] {
+		if *rule.Name != ruleName {
+			rules = append(rules, rule)
+		}
+	}
+
+	f.rules[key] = rules
+}
+
+func (f *fakeNSG) ruleNames() []string {
+	names := []string{}
+
+	for _, rule := range f.rules[resourceGroup+"/"+nsgName] {
+		names = append(names, *rule.Name)
+	}
+
+	return names
+}
+
+// destinationsOf returns the destination addresses of the given rule.
+func (f *fakeNSG) destinationsOf(ruleName string) []string {
+	for _, rule := range f.rules[resourceGroup+"/"+nsgName] {
+		if *rule.Name != ruleName {
+			continue
+		}
+
+		destinations := []string{}
+		for _, destination := range rule.Properties.DestinationAddressPrefixes {
+			destinations = append(destinations, *destination)
+		}
+
+		return destinations
+	}
+
+	return nil
+}
+
+var _ = Describe("AKS firewall", func() {
+	var (
+		client     *fakeNSG
+		kubeClient *kubeFake.Clientset
+		firewall   managed.Firewall
+		gateways   []corev1.Node
+		ports      managed.Ports
+	)
+
+	BeforeEach(func() {
+		client = &fakeNSG{
+			securityGroups: map[string][]string{resourceGroup: {nsgName}},
+			rules: map[string][]*armnetwork.SecurityRule{
+				resourceGroup + "/" + nsgName: {{
+					Name:       to.Ptr("other"),
+					Properties: &armnetwork.SecurityRulePropertiesFormat{Priority: to.Ptr(int32(3900))},
+				}},
+			},
+		}
+
+		gateways = []corev1.Node{newNode("node-1", "10.224.0.4"), newNode("node-2", "10.224.0.5")}
+		kubeClient = kubeFake.NewSimpleClientset(&gateways[0], &gateways[1])
+		gateways = gateways[:1]
+
+		firewall = aks.NewFirewallWithClient(client, kubeClient, clusterName)
+
+		ports = managed.Ports{
+			Public:   []cpapi.PortSpec{{Port: 4500, Protocol: "udp"}, {Port: 4490, Protocol: "udp"}},
+			Internal: []cpapi.PortSpec{{Port: 4800, Protocol: "udp"}},
+		}
+	})
+
+	Context("OpenPorts", func() {
+		It("should create the rules named after the cluster with free priorities", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.ruleNames()).To(ConsistOf("other", "submariner-test-cluster-udp-4500", "submariner-test-cluster-udp-4490",
+				"submariner-test-cluster-udp-4800"))
+
+			for _, rule := range client.rules[resourceGroup+"/"+nsgName] {
+				if strings.HasPrefix(*rule.Name, "submariner-") {
+					Expect(*rule.Properties.Priority).ToNot(Equal(int32(3900)))
+				}
+
+				if *rule.Name == "submariner-test-cluster-udp-4800" {
+					Expect(*rule.Properties.SourceAddressPrefix).To(Equal("VirtualNetwork"))
+				}
+			}
+		})
+
+		It("should only allow the traffic to the addresses of the gateways", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.destinationsOf("submariner-test-cluster-udp-4500")).To(ConsistOf("10.224.0.4"))
+			Expect(client.destinationsOf("submariner-test-cluster-udp-4800")).To(ConsistOf("10.224.0.4"))
+		})
+
+		When("the gateways changed", func() {
+			It("should update the destinations of the rules", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+				gateways = []corev1.Node{newNode("node-2", "10.224.0.5")}
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.destinationsOf("submariner-test-cluster-udp-4500")).To(ConsistOf("10.224.0.5"))
+			})
+		})
+
+		When("a gateway doesn't have an internal address", func() {
+			BeforeEach(func() {
+				gateways[0].Status.Addresses = nil
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+
+		It("should delete the rules for the ports which are no longer desired", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+			ports.Public = []cpapi.PortSpec{{Port: 4501, Protocol: "udp"}}
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.ruleNames()).To(ConsistOf("other", "submariner-test-cluster-udp-4501", "submariner-test-cluster-udp-4800"))
+		})
+
+		When("another cluster shares the security group", func() {
+			It("should keep the rules of the other cluster", func() {
+				Expect(aks.NewFirewallWithClient(client, kubeClient, "other-cluster").OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.ruleNames()).To(HaveLen(7))
+			})
+		})
+
+		When("another cluster whose name starts with the name of the cluster shares the security group", func() {
+			It("should keep the rules of the other cluster", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+				ports.Public = nil
+				Expect(aks.NewFirewallWithClient(client, kubeClient, "test").OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.ruleNames()).To(HaveLen(5))
+			})
+		})
+
+		When("creating a rule fails", func() {
+			BeforeEach(func() {
+				client.createErr = errors.New("fake error")
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+
+		When("a node has an unexpected provider ID", func() {
+			BeforeEach(func() {
+				node := newNode("node-3", "10.224.0.6")
+				node.Spec.ProviderID = "gce://project/zone/instance"
+				Expect(kubeClient.Tracker().Add(&node)).To(Succeed())
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+	})
+
+	Context("ClosePorts", func() {
+		BeforeEach(func() {
+			Expect(aks.NewFirewallWithClient(client, kubeClient, "other-cluster").OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+		})
+
+		It("should only delete the rules of the cluster, even without gateways", func() {
+			Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+			Expect(client.ruleNames()).To(ConsistOf("other", "submariner-other-cluster-udp-4500", "submariner-other-cluster-udp-4490",
+				"submariner-other-cluster-udp-4800"))
+		})
+	})
+})
+
+func newNode(name, address string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.NodeSpec{
+			ProviderID: "azure:///subscriptions/sub-1/resourceGroups/" + resourceGroup +
+				"/providers/Microsoft.Compute/virtualMachineScaleSets/aks-agentpool/virtualMachines/" + name,
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+		},
+	}
+}
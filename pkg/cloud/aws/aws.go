@@ -1,18 +1,20 @@
 package aws
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
 	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/cloud/reporter"
-	"github.com/stolostron/submariner-addon/pkg/constants"
 	submreporter "github.com/submariner-io/admiral/pkg/reporter"
 	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
 	cpaws "github.com/submariner-io/cloud-prepare/pkg/aws"
-	cpclient "github.com/submariner-io/cloud-prepare/pkg/aws/client"
 	"github.com/submariner-io/cloud-prepare/pkg/ocp"
-	"github.com/submariner-io/submariner/pkg/cni"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -24,14 +26,17 @@ const (
 )
 
 type awsProvider struct {
-	reporter          submreporter.Interface
-	nattPort          int64
-	nattDiscoveryPort int64
-	instanceType      string
-	cniType           string
-	gateways          int
-	cloudPrepare      cpapi.Cloud
-	gatewayDeployer   cpapi.GatewayDeployer
+	ports           ports.Plan
+	reporter        submreporter.Interface
+	nattPort        int64
+	instanceType    string
+	gateways        int
+	cloudPrepare    cpapi.Cloud
+	gatewayDeployer cpapi.GatewayDeployer
+	ec2Client       EC2API
+	kubeClient      kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	infraID         string
 }
 
 //nolint:revive // Ignore unexported-return - we can't reference the Provider interface here.
@@ -53,24 +58,17 @@ func NewProvider(info *provider.Info) (*awsProvider, error) {
 		instanceType = defaultInstanceType
 	}
 
-	accessKeyID, ok := info.CredentialsSecret.Data[accessKeyIDSecretKey]
-	if !ok {
-		return nil, fmt.Errorf("the aws credentials key %s is not in secret %s/%s", accessKeyIDSecretKey, info.ClusterName,
-			info.CredentialsSecret.Name)
-	}
-
-	secretAccessKey, ok := info.CredentialsSecret.Data[accessKeySecretKey]
-	if !ok {
-		return nil, fmt.Errorf("the aws credentials key %s is not in secret %s/%s", accessKeySecretKey, info.ClusterName,
-			info.CredentialsSecret.Name)
-	}
-
-	awsClient, err := cpclient.New(string(accessKeyID), string(secretAccessKey), info.Region)
+	credentialsProvider, err := NewCredentialsProvider(info.CredentialsSecret, &info.GatewayConfig.AWS, info.Region)
 	if err != nil {
 		return nil, err
 	}
 
-	cloudPrepare := cpaws.NewCloud(awsClient, info.InfraID, info.Region)
+	ec2Client := ec2.New(ec2.Options{
+		Region:      info.Region,
+		Credentials: credentialsProvider,
+	})
+
+	cloudPrepare := cpaws.NewCloud(ec2Client, info.InfraID, info.Region)
 
 	machineSetDeployer := ocp.NewK8sMachinesetDeployer(info.RestMapper, info.DynamicClient)
 	gwDeployer, err := cpaws.NewOcpGatewayDeployer(cloudPrepare, machineSetDeployer, instanceType)
@@ -79,38 +77,37 @@ func NewProvider(info *provider.Info) (*awsProvider, error) {
 	}
 
 	return &awsProvider{
-		reporter:          reporter.NewEventRecorderWrapper("AWSCloudProvider", info.EventRecorder),
-		nattPort:          int64(info.IPSecNATTPort),
-		nattDiscoveryPort: int64(info.NATTDiscoveryPort),
-		cniType:           info.NetworkType,
-		instanceType:      instanceType,
-		gateways:          info.Gateways,
-		cloudPrepare:      cloudPrepare,
-		gatewayDeployer:   gwDeployer,
+		ports:           ports.For(&info.SubmarinerConfigSpec, info.NetworkType),
+		reporter:        reporter.NewEventRecorderWrapper("AWSCloudProvider", info.EventRecorder, info.StepLog),
+		nattPort:        int64(info.IPSecNATTPort),
+		instanceType:    instanceType,
+		gateways:        info.Gateways,
+		cloudPrepare:    cloudPrepare,
+		gatewayDeployer: gwDeployer,
+		ec2Client:       ec2Client,
+		kubeClient:      info.KubeClient,
+		dynamicClient:   info.DynamicClient,
+		infraID:         info.InfraID,
 	}, nil
 }
 
 // PrepareSubmarinerClusterEnv prepares submariner cluster environment on AWS.
-func (a *awsProvider) PrepareSubmarinerClusterEnv() error {
+func (a *awsProvider) PrepareSubmarinerClusterEnv(ctx context.Context) error {
 	// See AWS() in https://github.com/submariner-io/subctl/blob/devel/pkg/cloud/prepare/aws.go
 	// For now we only support at least one gateway (no load-balancer)
 	if err := a.gatewayDeployer.Deploy(cpapi.GatewayDeployInput{
-		PublicPorts: []cpapi.PortSpec{
-			{Port: uint16(a.nattPort), Protocol: "udp"},
-			{Port: uint16(a.nattDiscoveryPort), Protocol: "udp"},
-			// ESP & AH protocols are used for private-ip to private-ip gateway communications
-			{Port: 0, Protocol: "50"},
-			{Port: 0, Protocol: "51"},
-		},
-		Gateways: a.gateways,
+		PublicPorts: ports.WithProtocolNumbers(a.ports.Public),
+		Gateways:    a.gateways,
 	}, a.reporter); err != nil {
 		return err
 	}
 
-	if !strings.EqualFold(a.cniType, cni.OVNKubernetes) {
-		if err := a.cloudPrepare.OpenPorts([]cpapi.PortSpec{
-			{Port: constants.SubmarinerRoutePort, Protocol: "udp"},
-		}, a.reporter); err != nil {
+	if len(a.ports.Internal) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := a.cloudPrepare.OpenPorts(a.ports.Internal, a.reporter); err != nil {
 			return err
 		}
 	}
@@ -120,12 +117,50 @@ func (a *awsProvider) PrepareSubmarinerClusterEnv() error {
 	return nil
 }
 
+// Plan returns the changes PrepareSubmarinerClusterEnv would make on AWS: the gateway security group opening the public
+// ports, the rules opening the internal ports in the worker and master security groups, and the gateway MachineSets.
+func (a *awsProvider) Plan(ctx context.Context) ([]configv1alpha1.PlannedChange, error) {
+	changes := []configv1alpha1.PlannedChange{}
+
+	for _, rule := range a.rules() {
+		changes = append(changes, ports.Change(rule.Kind, rule.Name, rule.Ports))
+	}
+
+	machineSets, err := provider.PlanGatewayMachineSets(ctx, a.dynamicClient, a.infraID, a.gateways, a.instanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, machineSets...), nil
+}
+
+// rules returns the security groups opening the public ports, and the security groups of the cluster in which the rules
+// opening the internal ports are added.
+func (a *awsProvider) rules() []ports.Rule {
+	return securityGroupRules(a.infraID, a.ports)
+}
+
+func securityGroupRules(infraID string, plan ports.Plan) []ports.Rule {
+	rules := []ports.Rule{{Kind: "SecurityGroup", Name: infraID + "-submariner-gw-sg", Ports: ports.WithProtocolNumbers(plan.Public)}}
+
+	if len(plan.Internal) > 0 {
+		rules = append(rules, ports.Rule{Kind: "SecurityGroupRule", Name: infraID + "-worker-sg", Ports: plan.Internal},
+			ports.Rule{Kind: "SecurityGroupRule", Name: infraID + "-master-sg", Ports: plan.Internal})
+	}
+
+	return rules
+}
+
 // CleanUpSubmarinerClusterEnv clean up submariner cluster environment on AWS after the SubmarinerConfig was deleted.
-func (a *awsProvider) CleanUpSubmarinerClusterEnv() error {
+func (a *awsProvider) CleanUpSubmarinerClusterEnv(ctx context.Context) error {
 	if err := a.gatewayDeployer.Cleanup(a.reporter); err != nil {
 		return err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if err := a.cloudPrepare.ClosePorts(a.reporter); err != nil {
 		return err
 	}
@@ -0,0 +1,133 @@
+package aws
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	//#nosec G101 -- This is the name of a key that will store a secret, but not a default secret
+	sessionTokenSecretKey         = "aws_session_token"
+	roleARNSecretKey              = "role_arn"
+	externalIDSecretKey           = "external_id"
+	webIdentityTokenFileSecretKey = "web_identity_token_file"
+	// sharedCredentialsSecretKey holds a shared credentials file, as in the Secrets created by the OpenShift Cloud Credential
+	// Operator for clusters using AWS STS.
+	sharedCredentialsSecretKey = "credentials"
+	roleSessionName            = "submariner-addon"
+	// defaultWebIdentityTokenFile is the path of the ServiceAccount token projected in the pod of the agent with the
+	// sts.amazonaws.com audience, which is also the path set in the Secrets created by the Cloud Credential Operator.
+	defaultWebIdentityTokenFile = "/var/run/secrets/openshift/serviceaccount/token"
+)
+
+// NewCredentialsProvider returns the AWS credentials provider described by the given credentials secret and gateway
+// configuration:
+//   - with the web_identity_token_file and role_arn keys, the role is assumed with the web identity token in the file;
+//   - with only the role_arn key, the role is assumed with the ServiceAccount token projected in the pod of the agent;
+//   - otherwise, with the aws_access_key_id and aws_secret_access_key keys, and optionally aws_session_token, the static
+//     credentials are used, to assume the role in the role_arn key with the ID in the external_id key if present.
+//
+// The roles of the gateway configuration are then assumed in order from these credentials. The options are applied to the STS
+// clients.
+func NewCredentialsProvider(secret *corev1.Secret, awsConfig *configv1alpha1.AWS, region string,
+	stsOptFns ...func(*sts.Options),
+) (aws.CredentialsProvider, error) {
+	data := secretData(secret)
+
+	newSTSClient := func(credentialsProvider aws.CredentialsProvider) *sts.Client {
+		return sts.New(sts.Options{Region: region, Credentials: credentialsProvider}, stsOptFns...)
+	}
+
+	tokenFile := data[webIdentityTokenFileSecretKey]
+	if tokenFile == "" && data[roleARNSecretKey] != "" && data[accessKeyIDSecretKey] == "" {
+		tokenFile = defaultWebIdentityTokenFile
+	}
+
+	var credentialsProvider aws.CredentialsProvider
+
+	switch {
+	case tokenFile != "":
+		if data[roleARNSecretKey] == "" {
+			return nil, fmt.Errorf("the aws credentials key %s is not in secret %s/%s: %w", roleARNSecretKey, secret.Namespace,
+				secret.Name, provider.ErrInvalidCredentials)
+		}
+
+		credentialsProvider = aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(newSTSClient(aws.AnonymousCredentials{}),
+			data[roleARNSecretKey], stscreds.IdentityTokenFile(tokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = roleSessionName
+			}))
+	default:
+		for _, key := range []string{accessKeyIDSecretKey, accessKeySecretKey} {
+			if data[key] == "" {
+				return nil, fmt.Errorf("the aws credentials key %s is not in secret %s/%s: %w", key, secret.Namespace, secret.Name,
+					provider.ErrInvalidCredentials)
+			}
+		}
+
+		credentialsProvider = credentials.NewStaticCredentialsProvider(data[accessKeyIDSecretKey], data[accessKeySecretKey],
+			data[sessionTokenSecretKey])
+
+		if data[roleARNSecretKey] != "" {
+			credentialsProvider = assumeRole(newSTSClient(credentialsProvider), configv1alpha1.AWSAssumeRole{
+				RoleARN:    data[roleARNSecretKey],
+				ExternalID: data[externalIDSecretKey],
+			})
+		}
+	}
+
+	if awsConfig != nil {
+		for _, role := range awsConfig.AssumeRoles {
+			credentialsProvider = assumeRole(newSTSClient(credentialsProvider), role)
+		}
+	}
+
+	return credentialsProvider, nil
+}
+
+func assumeRole(client *sts.Client, role configv1alpha1.AWSAssumeRole) aws.CredentialsProvider {
+	return aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(client, role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = roleSessionName
+
+		if role.ExternalID != "" {
+			o.ExternalID = aws.String(role.ExternalID)
+		}
+	}))
+}
+
+// secretData returns the keys of the secret, merged with the keys of the default profile of the shared credentials file it may
+// hold.
+func secretData(secret *corev1.Secret) map[string]string {
+	data := map[string]string{}
+
+	inDefaultProfile := true
+
+	scanner := bufio.NewScanner(bytes.NewReader(secret.Data[sharedCredentialsSecretKey]))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inDefaultProfile = line == "[default]"
+			continue
+		}
+
+		if key, value, found := strings.Cut(line, "="); found && inDefaultProfile {
+			data[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+
+	return data
+}
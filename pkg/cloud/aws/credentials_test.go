@@ -0,0 +1,192 @@
+package aws_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/aws"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const stsResponse = `<%[1]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <%[1]sResult>
+    <Credentials>
+      <AccessKeyId>%[2]s</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </%[1]sResult>
+  <ResponseMetadata>
+    <RequestId>request</RequestId>
+  </ResponseMetadata>
+</%[1]sResponse>`
+
+type stsRequest struct {
+	form          url.Values
+	authorization string
+}
+
+// fakeSTS is a stubbed STS endpoint which returns the access key "key-<n>" for the nth role assumed.
+type fakeSTS struct {
+	sync.Mutex
+	server   *httptest.Server
+	requests []stsRequest
+}
+
+func newFakeSTS() *fakeSTS {
+	f := &fakeSTS{}
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.ParseForm()).To(Succeed())
+
+		f.Lock()
+		f.requests = append(f.requests, stsRequest{form: r.PostForm, authorization: r.Header.Get("Authorization")})
+		accessKeyID := fmt.Sprintf("key-%d", len(f.requests))
+		f.Unlock()
+
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, stsResponse, r.PostForm.Get("Action"), accessKeyID)
+	}))
+
+	return f
+}
+
+func (f *fakeSTS) endpoint(o *sts.Options) {
+	o.BaseEndpoint = awssdk.String(f.server.URL)
+}
+
+var _ = Describe("NewCredentialsProvider", func() {
+	var (
+		fakeSTS   *fakeSTS
+		secret    *corev1.Secret
+		awsConfig *configv1alpha1.AWS
+	)
+
+	BeforeEach(func() {
+		fakeSTS = newFakeSTS()
+		DeferCleanup(fakeSTS.server.Close)
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "east"},
+			Data: map[string][]byte{
+				"aws_access_key_id":     []byte("static-key"),
+				"aws_secret_access_key": []byte("static-secret"),
+			},
+		}
+
+		awsConfig = &configv1alpha1.AWS{}
+	})
+
+	retrieve := func() awssdk.Credentials {
+		credentialsProvider, err := aws.NewCredentialsProvider(secret, awsConfig, "us-east-1", fakeSTS.endpoint)
+		Expect(err).To(Succeed())
+
+		credentials, err := credentialsProvider.Retrieve(context.TODO())
+		Expect(err).To(Succeed())
+
+		return credentials
+	}
+
+	When("the secret only contains static credentials", func() {
+		It("should use them without calling STS", func() {
+			Expect(retrieve().AccessKeyID).To(Equal("static-key"))
+			Expect(fakeSTS.requests).To(BeEmpty())
+		})
+	})
+
+	When("the secret contains a role and an external ID", func() {
+		BeforeEach(func() {
+			secret.Data["role_arn"] = []byte("arn:aws:iam::123456789012:role/submariner")
+			secret.Data["external_id"] = []byte("external")
+		})
+
+		It("should assume the role with the external ID from the static credentials", func() {
+			Expect(retrieve().AccessKeyID).To(Equal("key-1"))
+			Expect(fakeSTS.requests).To(HaveLen(1))
+			Expect(fakeSTS.requests[0].form.Get("Action")).To(Equal("AssumeRole"))
+			Expect(fakeSTS.requests[0].form.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/submariner"))
+			Expect(fakeSTS.requests[0].form.Get("ExternalId")).To(Equal("external"))
+			Expect(fakeSTS.requests[0].authorization).To(ContainSubstring("Credential=static-key/"))
+		})
+	})
+
+	When("the secret contains a web identity token file", func() {
+		BeforeEach(func() {
+			tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(tokenFile, []byte("web-identity-token"), 0o600)).To(Succeed())
+
+			secret.Data = map[string][]byte{
+				"credentials": []byte(fmt.Sprintf("[default]\nrole_arn = arn:aws:iam::123456789012:role/sts\n"+
+					"web_identity_token_file = %s\n", tokenFile)),
+			}
+		})
+
+		It("should assume the role with the web identity token", func() {
+			Expect(retrieve().AccessKeyID).To(Equal("key-1"))
+			Expect(fakeSTS.requests).To(HaveLen(1))
+			Expect(fakeSTS.requests[0].form.Get("Action")).To(Equal("AssumeRoleWithWebIdentity"))
+			Expect(fakeSTS.requests[0].form.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/sts"))
+			Expect(fakeSTS.requests[0].form.Get("WebIdentityToken")).To(Equal("web-identity-token"))
+		})
+	})
+
+	When("the secret only contains a role", func() {
+		BeforeEach(func() {
+			secret.Data = map[string][]byte{
+				"role_arn": []byte("arn:aws:iam::123456789012:role/sts"),
+			}
+		})
+
+		It("should assume the role with the ServiceAccount token projected in the pod", func() {
+			credentialsProvider, err := aws.NewCredentialsProvider(secret, awsConfig, "us-east-1", fakeSTS.endpoint)
+			Expect(err).To(Succeed())
+
+			_, err = credentialsProvider.Retrieve(context.TODO())
+			Expect(err).To(MatchError(ContainSubstring("/var/run/secrets/openshift/serviceaccount/token")))
+		})
+	})
+
+	When("the gateway configuration contains a chain of roles", func() {
+		BeforeEach(func() {
+			awsConfig.AssumeRoles = []configv1alpha1.AWSAssumeRole{
+				{RoleARN: "arn:aws:iam::123456789012:role/first"},
+				{RoleARN: "arn:aws:iam::210987654321:role/second", ExternalID: "external"},
+			}
+		})
+
+		It("should assume each role from the credentials of the previous one", func() {
+			Expect(retrieve().AccessKeyID).To(Equal("key-2"))
+			Expect(fakeSTS.requests).To(HaveLen(2))
+			Expect(fakeSTS.requests[0].form.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/first"))
+			Expect(fakeSTS.requests[0].authorization).To(ContainSubstring("Credential=static-key/"))
+			Expect(fakeSTS.requests[1].form.Get("RoleArn")).To(Equal("arn:aws:iam::210987654321:role/second"))
+			Expect(fakeSTS.requests[1].form.Get("ExternalId")).To(Equal("external"))
+			Expect(fakeSTS.requests[1].authorization).To(ContainSubstring("Credential=key-1/"))
+		})
+	})
+
+	When("the secret doesn't contain any credentials", func() {
+		BeforeEach(func() {
+			secret.Data = map[string][]byte{}
+		})
+
+		It("should return an invalid credentials error", func() {
+			_, err := aws.NewCredentialsProvider(secret, awsConfig, "us-east-1")
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"k8s.io/utils/ptr"
+)
+
+// DetectDrift verifies that the security groups still open the Submariner ports, and that the gateway MachineSets still
+// deploy the gateway nodes.
+func (a *awsProvider) DetectDrift(ctx context.Context) ([]string, error) {
+	drifts, err := DetectSecurityGroupsDrift(ctx, a.ec2Client, a.infraID, a.ports)
+	if err != nil {
+		return nil, err
+	}
+
+	machineSetDrifts, err := provider.DetectGatewayMachineSetsDrift(ctx, a.dynamicClient, a.infraID, a.gateways)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(drifts, machineSetDrifts...), nil
+}
+
+// DetectSecurityGroupsDrift verifies that the security groups of the cluster with the given infra ID still open the ports of
+// the given plan. The security groups are looked up in the VPC of the cluster by their Name tag, the worker and master
+// security groups created by the installer aren't named after it otherwise.
+func DetectSecurityGroupsDrift(ctx context.Context, client EC2API, infraID string, plan ports.Plan) ([]string, error) {
+	vpcID, err := clusterVPCID(ctx, client, infraID)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []string{}
+
+	for _, rule := range securityGroupRules(infraID, plan) {
+		output, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+			Filters: []types.Filter{
+				{Name: ptr.To("vpc-id"), Values: []string{vpcID}},
+				{Name: ptr.To("tag:Name"), Values: []string{rule.Name}},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing the security group %q: %w", rule.Name, err)
+		}
+
+		if len(output.SecurityGroups) == 0 {
+			drifts = append(drifts, rule.Deleted())
+			continue
+		}
+
+		drifts = append(drifts, rule.Drift(openedRanges(output.SecurityGroups[0].IpPermissions))...)
+	}
+
+	return drifts, nil
+}
+
+// clusterVPCID returns the ID of the VPC created by the installer for the cluster with the given infra ID.
+func clusterVPCID(ctx context.Context, client EC2API, infraID string) (string, error) {
+	output, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		Filters: []types.Filter{
+			{Name: ptr.To("tag:Name"), Values: []string{infraID + "-vpc"}},
+			{Name: ptr.To("tag:kubernetes.io/cluster/" + infraID), Values: []string{"owned"}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error describing the VPC of the cluster %q: %w", infraID, err)
+	}
+
+	if len(output.Vpcs) == 0 {
+		return "", fmt.Errorf("the VPC of the cluster %q wasn't found", infraID)
+	}
+
+	return ptr.Deref(output.Vpcs[0].VpcId, ""), nil
+}
+
+func openedRanges(permissions []types.IpPermission) []ports.Range {
+	opened := make([]ports.Range, len(permissions))
+
+	for i := range permissions {
+		opened[i].Protocol = ptr.Deref(permissions[i].IpProtocol, "")
+		if opened[i].Protocol == "-1" {
+			opened[i].Protocol = ports.ProtocolAny
+		}
+
+		from, to := ptr.Deref(permissions[i].FromPort, 0), ptr.Deref(permissions[i].ToPort, 0)
+		if from > 0 && to > 0 {
+			opened[i].From, opened[i].To = uint16(from), uint16(to)
+		}
+	}
+
+	return opened
+}
@@ -0,0 +1,234 @@
+package aws_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/aws"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	infraID    = "test-infra"
+	clusterVPC = "vpc-cluster"
+)
+
+// fakeEC2 describes the VPCs and the security groups matching the filters of the requests. The other requests fail with
+// the error configured for their action, if any.
+type fakeEC2 struct {
+	vpcs           []types.Vpc
+	securityGroups []types.SecurityGroup
+	describeErr    error
+	actionErrs     map[string]error
+}
+
+func (f *fakeEC2) DescribeVpcs(_ context.Context, params *ec2.DescribeVpcsInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeVpcsOutput, error) {
+	output := &ec2.DescribeVpcsOutput{}
+
+	for _, vpc := range f.vpcs {
+		if matchesFilters(params.Filters, vpc.VpcId, vpc.Tags) {
+			output.Vpcs = append(output.Vpcs, vpc)
+		}
+	}
+
+	return output, nil
+}
+
+func (f *fakeEC2) DescribeSecurityGroups(_ context.Context, params *ec2.DescribeSecurityGroupsInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeSecurityGroupsOutput, error) {
+	if f.describeErr != nil {
+		return nil, f.describeErr
+	}
+
+	output := &ec2.DescribeSecurityGroupsOutput{}
+
+	for _, group := range f.securityGroups {
+		if matchesFilters(params.Filters, group.VpcId, group.Tags) {
+			output.SecurityGroups = append(output.SecurityGroups, group)
+		}
+	}
+
+	return output, nil
+}
+
+func (f *fakeEC2) DescribeInstances(_ context.Context, _ *ec2.DescribeInstancesInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeInstancesOutput, error) {
+	return &ec2.DescribeInstancesOutput{}, f.actionErrs["ec2:DescribeInstances"]
+}
+
+func (f *fakeEC2) DescribeSubnets(_ context.Context, _ *ec2.DescribeSubnetsInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{}, f.actionErrs["ec2:DescribeSubnets"]
+}
+
+func (f *fakeEC2) CreateSecurityGroup(_ context.Context, _ *ec2.CreateSecurityGroupInput,
+	_ ...func(*ec2.Options),
+) (*ec2.CreateSecurityGroupOutput, error) {
+	return &ec2.CreateSecurityGroupOutput{}, f.actionErrs["ec2:CreateSecurityGroup"]
+}
+
+func (f *fakeEC2) AuthorizeSecurityGroupIngress(_ context.Context, _ *ec2.AuthorizeSecurityGroupIngressInput,
+	_ ...func(*ec2.Options),
+) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	return &ec2.AuthorizeSecurityGroupIngressOutput{}, f.actionErrs["ec2:AuthorizeSecurityGroupIngress"]
+}
+
+func (f *fakeEC2) CreateTags(_ context.Context, _ *ec2.CreateTagsInput,
+	_ ...func(*ec2.Options),
+) (*ec2.CreateTagsOutput, error) {
+	return &ec2.CreateTagsOutput{}, f.actionErrs["ec2:CreateTags"]
+}
+
+var _ = Describe("DetectSecurityGroupsDrift", func() {
+	var (
+		client *fakeEC2
+		plan   ports.Plan
+	)
+
+	BeforeEach(func() {
+		client = &fakeEC2{
+			vpcs: []types.Vpc{{
+				VpcId: ptr.To(clusterVPC),
+				Tags:  []types.Tag{newTag("Name", infraID+"-vpc"), newTag("kubernetes.io/cluster/"+infraID, "owned")},
+			}},
+			// The installer only names the worker and master security groups in their Name tag.
+			securityGroups: []types.SecurityGroup{
+				newSecurityGroup(clusterVPC, infraID+"-submariner-gw-sg", infraID+"-submariner-gw-sg", openedPort("udp", 4500)),
+				newSecurityGroup(clusterVPC, "terraform-00000001", infraID+"-worker-sg", openedPort("udp", 4800)),
+				newSecurityGroup(clusterVPC, "terraform-00000002", infraID+"-master-sg", openedPort("udp", 4800)),
+			},
+		}
+
+		plan = ports.Plan{
+			Public:   []cpapi.PortSpec{{Port: 4500, Protocol: "udp"}},
+			Internal: []cpapi.PortSpec{{Port: 4800, Protocol: "udp"}},
+		}
+	})
+
+	When("the security groups open the Submariner ports", func() {
+		It("should not report any drift", func() {
+			Expect(aws.DetectSecurityGroupsDrift(context.TODO(), client, infraID, plan)).To(BeEmpty())
+		})
+	})
+
+	When("a security group was deleted", func() {
+		BeforeEach(func() {
+			client.securityGroups = client.securityGroups[1:]
+		})
+
+		It("should report it", func() {
+			Expect(aws.DetectSecurityGroupsDrift(context.TODO(), client, infraID, plan)).To(Equal([]string{
+				`the SecurityGroup "test-infra-submariner-gw-sg" was deleted`,
+			}))
+		})
+	})
+
+	When("a rule of a security group was deleted", func() {
+		BeforeEach(func() {
+			client.securityGroups[1].IpPermissions = nil
+		})
+
+		It("should report the ports which aren't opened anymore", func() {
+			Expect(aws.DetectSecurityGroupsDrift(context.TODO(), client, infraID, plan)).To(Equal([]string{
+				`the SecurityGroupRule "test-infra-worker-sg" doesn't open 4800/udp anymore`,
+			}))
+		})
+	})
+
+	When("a security group with the same name exists in another VPC", func() {
+		BeforeEach(func() {
+			client.securityGroups[2].IpPermissions = nil
+			client.securityGroups = append([]types.SecurityGroup{
+				newSecurityGroup("vpc-other", infraID+"-master-sg", infraID+"-master-sg", openedPort("udp", 4800)),
+			}, client.securityGroups...)
+		})
+
+		It("should only verify the security group of the cluster VPC", func() {
+			Expect(aws.DetectSecurityGroupsDrift(context.TODO(), client, infraID, plan)).To(Equal([]string{
+				`the SecurityGroupRule "test-infra-master-sg" doesn't open 4800/udp anymore`,
+			}))
+		})
+	})
+
+	When("the VPC of the cluster isn't found", func() {
+		BeforeEach(func() {
+			client.vpcs = nil
+		})
+
+		It("should return an error", func() {
+			_, err := aws.DetectSecurityGroupsDrift(context.TODO(), client, infraID, plan)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("describing the security groups fails", func() {
+		BeforeEach(func() {
+			client.describeErr = errors.New("fake error")
+		})
+
+		It("should return an error", func() {
+			_, err := aws.DetectSecurityGroupsDrift(context.TODO(), client, infraID, plan)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func matchesFilters(filters []types.Filter, vpcID *string, tags []types.Tag) bool {
+	for _, filter := range filters {
+		var value string
+
+		switch name := ptr.Deref(filter.Name, ""); {
+		case name == "vpc-id":
+			value = ptr.Deref(vpcID, "")
+		case strings.HasPrefix(name, "tag:"):
+			for _, tag := range tags {
+				if ptr.Deref(tag.Key, "") == strings.TrimPrefix(name, "tag:") {
+					value = ptr.Deref(tag.Value, "")
+				}
+			}
+		default:
+			Fail("unexpected filter " + name)
+		}
+
+		found := false
+
+		for _, v := range filter.Values {
+			found = found || v == value
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newTag(key, value string) types.Tag {
+	return types.Tag{Key: ptr.To(key), Value: ptr.To(value)}
+}
+
+func newSecurityGroup(vpcID, groupName, name string, permissions ...types.IpPermission) types.SecurityGroup {
+	return types.SecurityGroup{
+		VpcId:         ptr.To(vpcID),
+		GroupName:     ptr.To(groupName),
+		Tags:          []types.Tag{newTag("Name", name)},
+		IpPermissions: permissions,
+	}
+}
+
+func openedPort(protocol string, port int32) types.IpPermission {
+	return types.IpPermission{IpProtocol: ptr.To(protocol), FromPort: ptr.To(port), ToPort: ptr.To(port)}
+}
@@ -0,0 +1,128 @@
+package aws
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// EC2API is the subset of the EC2 API used to check the permissions of the AWS credentials, with dry runs, and to detect the
+// drift of the security groups.
+type EC2API interface {
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	CreateSecurityGroup(ctx context.Context, params *ec2.CreateSecurityGroupInput,
+		optFns ...func(*ec2.Options)) (*ec2.CreateSecurityGroupOutput, error)
+	AuthorizeSecurityGroupIngress(ctx context.Context, params *ec2.AuthorizeSecurityGroupIngressInput,
+		optFns ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput,
+		optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+}
+
+// CheckPermissions checks with dry runs that the AWS credentials can describe the instances, create and modify the security groups
+// of the cluster, and that the MachineSets of the gateway nodes can be created.
+func (a *awsProvider) CheckPermissions(ctx context.Context) ([]string, error) {
+	missing, err := CheckEC2Permissions(ctx, a.ec2Client, a.infraID, a.nattPort)
+	if err != nil {
+		return nil, err
+	}
+
+	machineSetMissing, err := provider.CheckMachineSetPermissions(ctx, a.kubeClient)
+
+	return append(missing, machineSetMissing...), err
+}
+
+// CheckEC2Permissions returns the EC2 actions missing from the given client to prepare the cluster with the given infra ID,
+// checked with dry runs. The actions on the security groups are only checked if the cluster has a security group.
+func CheckEC2Permissions(ctx context.Context, client EC2API, infraID string, nattPort int64) ([]string, error) {
+	missing := []string{}
+
+	check := func(action string, err error) error {
+		switch {
+		case err == nil || isAPIError(err, "DryRunOperation"):
+		case isAPIError(err, "UnauthorizedOperation"):
+			missing = append(missing, action)
+		case provider.IsInvalidCredentials(err):
+			return err
+		default:
+			// The dry run failed for another reason, such as an invalid parameter, which doesn't tell whether the action is allowed.
+			klog.Warningf("Unable to check the permission for %s: %v", action, err)
+		}
+
+		return nil
+	}
+
+	_, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{DryRun: ptr.To(true)})
+	if err := check("ec2:DescribeInstances", err); err != nil {
+		return nil, err
+	}
+
+	_, err = client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{DryRun: ptr.To(true)})
+	if err := check("ec2:DescribeSubnets", err); err != nil {
+		return nil, err
+	}
+
+	groups, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []types.Filter{{Name: ptr.To("tag:kubernetes.io/cluster/" + infraID), Values: []string{"owned"}}},
+	})
+	if err := check("ec2:DescribeSecurityGroups", err); err != nil {
+		return nil, err
+	}
+
+	if groups != nil && len(groups.SecurityGroups) > 0 {
+		group := groups.SecurityGroups[0]
+
+		_, err = client.CreateSecurityGroup(ctx, &ec2.CreateSecurityGroupInput{
+			DryRun:      ptr.To(true),
+			GroupName:   ptr.To(infraID + "-submariner-gw-sg"),
+			Description: ptr.To("Submariner Gateway"),
+			VpcId:       group.VpcId,
+		})
+		if err := check("ec2:CreateSecurityGroup", err); err != nil {
+			return nil, err
+		}
+
+		_, err = client.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			DryRun:  ptr.To(true),
+			GroupId: group.GroupId,
+			IpPermissions: []types.IpPermission{{
+				IpProtocol: ptr.To("udp"),
+				FromPort:   ptr.To(int32(nattPort)),
+				ToPort:     ptr.To(int32(nattPort)),
+				IpRanges:   []types.IpRange{{CidrIp: ptr.To("0.0.0.0/0")}},
+			}},
+		})
+		if err := check("ec2:AuthorizeSecurityGroupIngress", err); err != nil {
+			return nil, err
+		}
+
+		_, err = client.CreateTags(ctx, &ec2.CreateTagsInput{
+			DryRun:    ptr.To(true),
+			Resources: []string{ptr.Deref(group.GroupId, "")},
+			Tags:      []types.Tag{{Key: ptr.To("submariner.io/gateway"), Value: ptr.To("")}},
+		})
+		if err := check("ec2:CreateTags", err); err != nil {
+			return nil, err
+		}
+	}
+
+	return missing, nil
+}
+
+func isAPIError(err error, code string) bool {
+	var apiErr smithy.APIError
+
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
@@ -0,0 +1,95 @@
+package aws_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/aws"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("CheckEC2Permissions", func() {
+	dryRunErr := &smithy.GenericAPIError{Code: "DryRunOperation"}
+	unauthorizedErr := &smithy.GenericAPIError{Code: "UnauthorizedOperation"}
+
+	newClient := func(actionErrs map[string]error) *fakeEC2 {
+		client := &fakeEC2{
+			securityGroups: []types.SecurityGroup{{
+				GroupId: ptr.To("sg-worker"),
+				VpcId:   ptr.To(clusterVPC),
+				Tags:    []types.Tag{newTag("kubernetes.io/cluster/"+infraID, "owned")},
+			}},
+			actionErrs: map[string]error{},
+		}
+
+		for _, action := range []string{
+			"ec2:DescribeInstances", "ec2:DescribeSubnets", "ec2:CreateSecurityGroup", "ec2:AuthorizeSecurityGroupIngress",
+			"ec2:CreateTags",
+		} {
+			client.actionErrs[action] = dryRunErr
+		}
+
+		for action, err := range actionErrs {
+			client.actionErrs[action] = err
+		}
+
+		return client
+	}
+
+	DescribeTable("the dry runs",
+		func(actionErrs map[string]error, expected []string) {
+			missing, err := aws.CheckEC2Permissions(context.TODO(), newClient(actionErrs), infraID, 4500)
+			Expect(err).To(Succeed())
+			Expect(missing).To(Equal(expected))
+		},
+		Entry("succeeding", nil, []string{}),
+		Entry("unauthorized", map[string]error{
+			"ec2:DescribeSubnets": unauthorizedErr,
+			"ec2:CreateTags":      unauthorizedErr,
+		}, []string{"ec2:DescribeSubnets", "ec2:CreateTags"}),
+		Entry("failing with another error", map[string]error{
+			"ec2:AuthorizeSecurityGroupIngress": &smithy.GenericAPIError{Code: "InvalidParameterValue"},
+		}, []string{}),
+		Entry("without a dry run error", map[string]error{"ec2:DescribeInstances": nil}, []string{}),
+	)
+
+	When("the cluster has no security group", func() {
+		It("should only check the describe actions", func() {
+			client := newClient(map[string]error{"ec2:CreateSecurityGroup": unauthorizedErr})
+			client.securityGroups = nil
+
+			Expect(aws.CheckEC2Permissions(context.TODO(), client, infraID, 4500)).To(BeEmpty())
+		})
+	})
+
+	When("describing the security groups is unauthorized", func() {
+		It("should return the missing action", func() {
+			client := newClient(nil)
+			client.describeErr = unauthorizedErr
+
+			Expect(aws.CheckEC2Permissions(context.TODO(), client, infraID, 4500)).To(Equal([]string{"ec2:DescribeSecurityGroups"}))
+		})
+	})
+
+	When("the credentials are invalid", func() {
+		It("should return an invalid credentials error", func() {
+			_, err := aws.CheckEC2Permissions(context.TODO(), newClient(map[string]error{
+				"ec2:DescribeInstances": &smithy.GenericAPIError{Code: "AuthFailure"},
+			}), infraID, 4500)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+
+	When("a dry run fails with an error which isn't an API error", func() {
+		It("should ignore it", func() {
+			Expect(aws.CheckEC2Permissions(context.TODO(), newClient(map[string]error{
+				"ec2:CreateTags": errors.New("fake error"),
+			}), infraID, 4500)).To(BeEmpty())
+		})
+	})
+})
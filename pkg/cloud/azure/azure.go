@@ -1,40 +1,40 @@
 package azure
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/pkg/errors"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
 	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/cloud/reporter"
-	"github.com/stolostron/submariner-addon/pkg/constants"
 	submreporter "github.com/submariner-io/admiral/pkg/reporter"
 	"github.com/submariner-io/cloud-prepare/pkg/api"
 	"github.com/submariner-io/cloud-prepare/pkg/azure"
 	"github.com/submariner-io/cloud-prepare/pkg/k8s"
 	"github.com/submariner-io/cloud-prepare/pkg/ocp"
-	"github.com/submariner-io/submariner/pkg/cni"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 )
 
-const (
-	servicePrincipalJSON = "osServicePrincipal.json"
-	gwInstanceType       = "Standard_F4s_v2"
-)
+const gwInstanceType = "Standard_F4s_v2"
 
 type azureProvider struct {
-	infraID           string
-	cniType           string
-	cloudPrepare      api.Cloud
-	reporter          submreporter.Interface
-	gwDeployer        api.GatewayDeployer
-	nattDiscoveryPort int64
-	gateways          int
-	nattPort          uint16
-	airGapped         bool
+	ports          ports.Plan
+	infraID        string
+	cloudPrepare   api.Cloud
+	reporter       submreporter.Interface
+	gwDeployer     api.GatewayDeployer
+	gateways       int
+	instanceType   string
+	airGapped      bool
+	credentials    azcore.TokenCredential
+	subscriptionID string
+	resourceGroup  string
+	kubeClient     kubernetes.Interface
+	dynamicClient  dynamic.Interface
 }
 
 //nolint:revive // Ignore unexported-return - we can't reference the Provider interface here.
@@ -52,14 +52,9 @@ func NewProvider(info *provider.Info) (*azureProvider, error) {
 		return nil, fmt.Errorf("the count of gateways is less than 1")
 	}
 
-	subscriptionID, err := initializeFromAuthFile(info.CredentialsSecret)
+	credentials, subscriptionID, err := NewCredential(info.CredentialsSecret, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to initialize from auth file")
-	}
-
-	credentials, err := azidentity.NewEnvironmentCredential(nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to create the Azure credentials")
+		return nil, err
 	}
 
 	k8sClient := k8s.NewInterface(info.KubeClient)
@@ -85,43 +80,40 @@ func NewProvider(info *provider.Info) (*azureProvider, error) {
 	cloudPrepare := azure.NewCloud(&cloudInfo)
 
 	return &azureProvider{
-		infraID:           info.InfraID,
-		nattPort:          uint16(info.IPSecNATTPort),
-		cniType:           info.NetworkType,
-		cloudPrepare:      cloudPrepare,
-		gwDeployer:        gwDeployer,
-		reporter:          reporter.NewEventRecorderWrapper("AzureCloudProvider", info.EventRecorder),
-		nattDiscoveryPort: int64(info.NATTDiscoveryPort),
-		gateways:          info.Gateways,
-		airGapped:         info.SubmarinerConfigSpec.AirGappedDeployment,
+		ports:          ports.For(&info.SubmarinerConfigSpec, info.NetworkType),
+		infraID:        info.InfraID,
+		cloudPrepare:   cloudPrepare,
+		gwDeployer:     gwDeployer,
+		reporter:       reporter.NewEventRecorderWrapper("AzureCloudProvider", info.EventRecorder, info.StepLog),
+		gateways:       info.Gateways,
+		instanceType:   instanceType,
+		airGapped:      info.SubmarinerConfigSpec.AirGappedDeployment,
+		credentials:    credentials,
+		subscriptionID: subscriptionID,
+		resourceGroup:  cloudInfo.BaseGroupName,
+		kubeClient:     info.KubeClient,
+		dynamicClient:  info.DynamicClient,
 	}, nil
 }
 
 // PrepareSubmarinerClusterEnv prepares submariner cluster environment on Azure
 // The below tasks will be executed
-// 1. create the inbound and outbound firewall rules for submariner, below ports will be opened
-//   - NAT traversal port (by default 4500/UDP)
-//   - 4800/UDP port to encapsulate Pod traffic from worker and master nodes to the Submariner Gateway nodes
-//   - ESP & AH protocols for private-ip to private-ip gateway communications
-func (r *azureProvider) PrepareSubmarinerClusterEnv() error {
-	// TODO For ovn the port 4800 need not be opened.
+// 1. create the inbound and outbound firewall rules for submariner, the ports planned for the cable driver will be opened
+func (r *azureProvider) PrepareSubmarinerClusterEnv(ctx context.Context) error {
 	if err := r.gwDeployer.Deploy(api.GatewayDeployInput{
-		PublicPorts: []api.PortSpec{
-			{Port: r.nattPort, Protocol: "udp"},
-			{Port: uint16(r.nattDiscoveryPort), Protocol: "udp"},
-			{Port: 0, Protocol: "esp"},
-			{Port: 0, Protocol: "ah"},
-		},
-		Gateways:  r.gateways,
-		AirGapped: r.airGapped,
+		PublicPorts: r.ports.Public,
+		Gateways:    r.gateways,
+		AirGapped:   r.airGapped,
 	}, r.reporter); err != nil {
 		return err
 	}
 
-	if !strings.EqualFold(r.cniType, cni.OVNKubernetes) {
-		if err := r.cloudPrepare.OpenPorts([]api.PortSpec{
-			{Port: constants.SubmarinerRoutePort, Protocol: "udp"},
-		}, r.reporter); err != nil {
+	if len(r.ports.Internal) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := r.cloudPrepare.OpenPorts(r.ports.Internal, r.reporter); err != nil {
 			return err
 		}
 	}
@@ -131,56 +123,55 @@ func (r *azureProvider) PrepareSubmarinerClusterEnv() error {
 	return nil
 }
 
-// CleanUpSubmarinerClusterEnv clean up submariner cluster environment on Azure after the SubmarinerConfig was deleted
-// 1. delete any dedicated gateways that were previously deployed.
-// 2. delete the inbound and outbound firewall rules to close submariner ports.
-func (r *azureProvider) CleanUpSubmarinerClusterEnv() error {
-	err := r.gwDeployer.Cleanup(r.reporter)
-	if err != nil {
-		return err
+// Plan returns the changes PrepareSubmarinerClusterEnv would make on Azure: the gateway network security group opening the
+// public ports, the rules opening the internal ports in the network security group of the cluster, and the gateway
+// MachineSets.
+func (r *azureProvider) Plan(ctx context.Context) ([]configv1alpha1.PlannedChange, error) {
+	changes := []configv1alpha1.PlannedChange{}
+
+	for _, rule := range r.rules() {
+		changes = append(changes, ports.Change(rule.Kind, rule.Name, rule.Ports))
 	}
 
-	err = r.cloudPrepare.ClosePorts(r.reporter)
+	machineSets, err := provider.PlanGatewayMachineSets(ctx, r.dynamicClient, r.infraID, r.gateways, r.instanceType)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	r.reporter.Success("The Submariner cluster environment has been cleaned up on Azure")
-
-	return nil
+	return append(changes, machineSets...), nil
 }
 
-func initializeFromAuthFile(credentialsSecret *corev1.Secret) (string, error) {
-	servicePrincipalJSON, ok := credentialsSecret.Data[servicePrincipalJSON]
+// rules returns the gateway network security group opening the public ports, and the network security group of the cluster
+// in which the rules opening the internal ports are added.
+func (r *azureProvider) rules() []ports.Rule {
+	rules := []ports.Rule{{Kind: "NetworkSecurityGroup", Name: r.infraID + "-submariner-nsg", Ports: r.ports.Public}}
 
-	//nolint:revive,stylecheck // Ignore var-naming: struct field ClientId should be ClientID et al.
-	var authInfo struct {
-		ClientId       string
-		ClientSecret   string
-		SubscriptionId string
-		TenantId       string
+	if len(r.ports.Internal) > 0 {
+		rules = append(rules, ports.Rule{Kind: "SecurityRule", Name: r.infraID + "-nsg", Ports: r.ports.Internal})
 	}
 
-	if !ok {
-		return "", errors.New("servicePrincipalJSON is not found in the credentials")
-	}
+	return rules
+}
 
-	err := json.Unmarshal(servicePrincipalJSON, &authInfo)
+// CleanUpSubmarinerClusterEnv clean up submariner cluster environment on Azure after the SubmarinerConfig was deleted
+// 1. delete any dedicated gateways that were previously deployed.
+// 2. delete the inbound and outbound firewall rules to close submariner ports.
+func (r *azureProvider) CleanUpSubmarinerClusterEnv(ctx context.Context) error {
+	err := r.gwDeployer.Cleanup(r.reporter)
 	if err != nil {
-		return "", errors.Wrap(err, "error unmarshalling servicePrincipalJSON")
+		return err
 	}
 
-	if err := os.Setenv("AZURE_CLIENT_ID", authInfo.ClientId); err != nil {
-		return "", err
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	if err := os.Setenv("AZURE_CLIENT_SECRET", authInfo.ClientSecret); err != nil {
-		return "", err
+	err = r.cloudPrepare.ClosePorts(r.reporter)
+	if err != nil {
+		return err
 	}
 
-	if err := os.Setenv("AZURE_TENANT_ID", authInfo.TenantId); err != nil {
-		return "", err
-	}
+	r.reporter.Success("The Submariner cluster environment has been cleaned up on Azure")
 
-	return authInfo.SubscriptionId, nil
+	return nil
 }
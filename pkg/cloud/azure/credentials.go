@@ -0,0 +1,103 @@
+package azure
+
+import (
+	"encoding/json"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ServicePrincipalJSON is the key of the credentials secret holding the Azure service principal.
+	ServicePrincipalJSON = "osServicePrincipal.json"
+
+	// defaultFederatedTokenFile is the path of the ServiceAccount token projected in the pod of the agent with the
+	// api://AzureADTokenExchange audience.
+	defaultFederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token"
+)
+
+// CredentialOptions configures the Azure credentials created from a service principal.
+type CredentialOptions struct {
+	azcore.ClientOptions
+
+	// DisableInstanceDiscovery disables the request to Microsoft Entra ID to validate the authority, for private clouds.
+	DisableInstanceDiscovery bool
+}
+
+// NewCredential returns the Azure credential of the service principal in the given credentials secret, and its subscription ID.
+// The credential is built from the content of the secret only, the environment of the process isn't read nor modified:
+//   - with a clientSecret, a client secret credential is returned;
+//   - with a federatedTokenFile, a workload identity credential is returned, which exchanges the token in the file;
+//   - with managedIdentity set to true, a managed identity credential is returned, for the clientId if set;
+//   - with only a clientId and a tenantId, a workload identity credential is returned, which exchanges the ServiceAccount
+//     token projected in the pod of the agent.
+func NewCredential(credentialsSecret *corev1.Secret, options *CredentialOptions) (azcore.TokenCredential, string, error) {
+	principalJSON, ok := credentialsSecret.Data[ServicePrincipalJSON]
+	if !ok {
+		return nil, "", errors.WithMessage(provider.ErrInvalidCredentials, "servicePrincipalJSON is not found in the credentials")
+	}
+
+	//nolint:revive,stylecheck // Ignore var-naming: struct field ClientId should be ClientID et al.
+	var authInfo struct {
+		ClientId           string
+		ClientSecret       string
+		SubscriptionId     string
+		TenantId           string
+		FederatedTokenFile string
+		ManagedIdentity    bool
+	}
+
+	if err := json.Unmarshal(principalJSON, &authInfo); err != nil {
+		return nil, "", errors.Wrap(err, "error unmarshalling servicePrincipalJSON")
+	}
+
+	if options == nil {
+		options = &CredentialOptions{}
+	}
+
+	var (
+		credential azcore.TokenCredential
+		err        error
+	)
+
+	switch {
+	case authInfo.ClientSecret != "":
+		credential, err = azidentity.NewClientSecretCredential(authInfo.TenantId, authInfo.ClientId, authInfo.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{
+				ClientOptions:            options.ClientOptions,
+				DisableInstanceDiscovery: options.DisableInstanceDiscovery,
+			})
+	case authInfo.ManagedIdentity && authInfo.FederatedTokenFile == "":
+		managedIdentityOptions := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: options.ClientOptions}
+		if authInfo.ClientId != "" {
+			managedIdentityOptions.ID = azidentity.ClientID(authInfo.ClientId)
+		}
+
+		credential, err = azidentity.NewManagedIdentityCredential(managedIdentityOptions)
+	case authInfo.FederatedTokenFile != "" || (authInfo.ClientId != "" && authInfo.TenantId != ""):
+		tokenFile := authInfo.FederatedTokenFile
+		if tokenFile == "" {
+			tokenFile = defaultFederatedTokenFile
+		}
+
+		credential, err = azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions:            options.ClientOptions,
+			ClientID:                 authInfo.ClientId,
+			TenantID:                 authInfo.TenantId,
+			TokenFilePath:            tokenFile,
+			DisableInstanceDiscovery: options.DisableInstanceDiscovery,
+		})
+	default:
+		return nil, "", errors.WithMessage(provider.ErrInvalidCredentials,
+			"servicePrincipalJSON contains neither a clientSecret, a clientId and a tenantId nor managedIdentity")
+	}
+
+	if err != nil {
+		return nil, "", errors.Wrap(err, "unable to create the Azure credential")
+	}
+
+	return credential, authInfo.SubscriptionId, nil
+}
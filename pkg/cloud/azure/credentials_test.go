@@ -0,0 +1,182 @@
+package azure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/azure"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// newFakeAuthority returns a stubbed Microsoft Entra authority which issues the access token "<tenant>/<client ID>" to the
+// clients presenting the secret "<client ID>-secret".
+func newFakeAuthority() *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/{tenant}/v2.0/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		base := "https://" + r.Host + "/" + r.PathValue("tenant")
+		Expect(json.NewEncoder(w).Encode(map[string]string{
+			"token_endpoint":         base + "/oauth2/v2.0/token",
+			"authorization_endpoint": base + "/oauth2/v2.0/authorize",
+			"issuer":                 base + "/v2.0",
+		})).To(Succeed())
+	})
+
+	mux.HandleFunc("/{tenant}/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.ParseForm()).To(Succeed())
+
+		if r.PostForm.Get("client_secret") != r.PostForm.Get("client_id")+"-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		Expect(json.NewEncoder(w).Encode(map[string]any{
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"access_token": r.PathValue("tenant") + "/" + r.PostForm.Get("client_id"),
+		})).To(Succeed())
+	})
+
+	return httptest.NewTLSServer(mux)
+}
+
+func newCredentialsSecret(principal string) *corev1.Secret {
+	return &corev1.Secret{Data: map[string][]byte{azure.ServicePrincipalJSON: []byte(principal)}}
+}
+
+var _ = Describe("NewCredential", func() {
+	var (
+		authority *httptest.Server
+		options   *azure.CredentialOptions
+	)
+
+	BeforeEach(func() {
+		authority = newFakeAuthority()
+		DeferCleanup(authority.Close)
+
+		options = &azure.CredentialOptions{
+			ClientOptions: azcore.ClientOptions{
+				Cloud:     cloud.Configuration{ActiveDirectoryAuthorityHost: authority.URL + "/"},
+				Transport: authority.Client(),
+			},
+			DisableInstanceDiscovery: true,
+		}
+	})
+
+	getToken := func(credential azcore.TokenCredential) string {
+		token, err := credential.GetToken(context.TODO(), policy.TokenRequestOptions{
+			Scopes: []string{"https://management.azure.com/.default"},
+		})
+		Expect(err).To(Succeed())
+
+		return token.Token
+	}
+
+	When("the service principal has a client secret", func() {
+		It("should return a client secret credential and the subscription ID", func() {
+			credential, subscriptionID, err := azure.NewCredential(newCredentialsSecret(
+				`{"clientId": "client", "clientSecret": "client-secret", "tenantId": "tenant", "subscriptionId": "subscription"}`),
+				options)
+			Expect(err).To(Succeed())
+			Expect(subscriptionID).To(Equal("subscription"))
+			Expect(getToken(credential)).To(Equal("tenant/client"))
+		})
+
+		It("should not set the Azure environment variables", func() {
+			_, _, err := azure.NewCredential(newCredentialsSecret(
+				`{"clientId": "client", "clientSecret": "client-secret", "tenantId": "tenant", "subscriptionId": "subscription"}`),
+				options)
+			Expect(err).To(Succeed())
+
+			for _, name := range []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_TENANT_ID"} {
+				Expect(os.Getenv(name)).To(BeEmpty(), name)
+			}
+		})
+	})
+
+	When("the service principals of two clusters are in different tenants", func() {
+		It("should use each one concurrently with its own tenant", func() {
+			credentialEast, _, err := azure.NewCredential(newCredentialsSecret(
+				`{"clientId": "east", "clientSecret": "east-secret", "tenantId": "tenant-a", "subscriptionId": "a"}`), options)
+			Expect(err).To(Succeed())
+
+			credentialWest, _, err := azure.NewCredential(newCredentialsSecret(
+				`{"clientId": "west", "clientSecret": "west-secret", "tenantId": "tenant-b", "subscriptionId": "b"}`), options)
+			Expect(err).To(Succeed())
+
+			var (
+				wg     sync.WaitGroup
+				tokens sync.Map
+			)
+
+			for name, credential := range map[string]azcore.TokenCredential{"east": credentialEast, "west": credentialWest} {
+				wg.Add(1)
+
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					tokens.Store(name, getToken(credential))
+				}()
+			}
+
+			wg.Wait()
+
+			tokenEast, _ := tokens.Load("east")
+			Expect(tokenEast).To(Equal("tenant-a/east"))
+
+			tokenWest, _ := tokens.Load("west")
+			Expect(tokenWest).To(Equal("tenant-b/west"))
+		})
+	})
+
+	When("the service principal has a federated token file", func() {
+		It("should return a workload identity credential", func() {
+			tokenFile := GinkgoT().TempDir() + "/token"
+			Expect(os.WriteFile(tokenFile, []byte("federated"), 0o600)).To(Succeed())
+
+			credential, _, err := azure.NewCredential(newCredentialsSecret(
+				`{"clientId": "client", "tenantId": "tenant", "federatedTokenFile": "`+tokenFile+`"}`), options)
+			Expect(err).To(Succeed())
+			Expect(credential).ToNot(BeNil())
+		})
+	})
+
+	When("the service principal only has a client ID and a tenant ID", func() {
+		It("should return a workload identity credential using the token projected in the pod", func() {
+			credential, _, err := azure.NewCredential(newCredentialsSecret(`{"clientId": "client", "tenantId": "tenant"}`), options)
+			Expect(err).To(Succeed())
+
+			_, err = credential.GetToken(context.TODO(), policy.TokenRequestOptions{
+				Scopes: []string{"https://management.azure.com/.default"},
+			})
+			Expect(err).To(MatchError(ContainSubstring("/var/run/secrets/azure/tokens/azure-identity-token")))
+		})
+	})
+
+	When("the service principal has no credentials", func() {
+		It("should return an invalid credentials error", func() {
+			_, _, err := azure.NewCredential(newCredentialsSecret(`{"subscriptionId": "subscription"}`), options)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+
+	When("the secret doesn't contain a service principal", func() {
+		It("should return an invalid credentials error", func() {
+			_, _, err := azure.NewCredential(&corev1.Secret{}, options)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+			Expect(strings.Contains(err.Error(), "servicePrincipalJSON")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"k8s.io/utils/ptr"
+)
+
+// DetectDrift verifies that the network security groups still open the Submariner ports, and that the gateway MachineSets
+// still deploy the gateway nodes.
+func (r *azureProvider) DetectDrift(ctx context.Context) ([]string, error) {
+	drifts := []string{}
+
+	client, err := armnetwork.NewSecurityGroupsClient(r.subscriptionID, r.credentials, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the security groups client")
+	}
+
+	for _, rule := range r.rules() {
+		group, err := client.Get(ctx, r.resourceGroup, rule.Name, nil)
+
+		var respErr *azcore.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			drifts = append(drifts, rule.Deleted())
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "error retrieving the network security group %q", rule.Name)
+		}
+
+		opened, err := openedRanges(&group.SecurityGroup)
+		if err != nil {
+			return nil, err
+		}
+
+		drifts = append(drifts, rule.Drift(opened)...)
+	}
+
+	machineSetDrifts, err := provider.DetectGatewayMachineSetsDrift(ctx, r.dynamicClient, r.infraID, r.gateways)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(drifts, machineSetDrifts...), nil
+}
+
+func openedRanges(group *armnetwork.SecurityGroup) ([]ports.Range, error) {
+	opened := []ports.Range{}
+
+	if group.Properties == nil {
+		return opened, nil
+	}
+
+	for _, securityRule := range group.Properties.SecurityRules {
+		properties := securityRule.Properties
+		if properties == nil || ptr.Deref(properties.Direction, "") != armnetwork.SecurityRuleDirectionInbound ||
+			ptr.Deref(properties.Access, "") != armnetwork.SecurityRuleAccessAllow {
+			continue
+		}
+
+		protocol := strings.ToLower(string(ptr.Deref(properties.Protocol, armnetwork.SecurityRuleProtocolAsterisk)))
+
+		portRanges := []string{}
+		if properties.DestinationPortRange != nil {
+			portRanges = append(portRanges, *properties.DestinationPortRange)
+		}
+
+		for _, portRange := range properties.DestinationPortRanges {
+			portRanges = append(portRanges, ptr.Deref(portRange, ""))
+		}
+
+		for _, portRange := range portRanges {
+			r, err := ports.ParseRange(protocol, portRange)
+			if err != nil {
+				return nil, err
+			}
+
+			opened = append(opened, r)
+		}
+	}
+
+	return opened, nil
+}
@@ -0,0 +1,140 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+)
+
+const permissionsAPIVersion = "2022-04-01"
+
+// requiredActions are the role actions needed on the resource group of the cluster to open the Submariner ports and to deploy
+// the gateway nodes.
+var requiredActions = []string{
+	"Microsoft.Compute/virtualMachines/read",
+	"Microsoft.Network/networkInterfaces/read",
+	"Microsoft.Network/networkInterfaces/write",
+	"Microsoft.Network/networkSecurityGroups/read",
+	"Microsoft.Network/networkSecurityGroups/write",
+	"Microsoft.Network/networkSecurityGroups/securityRules/read",
+	"Microsoft.Network/networkSecurityGroups/securityRules/write",
+	"Microsoft.Network/networkSecurityGroups/securityRules/delete",
+	"Microsoft.Network/publicIPAddresses/read",
+	"Microsoft.Network/publicIPAddresses/write",
+}
+
+type permission struct {
+	Actions    []string `json:"actions"`
+	NotActions []string `json:"notActions"`
+}
+
+type permissionsPage struct {
+	Value    []permission `json:"value"`
+	NextLink string       `json:"nextLink"`
+}
+
+// CheckPermissions checks that the role assignments of the Azure credentials on the resource group of the cluster allow to
+// describe the virtual machines and to manage the network security groups, and that the MachineSets of the gateway nodes can be
+// created.
+func (r *azureProvider) CheckPermissions(ctx context.Context) ([]string, error) {
+	missing, err := CheckRoleActions(ctx, r.credentials, nil, r.subscriptionID, r.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	machineSetMissing, err := provider.CheckMachineSetPermissions(ctx, r.kubeClient)
+
+	return append(missing, machineSetMissing...), err
+}
+
+// CheckRoleActions returns the role actions needed to prepare the cluster which the permissions of the given credential on the
+// given resource group don't allow.
+func CheckRoleActions(ctx context.Context, credential azcore.TokenCredential, options *arm.ClientOptions, subscriptionID,
+	resourceGroup string,
+) ([]string, error) {
+	permissions, err := listPermissions(ctx, credential, options, subscriptionID, resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+
+	for _, action := range requiredActions {
+		if !isActionAllowed(action, permissions) {
+			missing = append(missing, action)
+		}
+	}
+
+	return missing, nil
+}
+
+func listPermissions(ctx context.Context, credential azcore.TokenCredential, options *arm.ClientOptions, subscriptionID,
+	resourceGroup string,
+) ([]permission, error) {
+	client, err := arm.NewClient("submariner-addon", "v1.0.0", credential, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the Azure Resource Manager client")
+	}
+
+	endpoint := runtime.JoinPaths(client.Endpoint(), "subscriptions", subscriptionID, "resourceGroups", resourceGroup,
+		"providers/Microsoft.Authorization/permissions") + "?api-version=" + permissionsAPIVersion
+
+	permissions := []permission{}
+
+	for endpoint != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the Azure permissions request")
+		}
+
+		resp, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing the Azure permissions on resource group %q", resourceGroup)
+		}
+
+		if !runtime.HasStatusCode(resp, http.StatusOK) {
+			return nil, errors.Wrapf(runtime.NewResponseError(resp), "error listing the Azure permissions on resource group %q",
+				resourceGroup)
+		}
+
+		page := permissionsPage{}
+		if err := runtime.UnmarshalAsJSON(resp, &page); err != nil {
+			return nil, errors.Wrap(err, "error decoding the Azure permissions")
+		}
+
+		permissions = append(permissions, page.Value...)
+		endpoint = page.NextLink
+	}
+
+	return permissions, nil
+}
+
+// isActionAllowed returns whether one of the given permissions allows the action, the actions and not actions of a permission
+// possibly containing wildcards.
+func isActionAllowed(action string, permissions []permission) bool {
+	for i := range permissions {
+		if matchesAny(action, permissions[i].Actions) && !matchesAny(action, permissions[i].NotActions) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAny(action string, patterns []string) bool {
+	for _, pattern := range patterns {
+		expr := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if matched, err := regexp.MatchString(expr, action); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+package azure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/azure"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+)
+
+const (
+	subscriptionID = "subscription"
+	resourceGroup  = "test-infra-rg"
+)
+
+var allActions = []string{
+	"Microsoft.Compute/virtualMachines/read",
+	"Microsoft.Network/networkInterfaces/read",
+	"Microsoft.Network/networkInterfaces/write",
+	"Microsoft.Network/networkSecurityGroups/read",
+	"Microsoft.Network/networkSecurityGroups/write",
+	"Microsoft.Network/networkSecurityGroups/securityRules/read",
+	"Microsoft.Network/networkSecurityGroups/securityRules/write",
+	"Microsoft.Network/networkSecurityGroups/securityRules/delete",
+	"Microsoft.Network/publicIPAddresses/read",
+	"Microsoft.Network/publicIPAddresses/write",
+}
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+type permission struct {
+	Actions    []string `json:"actions"`
+	NotActions []string `json:"notActions,omitempty"`
+}
+
+var _ = Describe("CheckRoleActions", func() {
+	var (
+		pages      [][]permission
+		statusCode int
+		options    *arm.ClientOptions
+	)
+
+	BeforeEach(func() {
+		pages = [][]permission{{{Actions: []string{"*"}}}}
+		statusCode = http.StatusOK
+
+		// The stubbed Resource Manager API returns one page of permissions per request, linked by nextLink.
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/subscriptions/" + subscriptionID + "/resourceGroups/" + resourceGroup +
+				"/providers/Microsoft.Authorization/permissions"))
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer token"))
+
+			if statusCode != http.StatusOK {
+				w.WriteHeader(statusCode)
+				return
+			}
+
+			page := 0
+			if r.URL.Query().Has("page") {
+				page = 1
+			}
+
+			response := map[string]any{"value": pages[page]}
+			if page+1 < len(pages) {
+				response["nextLink"] = "https://" + r.Host + r.URL.Path + "?api-version=2022-04-01&page=1"
+			}
+
+			Expect(json.NewEncoder(w).Encode(response)).To(Succeed())
+		}))
+		DeferCleanup(server.Close)
+
+		options = &arm.ClientOptions{
+			ClientOptions: azcore.ClientOptions{
+				Cloud: cloud.Configuration{Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {Endpoint: server.URL, Audience: "https://management.azure.com"},
+				}},
+				Transport: server.Client(),
+				Retry:     policy.RetryOptions{MaxRetries: -1},
+			},
+		}
+	})
+
+	DescribeTable("the permissions",
+		func(permissions [][]permission, expected []string) {
+			pages = permissions
+
+			missing, err := azure.CheckRoleActions(context.TODO(), fakeCredential{}, options, subscriptionID, resourceGroup)
+			Expect(err).To(Succeed())
+			Expect(missing).To(Equal(expected))
+		},
+		Entry("allowing all the actions", [][]permission{{{Actions: []string{"*"}}}}, []string{}),
+		Entry("allowing the actions with wildcards", [][]permission{{{
+			Actions: []string{"Microsoft.Compute/*/read", "microsoft.network/*"},
+		}}}, []string{}),
+		Entry("excluding actions", [][]permission{{{
+			Actions:    []string{"*"},
+			NotActions: []string{"Microsoft.Network/*/write", "Microsoft.Network/networkSecurityGroups/securityRules/delete"},
+		}}}, []string{
+			"Microsoft.Network/networkInterfaces/write",
+			"Microsoft.Network/networkSecurityGroups/write",
+			"Microsoft.Network/networkSecurityGroups/securityRules/write",
+			"Microsoft.Network/networkSecurityGroups/securityRules/delete",
+			"Microsoft.Network/publicIPAddresses/write",
+		}),
+		Entry("excluding actions allowed by another permission", [][]permission{{
+			{Actions: []string{"*"}, NotActions: []string{"Microsoft.Network/*"}},
+			{Actions: []string{"Microsoft.Network/*"}},
+		}}, []string{}),
+		Entry("allowing actions on several pages", [][]permission{
+			{{Actions: []string{"Microsoft.Compute/*"}}},
+			{{Actions: []string{"Microsoft.Network/networkSecurityGroups/*"}}},
+		}, []string{
+			"Microsoft.Network/networkInterfaces/read",
+			"Microsoft.Network/networkInterfaces/write",
+			"Microsoft.Network/publicIPAddresses/read",
+			"Microsoft.Network/publicIPAddresses/write",
+		}),
+		Entry("not allowing any action", [][]permission{{}}, allActions),
+	)
+
+	When("the credentials are rejected", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusUnauthorized
+		})
+
+		It("should return an invalid credentials error", func() {
+			_, err := azure.CheckRoleActions(context.TODO(), fakeCredential{}, options, subscriptionID, resourceGroup)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+
+	When("listing the permissions fails", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusForbidden
+		})
+
+		It("should return an error", func() {
+			_, err := azure.CheckRoleActions(context.TODO(), fakeCredential{}, options, subscriptionID, resourceGroup)
+			Expect(err).To(HaveOccurred())
+			Expect(provider.IsInvalidCredentials(err)).To(BeFalse())
+		})
+	})
+})
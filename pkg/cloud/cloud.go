@@ -6,12 +6,18 @@ import (
 
 	"github.com/openshift/library-go/pkg/operator/events"
 	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/aks"
 	"github.com/stolostron/submariner-addon/pkg/cloud/aws"
 	"github.com/stolostron/submariner-addon/pkg/cloud/azure"
+	"github.com/stolostron/submariner-addon/pkg/cloud/eks"
 	"github.com/stolostron/submariner-addon/pkg/cloud/gcp"
+	"github.com/stolostron/submariner-addon/pkg/cloud/gke"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
 	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/cloud/rhos"
+	"github.com/stolostron/submariner-addon/pkg/cloud/roks"
 	"github.com/stolostron/submariner-addon/pkg/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
@@ -21,15 +27,46 @@ import (
 
 //go:generate mockgen -source=./cloud.go -destination=./fake/cloud.go -package=fake
 
+// Provider prepares the submariner cluster environment on a cloud. The methods stop once the given context is done: the calls
+// to the cloud APIs made by the providers use the context, the cloud-prepare operations which don't accept one are not started
+// once it's done.
 type Provider interface {
 	// PrepareSubmarinerClusterEnv prepares submariner cluster environment
-	PrepareSubmarinerClusterEnv() error
+	PrepareSubmarinerClusterEnv(ctx context.Context) error
 	// CleanUpSubmarinerClusterEnv clean up the prepared submariner cluster environment
-	CleanUpSubmarinerClusterEnv() error
+	CleanUpSubmarinerClusterEnv(ctx context.Context) error
+	// Plan returns the changes PrepareSubmarinerClusterEnv would make to prepare the submariner cluster environment, without
+	// applying them
+	Plan(ctx context.Context) ([]configv1alpha1.PlannedChange, error)
 }
 
 type ProviderFactory interface {
-	Get(config *configv1alpha1.SubmarinerConfig, eventsRecorder events.Recorder) (Provider, bool, error)
+	// Get returns the provider for the given config, reading its credentials and creating its cloud clients with the given
+	// context. The steps of the operations reported by the provider are recorded in the given step log, if set.
+	Get(ctx context.Context, config *configv1alpha1.SubmarinerConfig, eventsRecorder events.Recorder,
+		stepLog *provider.StepLog) (Provider, bool, error)
+}
+
+// PermissionsChecker is implemented by the providers which can check that their cloud credentials have the permissions required to
+// prepare the submariner cluster environment, without mutating anything.
+type PermissionsChecker interface {
+	// CheckPermissions returns the permissions missing from the cloud credentials.
+	CheckPermissions(ctx context.Context) ([]string, error)
+}
+
+// DriftDetector is implemented by the providers which can verify that the submariner cluster environment they prepared wasn't
+// changed since, without mutating anything.
+type DriftDetector interface {
+	// DetectDrift returns the descriptions of the changes of the prepared submariner cluster environment, such as the deleted
+	// firewall rules or the gateway MachineSets scaled to 0.
+	DetectDrift(ctx context.Context) ([]string, error)
+}
+
+// GatewayPlanner is implemented by the providers which prepare existing nodes as gateways, so the changes can be planned for the
+// nodes which would be labeled as gateways, without labeling them.
+type GatewayPlanner interface {
+	// PlanForGateways returns the changes PrepareSubmarinerClusterEnv would make to prepare the given gateway nodes.
+	PlanForGateways(ctx context.Context, gateways []string) ([]configv1alpha1.PlannedChange, error)
 }
 
 type providerFactory struct {
@@ -39,32 +76,88 @@ type providerFactory struct {
 	hubKubeClient kubernetes.Interface
 }
 
-type ProviderFn func(*provider.Info) (Provider, error)
+type ProviderFn func(context.Context, *provider.Info) (Provider, error)
 
-var providers = map[string]ProviderFn{}
+var (
+	providers       = map[string]ProviderFn{}
+	vendorProviders = map[string]ProviderFn{}
+)
 
 func init() {
-	RegisterProvider("AWS", func(info *provider.Info) (Provider, error) {
+	RegisterProvider("AWS", func(_ context.Context, info *provider.Info) (Provider, error) {
 		return aws.NewProvider(info)
 	})
 
-	RegisterProvider("GCP", func(info *provider.Info) (Provider, error) {
-		return gcp.NewProvider(info)
+	RegisterProvider("GCP", func(ctx context.Context, info *provider.Info) (Provider, error) {
+		return gcp.NewProvider(ctx, info)
 	})
 
-	RegisterProvider("OpenStack", func(info *provider.Info) (Provider, error) {
+	RegisterProvider("OpenStack", func(_ context.Context, info *provider.Info) (Provider, error) {
 		return rhos.NewProvider(info)
 	})
 
-	RegisterProvider("Azure", func(info *provider.Info) (Provider, error) {
+	RegisterProvider("Azure", func(_ context.Context, info *provider.Info) (Provider, error) {
 		return azure.NewProvider(info)
 	})
+
+	RegisterVendorProvider(constants.ProductEKS, managedProviderFn(constants.ProductEKS, withoutContext(eks.NewFirewall)))
+
+	RegisterVendorProvider(constants.ProductGKE, managedProviderFn(constants.ProductGKE, gke.NewFirewall))
+
+	RegisterVendorProvider(constants.ProductAKS, managedProviderFn(constants.ProductAKS, withoutContext(aks.NewFirewall)))
+
+	// MachineSets can't be created on managed OpenShift clusters, they are prepared like the managed Kubernetes ones. The
+	// ROSA nodes run in the customer's AWS account, their network interfaces can be given the gateway security group.
+	RegisterVendorProvider(constants.ProductROSA, managedProviderFn(constants.ProductROSA, withoutContext(eks.NewFirewall)))
+
+	// The network security groups of ARO clusters can't be modified, the nodes are only labeled and the preparation fails
+	// with the ports to open.
+	RegisterVendorProvider(constants.ProductARO, managedProviderFn(constants.ProductARO, withoutContext(aks.NewAROFirewall)))
+
+	RegisterVendorProvider(constants.ProductROKS, managedProviderFn(constants.ProductROKS, withoutContext(roks.NewFirewall)))
 }
 
 func RegisterProvider(platform string, f ProviderFn) {
 	providers[platform] = f
 }
 
+// RegisterVendorProvider registers a provider for the managed clusters of the given vendor, regardless of their platform.
+func RegisterVendorProvider(vendor string, f ProviderFn) {
+	vendorProviders[vendor] = f
+}
+
+// UnregisterVendorProvider removes the provider registered for the given vendor, if any.
+func UnregisterVendorProvider(vendor string) {
+	delete(vendorProviders, vendor)
+}
+
+// UsesExistingGatewayNodes returns whether the providers for the given vendor don't deploy dedicated gateway nodes, but
+// rely on existing nodes being labeled as gateways before preparing the cluster environment.
+func UsesExistingGatewayNodes(vendor string) bool {
+	_, found := vendorProviders[vendor]
+	return found
+}
+
+type firewallFn func(context.Context, *provider.Info) (managed.Firewall, error)
+
+func managedProviderFn(name string, newFirewall firewallFn) ProviderFn {
+	return func(ctx context.Context, info *provider.Info) (Provider, error) {
+		firewall, err := newFirewall(ctx, info)
+		if err != nil {
+			return nil, err
+		}
+
+		return managed.NewProvider(name, info, firewall), nil
+	}
+}
+
+// withoutContext adapts the given firewall constructor, which doesn't call the cloud APIs, to a firewallFn.
+func withoutContext(newFirewall func(*provider.Info) (managed.Firewall, error)) firewallFn {
+	return func(_ context.Context, info *provider.Info) (managed.Firewall, error) {
+		return newFirewall(info)
+	}
+}
+
 func NewProviderFactory(restMapper meta.RESTMapper, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
 	hubKubeClient kubernetes.Interface,
 ) ProviderFactory {
@@ -76,7 +169,9 @@ func NewProviderFactory(restMapper meta.RESTMapper, kubeClient kubernetes.Interf
 	}
 }
 
-func (f *providerFactory) Get(config *configv1alpha1.SubmarinerConfig, eventsRecorder events.Recorder) (Provider, bool, error) {
+func (f *providerFactory) Get(ctx context.Context, config *configv1alpha1.SubmarinerConfig, eventsRecorder events.Recorder,
+	stepLog *provider.StepLog,
+) (Provider, bool, error) {
 	managedClusterInfo := &config.Status.ManagedClusterInfo
 
 	klog.V(4).Infof("Get cloud provider: ManagedClusterInfo: %#v", managedClusterInfo)
@@ -86,10 +181,15 @@ func (f *providerFactory) Get(config *configv1alpha1.SubmarinerConfig, eventsRec
 		KubeClient:           f.kubeClient,
 		DynamicClient:        f.dynamicClient,
 		EventRecorder:        eventsRecorder,
+		StepLog:              stepLog,
 		SubmarinerConfigSpec: config.Spec,
 		ManagedClusterInfo:   *managedClusterInfo,
 	}
 
+	if info.SubmarinerConfigSpec.IPSecIKEPort == 0 {
+		info.SubmarinerConfigSpec.IPSecIKEPort = constants.SubmarinerIKEPort
+	}
+
 	if info.SubmarinerConfigSpec.IPSecNATTPort == 0 {
 		info.SubmarinerConfigSpec.IPSecNATTPort = constants.SubmarinerNatTPort
 	}
@@ -99,17 +199,22 @@ func (f *providerFactory) Get(config *configv1alpha1.SubmarinerConfig, eventsRec
 	}
 
 	vendor := managedClusterInfo.Vendor
-	if vendor == constants.ProductROSA || vendor == constants.ProductARO || vendor == constants.ProductROKS {
-		return nil, false, nil
-	}
 
-	if vendor != constants.ProductOCP {
-		return nil, false, fmt.Errorf("unsupported vendor %q for cluster %q", vendor, managedClusterInfo.ClusterName)
-	}
-
-	providerFn, found := providers[managedClusterInfo.Platform]
-	if !found {
-		return nil, false, nil
+	providerFn, found := vendorProviders[vendor]
+	if found {
+		if info.SubmarinerConfigSpec.CredentialsSecret == nil {
+			// without cloud credentials, the gateway nodes are only labeled and the ports must be opened by the user
+			return nil, false, nil
+		}
+	} else {
+		if vendor != constants.ProductOCP {
+			return nil, false, fmt.Errorf("unsupported vendor %q for cluster %q", vendor, managedClusterInfo.ClusterName)
+		}
+
+		providerFn, found = providers[managedClusterInfo.Platform]
+		if !found {
+			return nil, false, nil
+		}
 	}
 
 	if info.SubmarinerConfigSpec.CredentialsSecret == nil {
@@ -118,13 +223,17 @@ func (f *providerFactory) Get(config *configv1alpha1.SubmarinerConfig, eventsRec
 
 	var err error
 
-	info.CredentialsSecret, err = f.hubKubeClient.CoreV1().Secrets(info.ClusterName).Get(context.TODO(),
+	info.CredentialsSecret, err = f.hubKubeClient.CoreV1().Secrets(info.ClusterName).Get(ctx,
 		info.SubmarinerConfigSpec.CredentialsSecret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, true, fmt.Errorf("%w: %w", provider.ErrInvalidCredentials, err)
+	}
+
 	if err != nil {
 		return nil, true, err
 	}
 
-	instance, err := providerFn(info)
+	instance, err := providerFn(ctx, info)
 
 	return instance, true, err
 }
@@ -31,7 +31,12 @@ var _ = Describe("ProviderFactory Get", func() {
 	BeforeEach(func() {
 		hubKubeClient = kubeFake.NewSimpleClientset()
 
-		providerFactory = cloud.NewProviderFactory(nil, kubeFake.NewSimpleClientset(),
+		gateway := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "gateway",
+			Labels: map[string]string{"submariner.io/gateway": "true"},
+		}}
+
+		providerFactory = cloud.NewProviderFactory(nil, kubeFake.NewSimpleClientset(gateway),
 			dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), hubKubeClient)
 
 		submarinerConfig = &configv1alpha1.SubmarinerConfig{
@@ -49,7 +54,7 @@ var _ = Describe("ProviderFactory Get", func() {
 
 	When("the ManagedClusterInfo Platform has no provider implementation", func() {
 		It("should return false", func() {
-			provider, found, err := providerFactory.Get(submarinerConfig, events.NewLoggingEventRecorder("test"))
+			provider, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
 			Expect(err).To(Succeed())
 			Expect(found).To(BeFalse())
 			Expect(provider).To(BeNil())
@@ -62,7 +67,7 @@ var _ = Describe("ProviderFactory Get", func() {
 		})
 
 		It("should return an error", func() {
-			_, _, err := providerFactory.Get(submarinerConfig, events.NewLoggingEventRecorder("test"))
+			_, _, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -72,11 +77,171 @@ var _ = Describe("ProviderFactory Get", func() {
 			submarinerConfig.Status.ManagedClusterInfo.Vendor = constants.ProductROSA
 		})
 
-		It("should return false", func() {
-			provider, found, err := providerFactory.Get(submarinerConfig, events.NewLoggingEventRecorder("test"))
+		Context("and the credentials Secret reference isn't provided", func() {
+			It("should return false", func() {
+				provider, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+				Expect(err).To(Succeed())
+				Expect(found).To(BeFalse())
+				Expect(provider).To(BeNil())
+			})
+		})
+
+		Context("and the credentials Secret exists", func() {
+			BeforeEach(func() {
+				submarinerConfig.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "aws-creds"}
+
+				_, err := hubKubeClient.CoreV1().Secrets(clusterName).Create(context.TODO(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aws-creds",
+						Namespace: clusterName,
+					},
+					Data: map[string][]byte{
+						"aws_access_key_id":     []byte("id"),
+						"aws_secret_access_key": []byte("secret"),
+					},
+				}, metav1.CreateOptions{})
+				Expect(err).To(Succeed())
+			})
+
+			It("should return an instance which uses the existing gateway nodes", func() {
+				provider, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+				Expect(err).To(Succeed())
+				Expect(found).To(BeTrue())
+				Expect(provider).ToNot(BeNil())
+				Expect(cloud.UsesExistingGatewayNodes(constants.ProductROSA)).To(BeTrue())
+			})
+
+			It("should plan the firewall rules of the labeled gateway nodes", func() {
+				provider, _, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+				Expect(err).To(Succeed())
+
+				changes, err := provider.Plan(context.TODO())
+				Expect(err).To(Succeed())
+				Expect(changes).To(HaveLen(1))
+				Expect(changes[0].Kind).To(Equal("FirewallRule"))
+				Expect(changes[0].Name).To(Equal("gateway"))
+				Expect(changes[0].Description).To(ContainSubstring(constants.ProductROSA))
+			})
+		})
+	})
+
+	When("the ManagedClusterInfo Vendor is ARO", func() {
+		BeforeEach(func() {
+			submarinerConfig.Status.ManagedClusterInfo.Vendor = constants.ProductARO
+			submarinerConfig.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "azure-creds"}
+
+			_, err := hubKubeClient.CoreV1().Secrets(clusterName).Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "azure-creds",
+					Namespace: clusterName,
+				},
+			}, metav1.CreateOptions{})
 			Expect(err).To(Succeed())
-			Expect(found).To(BeFalse())
-			Expect(provider).To(BeNil())
+		})
+
+		It("should return an instance which fails to prepare the cluster with the ports to open", func() {
+			instance, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+			Expect(err).To(Succeed())
+			Expect(found).To(BeTrue())
+			Expect(cloud.UsesExistingGatewayNodes(constants.ProductARO)).To(BeTrue())
+
+			err = instance.PrepareSubmarinerClusterEnv(context.TODO())
+			Expect(err).To(MatchError(provider.ErrUnsupported))
+			Expect(err.Error()).To(ContainSubstring("4500/udp"))
+		})
+
+		It("should return an instance which cleans up the cluster", func() {
+			instance, _, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+			Expect(err).To(Succeed())
+			Expect(instance.CleanUpSubmarinerClusterEnv(context.TODO())).To(Succeed())
+		})
+	})
+
+	When("the ManagedClusterInfo Vendor is a managed Kubernetes service", func() {
+		BeforeEach(func() {
+			submarinerConfig.Status.ManagedClusterInfo.Vendor = constants.ProductEKS
+			submarinerConfig.Status.ManagedClusterInfo.Platform = "AWS"
+		})
+
+		Context("and the credentials Secret reference isn't provided", func() {
+			It("should return false", func() {
+				provider, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+				Expect(err).To(Succeed())
+				Expect(found).To(BeFalse())
+				Expect(provider).To(BeNil())
+			})
+		})
+
+		Context("and the credentials Secret exists", func() {
+			BeforeEach(func() {
+				submarinerConfig.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "aws-creds"}
+
+				_, err := hubKubeClient.CoreV1().Secrets(clusterName).Create(context.TODO(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aws-creds",
+						Namespace: clusterName,
+					},
+					Data: map[string][]byte{
+						"aws_access_key_id":     []byte("id"),
+						"aws_secret_access_key": []byte("secret"),
+					},
+				}, metav1.CreateOptions{})
+				Expect(err).To(Succeed())
+			})
+
+			It("should return an instance", func() {
+				provider, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+				Expect(err).To(Succeed())
+				Expect(found).To(BeTrue())
+				Expect(provider).ToNot(BeNil())
+				Expect(cloud.UsesExistingGatewayNodes(constants.ProductEKS)).To(BeTrue())
+			})
+		})
+	})
+
+	When("a vendor provider implementation is registered", func() {
+		mockProvider := &fake.MockProvider{}
+
+		var (
+			providerInfo *provider.Info
+			providerCtx  context.Context
+		)
+
+		BeforeEach(func() {
+			submarinerConfig.Status.ManagedClusterInfo.Vendor = "BAR"
+			submarinerConfig.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "test-secret"}
+			providerInfo = nil
+			providerCtx = nil
+
+			cloud.RegisterVendorProvider(submarinerConfig.Status.ManagedClusterInfo.Vendor,
+				func(ctx context.Context, info *provider.Info) (cloud.Provider, error) {
+					providerCtx = ctx
+					providerInfo = info
+
+					return mockProvider, nil
+				})
+			DeferCleanup(cloud.UnregisterVendorProvider, submarinerConfig.Status.ManagedClusterInfo.Vendor)
+
+			_, err := hubKubeClient.CoreV1().Secrets(clusterName).Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: clusterName,
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+		})
+
+		It("should return an instance regardless of the Platform", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			provider, found, err := providerFactory.Get(ctx, submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
+			Expect(err).To(Succeed())
+			Expect(found).To(BeTrue())
+			Expect(provider).To(Equal(mockProvider))
+			Expect(providerCtx).To(BeIdenticalTo(ctx))
+			Expect(providerInfo).ToNot(BeNil())
+			Expect(providerInfo.CredentialsSecret.Name).To(Equal("test-secret"))
 		})
 	})
 
@@ -91,13 +256,14 @@ var _ = Describe("ProviderFactory Get", func() {
 
 		BeforeEach(func() {
 			submarinerConfig.Status.ManagedClusterInfo.Platform = "FOO"
-			cloud.RegisterProvider(submarinerConfig.Status.ManagedClusterInfo.Platform, func(info *provider.Info) (cloud.Provider, error) {
-				Expect(info.IPSecNATTPort).To(Equal(constants.SubmarinerNatTPort))
-				Expect(info.NATTDiscoveryPort).To(Equal(constants.SubmarinerNatTDiscoveryPort))
-				Expect(info.CredentialsSecret).To(Equal(credentialsSecret))
+			cloud.RegisterProvider(submarinerConfig.Status.ManagedClusterInfo.Platform,
+				func(_ context.Context, info *provider.Info) (cloud.Provider, error) {
+					Expect(info.IPSecNATTPort).To(Equal(constants.SubmarinerNatTPort))
+					Expect(info.NATTDiscoveryPort).To(Equal(constants.SubmarinerNatTDiscoveryPort))
+					Expect(info.CredentialsSecret).To(Equal(credentialsSecret))
 
-				return mockProvider, nil
-			})
+					return mockProvider, nil
+				})
 		})
 
 		Context("and the credentials Secret exists", func() {
@@ -110,7 +276,7 @@ var _ = Describe("ProviderFactory Get", func() {
 			})
 
 			It("should return an instance", func() {
-				provider, found, err := providerFactory.Get(submarinerConfig, events.NewLoggingEventRecorder("test"))
+				provider, found, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
 
 				Expect(err).To(Succeed())
 				Expect(found).To(BeTrue())
@@ -120,7 +286,7 @@ var _ = Describe("ProviderFactory Get", func() {
 
 		Context("and the credentials Secret reference isn't provided", func() {
 			It("should return an error", func() {
-				_, _, err := providerFactory.Get(submarinerConfig, events.NewLoggingEventRecorder("test"))
+				_, _, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -130,9 +296,10 @@ var _ = Describe("ProviderFactory Get", func() {
 				submarinerConfig.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "test-secret"}
 			})
 
-			It("should return an error", func() {
-				_, _, err := providerFactory.Get(submarinerConfig, events.NewLoggingEventRecorder("test"))
+			It("should return an invalid credentials error", func() {
+				_, _, err := providerFactory.Get(context.TODO(), submarinerConfig, events.NewLoggingEventRecorder("test"), nil)
 				Expect(err).To(HaveOccurred())
+				Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
 			})
 		})
 	})
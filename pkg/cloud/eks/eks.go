@@ -0,0 +1,458 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+	awscloud "github.com/stolostron/submariner-addon/pkg/cloud/aws"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	publicCIDR      = "0.0.0.0/0"
+	ruleDescription = "Submariner"
+	// clusterTagKey is the tag identifying the security group rules created for a cluster.
+	clusterTagKey = "submariner.io/cluster"
+)
+
+// EC2API is the subset of the EC2 API used to open the Submariner ports on a dedicated security group of the gateways.
+type EC2API interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	CreateSecurityGroup(ctx context.Context, params *ec2.CreateSecurityGroupInput,
+		optFns ...func(*ec2.Options)) (*ec2.CreateSecurityGroupOutput, error)
+	DeleteSecurityGroup(ctx context.Context, params *ec2.DeleteSecurityGroupInput,
+		optFns ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)
+	DescribeSecurityGroupRules(ctx context.Context, params *ec2.DescribeSecurityGroupRulesInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupRulesOutput, error)
+	AuthorizeSecurityGroupIngress(ctx context.Context, params *ec2.AuthorizeSecurityGroupIngressInput,
+		optFns ...func(*ec2.Options)) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
+	RevokeSecurityGroupIngress(ctx context.Context, params *ec2.RevokeSecurityGroupIngressInput,
+		optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput,
+		optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)
+	ModifyNetworkInterfaceAttribute(ctx context.Context, params *ec2.ModifyNetworkInterfaceAttributeInput,
+		optFns ...func(*ec2.Options)) (*ec2.ModifyNetworkInterfaceAttributeOutput, error)
+}
+
+type firewall struct {
+	client      EC2API
+	clusterName string
+}
+
+// gatewayInterfaces describes the network interfaces of the gateway instances.
+type gatewayInterfaces struct {
+	vpcID string
+	// groups are the security groups of each network interface, by network interface ID.
+	groups map[string][]string
+}
+
+// NewFirewall returns a Firewall for the EKS or ROSA cluster described by the given info, using its AWS credentials, as
+// described by aws.NewCredentialsProvider.
+func NewFirewall(info *provider.Info) (managed.Firewall, error) {
+	if info.Region == "" {
+		return nil, fmt.Errorf("cluster region is empty")
+	}
+
+	credentialsProvider, err := awscloud.NewCredentialsProvider(info.CredentialsSecret, &info.GatewayConfig.AWS, info.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFirewallWithClient(ec2.New(ec2.Options{
+		Region:      info.Region,
+		Credentials: credentialsProvider,
+	}), info.ClusterName), nil
+}
+
+// NewFirewallWithClient returns a Firewall which opens the Submariner ports on a security group named after the given
+// cluster, attached to the network interfaces of the gateway instances only, using the given EC2 client. The node security
+// groups are shared by all the nodes of a node group, they aren't modified. The rules are tagged with the cluster name, so the
+// rules which are no longer desired can be removed.
+func NewFirewallWithClient(client EC2API, clusterName string) managed.Firewall {
+	return &firewall{client: client, clusterName: clusterName}
+}
+
+// OpenPorts ensures the security group of the cluster allows the given ports and is only attached to the gateways, revoking
+// the rules of the cluster which are no longer desired, for ports which changed or in other security groups.
+func (f *firewall) OpenPorts(ctx context.Context, gateways []corev1.Node, ports managed.Ports) error {
+	interfaces, err := f.interfacesFor(ctx, gateways)
+	if err != nil {
+		return err
+	}
+
+	groupID, err := f.findSecurityGroup(ctx)
+	if err != nil {
+		return err
+	}
+
+	if groupID == "" && len(gateways) > 0 {
+		groupID, err = f.createSecurityGroup(ctx, interfaces.vpcID)
+		if err != nil {
+			return err
+		}
+	}
+
+	desired := map[string]types.IpPermission{}
+
+	if groupID != "" {
+		workerGroups := sets.New[string]()
+		for _, groups := range interfaces.groups {
+			workerGroups.Insert(groups...)
+		}
+
+		for _, permission := range permissionsFor(sets.List(workerGroups.Delete(groupID)), ports) {
+			desired[permissionKey(groupID, &permission)] = permission
+		}
+	}
+
+	if err := f.ensureRules(ctx, desired); err != nil {
+		return err
+	}
+
+	if groupID == "" {
+		return nil
+	}
+
+	return f.ensureAttachments(ctx, groupID, interfaces.groups)
+}
+
+// ClosePorts revokes all the rules of the cluster and deletes its security group, they're removed even if no node is labeled
+// as gateway anymore.
+func (f *firewall) ClosePorts(ctx context.Context, _ []corev1.Node, _ managed.Ports) error {
+	if err := f.ensureRules(ctx, map[string]types.IpPermission{}); err != nil {
+		return err
+	}
+
+	groupID, err := f.findSecurityGroup(ctx)
+	if err != nil || groupID == "" {
+		return err
+	}
+
+	// A security group can only be deleted once it's no longer attached to a network interface.
+	if err := f.ensureAttachments(ctx, groupID, map[string][]string{}); err != nil {
+		return err
+	}
+
+	_, err = f.client.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String(groupID)})
+	if err != nil && !isAPIError(err, "InvalidGroup.NotFound") {
+		return errors.Wrapf(err, "error deleting security group %q", groupID)
+	}
+
+	return nil
+}
+
+// ensureRules authorizes the given permissions, by key, and revokes the other rules of the cluster, in any security group.
+func (f *firewall) ensureRules(ctx context.Context, desired map[string]types.IpPermission) error {
+	owned, err := f.ownRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	stale := []types.SecurityGroupRule{}
+
+	for i := range owned {
+		key := ruleKey(&owned[i])
+		if _, found := desired[key]; found {
+			delete(desired, key)
+			continue
+		}
+
+		stale = append(stale, owned[i])
+	}
+
+	if err := f.revoke(ctx, stale); err != nil {
+		return err
+	}
+
+	for _, key := range sets.List(sets.KeySet(desired)) {
+		groupID, _, _ := strings.Cut(key, "/")
+
+		_, err := f.client.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:           aws.String(groupID),
+			IpPermissions:     []types.IpPermission{desired[key]},
+			TagSpecifications: f.tagsFor(types.ResourceTypeSecurityGroupRule),
+		})
+		if err != nil && !isAPIError(err, "InvalidPermission.Duplicate") {
+			return errors.Wrapf(err, "error authorizing ingress on security group %q", groupID)
+		}
+	}
+
+	return nil
+}
+
+// ensureAttachments attaches the given security group to the given network interfaces, with their security groups, and
+// detaches it from the other network interfaces.
+func (f *firewall) ensureAttachments(ctx context.Context, groupID string, desired map[string][]string) error {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []types.Filter{{Name: aws.String("group-id"), Values: []string{groupID}}},
+	}
+
+	for {
+		output, err := f.client.DescribeNetworkInterfaces(ctx, input)
+		if err != nil {
+			return errors.Wrapf(err, "error describing the network interfaces of security group %q", groupID)
+		}
+
+		for i := range output.NetworkInterfaces {
+			interfaceID := aws.ToString(output.NetworkInterfaces[i].NetworkInterfaceId)
+			if _, found := desired[interfaceID]; found {
+				continue
+			}
+
+			groups := groupIDsOf(output.NetworkInterfaces[i].Groups)
+			if err := f.setGroups(ctx, interfaceID, slices.DeleteFunc(groups, func(id string) bool { return id == groupID })); err != nil {
+				return err
+			}
+		}
+
+		if aws.ToString(output.NextToken) == "" {
+			break
+		}
+
+		input.NextToken = output.NextToken
+	}
+
+	for _, interfaceID := range sets.List(sets.KeySet(desired)) {
+		if slices.Contains(desired[interfaceID], groupID) {
+			continue
+		}
+
+		if err := f.setGroups(ctx, interfaceID, append(slices.Clone(desired[interfaceID]), groupID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *firewall) setGroups(ctx context.Context, interfaceID string, groups []string) error {
+	_, err := f.client.ModifyNetworkInterfaceAttribute(ctx, &ec2.ModifyNetworkInterfaceAttributeInput{
+		NetworkInterfaceId: aws.String(interfaceID),
+		Groups:             groups,
+	})
+
+	return errors.Wrapf(err, "error setting the security groups of network interface %q", interfaceID)
+}
+
+// findSecurityGroup returns the ID of the security group of the cluster, or an empty string if it doesn't exist.
+func (f *firewall) findSecurityGroup(ctx context.Context) (string, error) {
+	name := managed.ResourceName(f.clusterName, "gateways")
+
+	output, err := f.client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("group-name"), Values: []string{name}},
+			{Name: aws.String("tag:" + clusterTagKey), Values: []string{f.clusterName}},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "error retrieving security group %q", name)
+	}
+
+	if len(output.SecurityGroups) == 0 {
+		return "", nil
+	}
+
+	return aws.ToString(output.SecurityGroups[0].GroupId), nil
+}
+
+func (f *firewall) createSecurityGroup(ctx context.Context, vpcID string) (string, error) {
+	name := managed.ResourceName(f.clusterName, "gateways")
+
+	output, err := f.client.CreateSecurityGroup(ctx, &ec2.CreateSecurityGroupInput{
+		GroupName:         aws.String(name),
+		Description:       aws.String("Submariner gateways of cluster " + f.clusterName),
+		VpcId:             aws.String(vpcID),
+		TagSpecifications: f.tagsFor(types.ResourceTypeSecurityGroup),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating security group %q", name)
+	}
+
+	return aws.ToString(output.GroupId), nil
+}
+
+func (f *firewall) tagsFor(resourceType types.ResourceType) []types.TagSpecification {
+	return []types.TagSpecification{{
+		ResourceType: resourceType,
+		Tags:         []types.Tag{{Key: aws.String(clusterTagKey), Value: aws.String(f.clusterName)}},
+	}}
+}
+
+// ownRules returns the ingress rules tagged with the name of the cluster, in any security group.
+func (f *firewall) ownRules(ctx context.Context) ([]types.SecurityGroupRule, error) {
+	rules := []types.SecurityGroupRule{}
+	input := &ec2.DescribeSecurityGroupRulesInput{
+		Filters: []types.Filter{{Name: aws.String("tag:" + clusterTagKey), Values: []string{f.clusterName}}},
+	}
+
+	for {
+		output, err := f.client.DescribeSecurityGroupRules(ctx, input)
+		if err != nil {
+			return nil, errors.Wrap(err, "error describing the security group rules of the cluster")
+		}
+
+		for i := range output.SecurityGroupRules {
+			if !aws.ToBool(output.SecurityGroupRules[i].IsEgress) {
+				rules = append(rules, output.SecurityGroupRules[i])
+			}
+		}
+
+		if aws.ToString(output.NextToken) == "" {
+			return rules, nil
+		}
+
+		input.NextToken = output.NextToken
+	}
+}
+
+func (f *firewall) revoke(ctx context.Context, rules []types.SecurityGroupRule) error {
+	ruleIDs := map[string][]string{}
+
+	for i := range rules {
+		groupID := aws.ToString(rules[i].GroupId)
+		ruleIDs[groupID] = append(ruleIDs[groupID], aws.ToString(rules[i].SecurityGroupRuleId))
+	}
+
+	for _, groupID := range sets.List(sets.KeySet(ruleIDs)) {
+		_, err := f.client.RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{
+			GroupId:              aws.String(groupID),
+			SecurityGroupRuleIds: ruleIDs[groupID],
+		})
+		if err != nil && !isAPIError(err, "InvalidSecurityGroupRuleId.NotFound") && !isAPIError(err, "InvalidGroup.NotFound") {
+			return errors.Wrapf(err, "error revoking ingress on security group %q", groupID)
+		}
+	}
+
+	return nil
+}
+
+// interfacesFor returns the VPC and the network interfaces of the gateway instances, with their security groups.
+func (f *firewall) interfacesFor(ctx context.Context, gateways []corev1.Node) (*gatewayInterfaces, error) {
+	interfaces := &gatewayInterfaces{groups: map[string][]string{}}
+	instanceIDs := []string{}
+
+	for i := range gateways {
+		instanceID, err := instanceIDFor(&gateways[i])
+		if err != nil {
+			return nil, err
+		}
+
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+
+	if len(instanceIDs) == 0 {
+		return interfaces, nil
+	}
+
+	output, err := f.client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIDs})
+	if err != nil {
+		return nil, errors.Wrap(err, "error describing the gateway instances")
+	}
+
+	for i := range output.Reservations {
+		for j := range output.Reservations[i].Instances {
+			instance := &output.Reservations[i].Instances[j]
+			interfaces.vpcID = aws.ToString(instance.VpcId)
+
+			for k := range instance.NetworkInterfaces {
+				interfaces.groups[aws.ToString(instance.NetworkInterfaces[k].NetworkInterfaceId)] =
+					groupIDsOf(instance.NetworkInterfaces[k].Groups)
+			}
+		}
+	}
+
+	return interfaces, nil
+}
+
+func groupIDsOf(groups []types.GroupIdentifier) []string {
+	ids := make([]string, len(groups))
+	for i := range groups {
+		ids[i] = aws.ToString(groups[i].GroupId)
+	}
+
+	return ids
+}
+
+// instanceIDFor extracts the EC2 instance ID from a node provider ID of the form aws:///<zone>/<instance-id>.
+func instanceIDFor(node *corev1.Node) (string, error) {
+	if !strings.HasPrefix(node.Spec.ProviderID, "aws://") {
+		return "", fmt.Errorf("node %q has an unexpected provider ID %q", node.Name, node.Spec.ProviderID)
+	}
+
+	return node.Spec.ProviderID[strings.LastIndex(node.Spec.ProviderID, "/")+1:], nil
+}
+
+// permissionsFor returns the permissions opening the public ports to any address, and the internal ports to the given worker
+// security groups.
+func permissionsFor(workerGroups []string, ports managed.Ports) []types.IpPermission {
+	permissions := []types.IpPermission{}
+
+	for _, port := range ports.Public {
+		permission := permissionFor(port)
+		permission.IpRanges = []types.IpRange{{CidrIp: aws.String(publicCIDR), Description: aws.String(ruleDescription)}}
+		permissions = append(permissions, permission)
+	}
+
+	for _, groupID := range workerGroups {
+		for _, port := range ports.Internal {
+			permission := permissionFor(port)
+			permission.UserIdGroupPairs = []types.UserIdGroupPair{
+				{GroupId: aws.String(groupID), Description: aws.String(ruleDescription)},
+			}
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions
+}
+
+func permissionFor(port cpapi.PortSpec) types.IpPermission {
+	return types.IpPermission{
+		IpProtocol: aws.String(port.Protocol),
+		FromPort:   aws.Int32(int32(port.Port)),
+		ToPort:     aws.Int32(int32(port.Port)),
+	}
+}
+
+// permissionKey identifies a permission of a security group by its protocol, port and source, as ruleKey does for a rule.
+func permissionKey(groupID string, permission *types.IpPermission) string {
+	source := ""
+
+	if len(permission.IpRanges) > 0 {
+		source = aws.ToString(permission.IpRanges[0].CidrIp)
+	} else if len(permission.UserIdGroupPairs) > 0 {
+		source = aws.ToString(permission.UserIdGroupPairs[0].GroupId)
+	}
+
+	return fmt.Sprintf("%s/%s/%d/%s", groupID, aws.ToString(permission.IpProtocol), aws.ToInt32(permission.FromPort), source)
+}
+
+func ruleKey(rule *types.SecurityGroupRule) string {
+	source := aws.ToString(rule.CidrIpv4)
+
+	if rule.ReferencedGroupInfo != nil {
+		source = aws.ToString(rule.ReferencedGroupInfo.GroupId)
+	}
+
+	return fmt.Sprintf("%s/%s/%d/%s", aws.ToString(rule.GroupId), aws.ToString(rule.IpProtocol), aws.ToInt32(rule.FromPort), source)
+}
+
+func isAPIError(err error, code string) bool {
+	var apiErr smithy.APIError
+
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
@@ -0,0 +1,13 @@
+package eks_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEKS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EKS Cloud Provider Suite")
+}
@@ -0,0 +1,536 @@
+package eks_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/eks"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	clusterName = "east"
+	vpcID       = "vpc-1"
+)
+
+// fakeEC2 stores the security groups, their rules with their tags, and the security groups of the network interfaces, as
+// EC2 does.
+type fakeEC2 struct {
+	// instances are the network interfaces of each instance.
+	instances map[string][]string
+	// interfaces are the security groups of each network interface.
+	interfaces     map[string][]string
+	securityGroups []types.SecurityGroup
+	rules          []types.SecurityGroupRule
+	authorizeErr   error
+	authorizeCount int
+	nextID         int
+}
+
+func (f *fakeEC2) DescribeInstances(_ context.Context, params *ec2.DescribeInstancesInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeInstancesOutput, error) {
+	instances := []types.Instance{}
+
+	for _, id := range params.InstanceIds {
+		interfaces := []types.InstanceNetworkInterface{}
+		for _, interfaceID := range f.instances[id] {
+			interfaces = append(interfaces, types.InstanceNetworkInterface{
+				NetworkInterfaceId: aws.String(interfaceID),
+				Groups:             groupIdentifiers(f.interfaces[interfaceID]),
+			})
+		}
+
+		instances = append(instances, types.Instance{
+			InstanceId:        aws.String(id),
+			VpcId:             aws.String(vpcID),
+			NetworkInterfaces: interfaces,
+		})
+	}
+
+	return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: instances}}}, nil
+}
+
+func (f *fakeEC2) DescribeSecurityGroups(_ context.Context, params *ec2.DescribeSecurityGroupsInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeSecurityGroupsOutput, error) {
+	output := &ec2.DescribeSecurityGroupsOutput{}
+
+	for _, group := range f.securityGroups {
+		tagFilters := []types.Filter{}
+		matches := true
+
+		for _, filter := range params.Filters {
+			if aws.ToString(filter.Name) == "group-name" {
+				matches = matches && slices.Contains(filter.Values, aws.ToString(group.GroupName))
+			} else {
+				tagFilters = append(tagFilters, filter)
+			}
+		}
+
+		if matches && hasTags(group.Tags, tagFilters) {
+			output.SecurityGroups = append(output.SecurityGroups, group)
+		}
+	}
+
+	return output, nil
+}
+
+func (f *fakeEC2) CreateSecurityGroup(_ context.Context, params *ec2.CreateSecurityGroupInput,
+	_ ...func(*ec2.Options),
+) (*ec2.CreateSecurityGroupOutput, error) {
+	Expect(aws.ToString(params.VpcId)).To(Equal(vpcID))
+
+	f.nextID++
+
+	group := types.SecurityGroup{
+		GroupId:   aws.String(fmt.Sprintf("sg-%d", f.nextID)),
+		GroupName: params.GroupName,
+		VpcId:     params.VpcId,
+	}
+
+	for _, spec := range params.TagSpecifications {
+		Expect(spec.ResourceType).To(Equal(types.ResourceTypeSecurityGroup))
+		group.Tags = append(group.Tags, spec.Tags...)
+	}
+
+	f.securityGroups = append(f.securityGroups, group)
+
+	return &ec2.CreateSecurityGroupOutput{GroupId: group.GroupId}, nil
+}
+
+func (f *fakeEC2) DeleteSecurityGroup(_ context.Context, params *ec2.DeleteSecurityGroupInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DeleteSecurityGroupOutput, error) {
+	for _, groups := range f.interfaces {
+		if slices.Contains(groups, aws.ToString(params.GroupId)) {
+			return nil, &smithy.GenericAPIError{Code: "DependencyViolation"}
+		}
+	}
+
+	f.securityGroups = slices.DeleteFunc(f.securityGroups, func(group types.SecurityGroup) bool {
+		return aws.ToString(group.GroupId) == aws.ToString(params.GroupId)
+	})
+
+	return &ec2.DeleteSecurityGroupOutput{}, nil
+}
+
+func (f *fakeEC2) DescribeNetworkInterfaces(_ context.Context, params *ec2.DescribeNetworkInterfacesInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	Expect(params.Filters).To(HaveLen(1))
+	Expect(aws.ToString(params.Filters[0].Name)).To(Equal("group-id"))
+
+	output := &ec2.DescribeNetworkInterfacesOutput{}
+
+	for _, interfaceID := range sets.List(sets.KeySet(f.interfaces)) {
+		if slices.ContainsFunc(f.interfaces[interfaceID], func(groupID string) bool {
+			return slices.Contains(params.Filters[0].Values, groupID)
+		}) {
+			output.NetworkInterfaces = append(output.NetworkInterfaces, types.NetworkInterface{
+				NetworkInterfaceId: aws.String(interfaceID),
+				Groups:             groupIdentifiers(f.interfaces[interfaceID]),
+			})
+		}
+	}
+
+	return output, nil
+}
+
+func (f *fakeEC2) ModifyNetworkInterfaceAttribute(_ context.Context, params *ec2.ModifyNetworkInterfaceAttributeInput,
+	_ ...func(*ec2.Options),
+) (*ec2.ModifyNetworkInterfaceAttributeOutput, error) {
+	f.interfaces[aws.ToString(params.NetworkInterfaceId)] = params.Groups
+
+	return &ec2.ModifyNetworkInterfaceAttributeOutput{}, nil
+}
+
+func (f *fakeEC2) DescribeSecurityGroupRules(_ context.Context, params *ec2.DescribeSecurityGroupRulesInput,
+	_ ...func(*ec2.Options),
+) (*ec2.DescribeSecurityGroupRulesOutput, error) {
+	output := &ec2.DescribeSecurityGroupRulesOutput{}
+
+	for _, rule := range f.rules {
+		if hasTags(rule.Tags, params.Filters) {
+			output.SecurityGroupRules = append(output.SecurityGroupRules, rule)
+		}
+	}
+
+	return output, nil
+}
+
+func (f *fakeEC2) AuthorizeSecurityGroupIngress(_ context.Context, params *ec2.AuthorizeSecurityGroupIngressInput,
+	_ ...func(*ec2.Options),
+) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
+	if f.authorizeErr != nil {
+		return nil, f.authorizeErr
+	}
+
+	f.authorizeCount++
+
+	tags := []types.Tag{}
+	for _, spec := range params.TagSpecifications {
+		Expect(spec.ResourceType).To(Equal(types.ResourceTypeSecurityGroupRule))
+		tags = append(tags, spec.Tags...)
+	}
+
+	for _, permission := range params.IpPermissions {
+		f.nextID++
+
+		rule := types.SecurityGroupRule{
+			SecurityGroupRuleId: aws.String(fmt.Sprintf("sgr-%d", f.nextID)),
+			GroupId:             params.GroupId,
+			IsEgress:            aws.Bool(false),
+			IpProtocol:          permission.IpProtocol,
+			FromPort:            permission.FromPort,
+			ToPort:              permission.ToPort,
+			Tags:                tags,
+		}
+
+		if len(permission.IpRanges) > 0 {
+			rule.CidrIpv4 = permission.IpRanges[0].CidrIp
+		} else {
+			rule.ReferencedGroupInfo = &types.ReferencedSecurityGroup{GroupId: permission.UserIdGroupPairs[0].GroupId}
+		}
+
+		f.rules = append(f.rules, rule)
+	}
+
+	return &ec2.AuthorizeSecurityGroupIngressOutput{}, nil
+}
+
+func (f *fakeEC2) RevokeSecurityGroupIngress(_ context.Context, params *ec2.RevokeSecurityGroupIngressInput,
+	_ ...func(*ec2.Options),
+) (*ec2.RevokeSecurityGroupIngressOutput, error) {
+	rules := []types.SecurityGroupRule{}
+
+	for _, rule := range f.rules {
+		if *rule.GroupId != *params.GroupId || !slices.Contains(params.SecurityGroupRuleIds, *rule.SecurityGroupRuleId) {
+			rules = append(rules, rule)
+		}
+	}
+
+	f.rules = rules
+
+	return &ec2.RevokeSecurityGroupIngressOutput{}, nil
+}
+
+// gatewayGroup returns the ID of the security group created for the gateways of the cluster, or an empty string.
+func (f *fakeEC2) gatewayGroup() string {
+	for _, group := range f.securityGroups {
+		if aws.ToString(group.GroupName) == managed.ResourceName(clusterName, "gateways") {
+			return aws.ToString(group.GroupId)
+		}
+	}
+
+	return ""
+}
+
+// rulesOf returns the ports opened by the rules of the given security group, as <protocol>/<port>/<source>.
+func (f *fakeEC2) rulesOf(groupID string) []string {
+	opened := []string{}
+
+	for _, rule := range f.rules {
+		if *rule.GroupId != groupID {
+			continue
+		}
+
+		source := aws.ToString(rule.CidrIpv4)
+		if rule.ReferencedGroupInfo != nil {
+			source = *rule.ReferencedGroupInfo.GroupId
+		}
+
+		opened = append(opened, fmt.Sprintf("%s/%d/%s", *rule.IpProtocol, *rule.FromPort, source))
+	}
+
+	return opened
+}
+
+var _ = Describe("EKS firewall", func() {
+	var (
+		client   *fakeEC2
+		gateways []corev1.Node
+		ports    managed.Ports
+		firewall managed.Firewall
+	)
+
+	BeforeEach(func() {
+		client = &fakeEC2{
+			instances: map[string][]string{
+				"i-0001": {"eni-0001"},
+				"i-0002": {"eni-0002"},
+				"i-0003": {"eni-0003"},
+			},
+			interfaces: map[string][]string{
+				"eni-0001": {"sg-cluster", "sg-node"},
+				"eni-0002": {"sg-cluster"},
+				"eni-0003": {"sg-cluster"},
+			},
+		}
+
+		gateways = []corev1.Node{newNode("node-1", "aws:///us-east-1a/i-0001"), newNode("node-2", "aws:///us-east-1b/i-0002")}
+
+		ports = managed.Ports{
+			Public:   []cpapi.PortSpec{{Port: 4500, Protocol: "udp"}},
+			Internal: []cpapi.PortSpec{{Port: 4800, Protocol: "udp"}},
+		}
+
+		firewall = eks.NewFirewallWithClient(client, clusterName)
+	})
+
+	Context("OpenPorts", func() {
+		It("should authorize the ports on a security group of the cluster only", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+			groupID := client.gatewayGroup()
+			Expect(groupID).ToNot(BeEmpty())
+			Expect(client.rulesOf(groupID)).To(ConsistOf("udp/4500/0.0.0.0/0", "udp/4800/sg-cluster", "udp/4800/sg-node"))
+			Expect(client.rulesOf("sg-cluster")).To(BeEmpty())
+			Expect(client.rulesOf("sg-node")).To(BeEmpty())
+		})
+
+		It("should attach the security group to the gateway network interfaces only", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+			groupID := client.gatewayGroup()
+			Expect(client.interfaces["eni-0001"]).To(ConsistOf("sg-cluster", "sg-node", groupID))
+			Expect(client.interfaces["eni-0002"]).To(ConsistOf("sg-cluster", groupID))
+			Expect(client.interfaces["eni-0003"]).To(ConsistOf("sg-cluster"))
+		})
+
+		It("should tag the security group and the rules with the cluster name", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+			tag := types.Tag{Key: aws.String("submariner.io/cluster"), Value: aws.String(clusterName)}
+			Expect(client.securityGroups[0].Tags).To(ConsistOf(tag))
+
+			for _, rule := range client.rules {
+				Expect(rule.Tags).To(ConsistOf(tag))
+			}
+		})
+
+		It("should not create the security group or authorize the existing rules again", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			count := client.authorizeCount
+
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.securityGroups).To(HaveLen(1))
+			Expect(client.authorizeCount).To(Equal(count))
+		})
+
+		When("a port changed", func() {
+			It("should revoke the rules of the previous port", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+				ports.Public = []cpapi.PortSpec{{Port: 4501, Protocol: "udp"}}
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.rulesOf(client.gatewayGroup())).To(ConsistOf("udp/4501/0.0.0.0/0", "udp/4800/sg-cluster",
+					"udp/4800/sg-node"))
+			})
+		})
+
+		When("a node isn't a gateway anymore", func() {
+			It("should detach the security group from its network interface", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(firewall.OpenPorts(context.TODO(), gateways[1:], ports)).To(Succeed())
+				Expect(client.interfaces["eni-0001"]).To(ConsistOf("sg-cluster", "sg-node"))
+				Expect(client.rulesOf(client.gatewayGroup())).To(ConsistOf("udp/4500/0.0.0.0/0", "udp/4800/sg-cluster"))
+			})
+		})
+
+		When("the node security groups have rules of the cluster", func() {
+			BeforeEach(func() {
+				client.rules = []types.SecurityGroupRule{
+					newRule("sgr-own", "sg-node", 4500, types.Tag{Key: aws.String("submariner.io/cluster"), Value: aws.String(clusterName)}),
+				}
+			})
+
+			It("should revoke them", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.rulesOf("sg-node")).To(BeEmpty())
+			})
+		})
+
+		When("the security groups have rules which weren't created for the cluster", func() {
+			BeforeEach(func() {
+				client.rules = []types.SecurityGroupRule{
+					newRule("sgr-other", "sg-node", 22, types.Tag{Key: aws.String("submariner.io/cluster"), Value: aws.String("west")}),
+					newRule("sgr-untagged", "sg-node", 4501),
+				}
+			})
+
+			It("should not revoke them", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.rulesOf("sg-node")).To(ConsistOf("tcp/22/10.0.0.0/16", "tcp/4501/10.0.0.0/16"))
+			})
+		})
+
+		When("the rules already exist", func() {
+			BeforeEach(func() {
+				client.authorizeErr = &smithy.GenericAPIError{Code: "InvalidPermission.Duplicate"}
+			})
+
+			It("should succeed", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			})
+		})
+
+		When("authorizing fails", func() {
+			BeforeEach(func() {
+				client.authorizeErr = &smithy.GenericAPIError{Code: "UnauthorizedOperation"}
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+
+		When("a node has an unexpected provider ID", func() {
+			BeforeEach(func() {
+				gateways[1].Spec.ProviderID = "gce://project/zone/name"
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+	})
+
+	Context("ClosePorts", func() {
+		BeforeEach(func() {
+			client.rules = []types.SecurityGroupRule{newRule("sgr-untagged", "sg-node", 22)}
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+		})
+
+		It("should detach and delete the security group of the cluster, even without gateways", func() {
+			Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+			Expect(client.securityGroups).To(BeEmpty())
+			Expect(client.interfaces["eni-0001"]).To(ConsistOf("sg-cluster", "sg-node"))
+			Expect(client.interfaces["eni-0002"]).To(ConsistOf("sg-cluster"))
+			Expect(client.rulesOf("sg-node")).To(ConsistOf("tcp/22/10.0.0.0/16"))
+		})
+
+		When("the security group doesn't exist", func() {
+			It("should succeed", func() {
+				Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+				Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+			})
+		})
+	})
+})
+
+var _ = Describe("NewFirewall", func() {
+	var info *provider.Info
+
+	BeforeEach(func() {
+		info = &provider.Info{
+			CredentialsSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "east"},
+				Data: map[string][]byte{
+					"aws_access_key_id":     []byte("static-key"),
+					"aws_secret_access_key": []byte("static-secret"),
+				},
+			},
+			ManagedClusterInfo: configv1alpha1.ManagedClusterInfo{Region: "us-east-1"},
+		}
+	})
+
+	When("the secret contains static credentials", func() {
+		It("should succeed", func() {
+			Expect(eks.NewFirewall(info)).ToNot(BeNil())
+		})
+	})
+
+	When("the secret only contains a role", func() {
+		BeforeEach(func() {
+			info.CredentialsSecret.Data = map[string][]byte{
+				"role_arn": []byte("arn:aws:iam::123456789012:role/submariner"),
+			}
+		})
+
+		It("should succeed", func() {
+			Expect(eks.NewFirewall(info)).ToNot(BeNil())
+		})
+	})
+
+	When("the secret doesn't contain any credentials", func() {
+		BeforeEach(func() {
+			info.CredentialsSecret.Data = map[string][]byte{}
+		})
+
+		It("should return an invalid credentials error", func() {
+			_, err := eks.NewFirewall(info)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+
+	When("the cluster region is empty", func() {
+		BeforeEach(func() {
+			info.Region = ""
+		})
+
+		It("should return an error", func() {
+			_, err := eks.NewFirewall(info)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func newRule(id, groupID string, port int32, tags ...types.Tag) types.SecurityGroupRule {
+	return types.SecurityGroupRule{
+		SecurityGroupRuleId: aws.String(id),
+		GroupId:             aws.String(groupID),
+		IsEgress:            aws.Bool(false),
+		IpProtocol:          aws.String("tcp"),
+		FromPort:            aws.Int32(port),
+		ToPort:              aws.Int32(port),
+		CidrIpv4:            aws.String("10.0.0.0/16"),
+		Tags:                tags,
+	}
+}
+
+// hasTags returns whether the given tags match the tag filters.
+func hasTags(tags []types.Tag, filters []types.Filter) bool {
+	for _, filter := range filters {
+		name, found := strings.CutPrefix(aws.ToString(filter.Name), "tag:")
+		Expect(found).To(BeTrue(), "unexpected filter %q", aws.ToString(filter.Name))
+
+		if !slices.ContainsFunc(tags, func(tag types.Tag) bool {
+			return aws.ToString(tag.Key) == name && slices.Contains(filter.Values, aws.ToString(tag.Value))
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func groupIdentifiers(groupIDs []string) []types.GroupIdentifier {
+	groups := []types.GroupIdentifier{}
+	for _, groupID := range groupIDs {
+		groups = append(groups, types.GroupIdentifier{GroupId: aws.String(groupID)})
+	}
+
+	return groups
+}
+
+func newNode(name, providerID string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{ProviderID: providerID},
+	}
+}
@@ -5,15 +5,18 @@
 //
 //	mockgen -source=./cloud.go -destination=./fake/cloud.go -package=fake
 //
+
 // Package fake is a generated GoMock package.
 package fake
 
 import (
+	context "context"
 	reflect "reflect"
 
 	events "github.com/openshift/library-go/pkg/operator/events"
 	v1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
 	cloud "github.com/stolostron/submariner-addon/pkg/cloud"
+	provider "github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,31 +44,46 @@ func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
 }
 
 // CleanUpSubmarinerClusterEnv mocks base method.
-func (m *MockProvider) CleanUpSubmarinerClusterEnv() error {
+func (m *MockProvider) CleanUpSubmarinerClusterEnv(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CleanUpSubmarinerClusterEnv")
+	ret := m.ctrl.Call(m, "CleanUpSubmarinerClusterEnv", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CleanUpSubmarinerClusterEnv indicates an expected call of CleanUpSubmarinerClusterEnv.
-func (mr *MockProviderMockRecorder) CleanUpSubmarinerClusterEnv() *gomock.Call {
+func (mr *MockProviderMockRecorder) CleanUpSubmarinerClusterEnv(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanUpSubmarinerClusterEnv", reflect.TypeOf((*MockProvider)(nil).CleanUpSubmarinerClusterEnv), ctx)
+}
+
+// Plan mocks base method.
+func (m *MockProvider) Plan(ctx context.Context) ([]v1alpha1.PlannedChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx)
+	ret0, _ := ret[0].([]v1alpha1.PlannedChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockProviderMockRecorder) Plan(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanUpSubmarinerClusterEnv", reflect.TypeOf((*MockProvider)(nil).CleanUpSubmarinerClusterEnv))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockProvider)(nil).Plan), ctx)
 }
 
 // PrepareSubmarinerClusterEnv mocks base method.
-func (m *MockProvider) PrepareSubmarinerClusterEnv() error {
+func (m *MockProvider) PrepareSubmarinerClusterEnv(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PrepareSubmarinerClusterEnv")
+	ret := m.ctrl.Call(m, "PrepareSubmarinerClusterEnv", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PrepareSubmarinerClusterEnv indicates an expected call of PrepareSubmarinerClusterEnv.
-func (mr *MockProviderMockRecorder) PrepareSubmarinerClusterEnv() *gomock.Call {
+func (mr *MockProviderMockRecorder) PrepareSubmarinerClusterEnv(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareSubmarinerClusterEnv", reflect.TypeOf((*MockProvider)(nil).PrepareSubmarinerClusterEnv))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareSubmarinerClusterEnv", reflect.TypeOf((*MockProvider)(nil).PrepareSubmarinerClusterEnv), ctx)
 }
 
 // MockProviderFactory is a mock of ProviderFactory interface.
@@ -92,9 +110,9 @@ func (m *MockProviderFactory) EXPECT() *MockProviderFactoryMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockProviderFactory) Get(config *v1alpha1.SubmarinerConfig, eventsRecorder events.Recorder) (cloud.Provider, bool, error) {
+func (m *MockProviderFactory) Get(ctx context.Context, config *v1alpha1.SubmarinerConfig, eventsRecorder events.Recorder, stepLog *provider.StepLog) (cloud.Provider, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", config, eventsRecorder)
+	ret := m.ctrl.Call(m, "Get", ctx, config, eventsRecorder, stepLog)
 	ret0, _ := ret[0].(cloud.Provider)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
@@ -102,7 +120,121 @@ func (m *MockProviderFactory) Get(config *v1alpha1.SubmarinerConfig, eventsRecor
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockProviderFactoryMockRecorder) Get(config, eventsRecorder any) *gomock.Call {
+func (mr *MockProviderFactoryMockRecorder) Get(ctx, config, eventsRecorder, stepLog any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProviderFactory)(nil).Get), ctx, config, eventsRecorder, stepLog)
+}
+
+// MockPermissionsChecker is a mock of PermissionsChecker interface.
+type MockPermissionsChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionsCheckerMockRecorder
+}
+
+// MockPermissionsCheckerMockRecorder is the mock recorder for MockPermissionsChecker.
+type MockPermissionsCheckerMockRecorder struct {
+	mock *MockPermissionsChecker
+}
+
+// NewMockPermissionsChecker creates a new mock instance.
+func NewMockPermissionsChecker(ctrl *gomock.Controller) *MockPermissionsChecker {
+	mock := &MockPermissionsChecker{ctrl: ctrl}
+	mock.recorder = &MockPermissionsCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionsChecker) EXPECT() *MockPermissionsCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckPermissions mocks base method.
+func (m *MockPermissionsChecker) CheckPermissions(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPermissions", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissions indicates an expected call of CheckPermissions.
+func (mr *MockPermissionsCheckerMockRecorder) CheckPermissions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissions", reflect.TypeOf((*MockPermissionsChecker)(nil).CheckPermissions), ctx)
+}
+
+// MockDriftDetector is a mock of DriftDetector interface.
+type MockDriftDetector struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriftDetectorMockRecorder
+}
+
+// MockDriftDetectorMockRecorder is the mock recorder for MockDriftDetector.
+type MockDriftDetectorMockRecorder struct {
+	mock *MockDriftDetector
+}
+
+// NewMockDriftDetector creates a new mock instance.
+func NewMockDriftDetector(ctrl *gomock.Controller) *MockDriftDetector {
+	mock := &MockDriftDetector{ctrl: ctrl}
+	mock.recorder = &MockDriftDetectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriftDetector) EXPECT() *MockDriftDetectorMockRecorder {
+	return m.recorder
+}
+
+// DetectDrift mocks base method.
+func (m *MockDriftDetector) DetectDrift(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectDrift", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectDrift indicates an expected call of DetectDrift.
+func (mr *MockDriftDetectorMockRecorder) DetectDrift(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectDrift", reflect.TypeOf((*MockDriftDetector)(nil).DetectDrift), ctx)
+}
+
+// MockGatewayPlanner is a mock of GatewayPlanner interface.
+type MockGatewayPlanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockGatewayPlannerMockRecorder
+}
+
+// MockGatewayPlannerMockRecorder is the mock recorder for MockGatewayPlanner.
+type MockGatewayPlannerMockRecorder struct {
+	mock *MockGatewayPlanner
+}
+
+// NewMockGatewayPlanner creates a new mock instance.
+func NewMockGatewayPlanner(ctrl *gomock.Controller) *MockGatewayPlanner {
+	mock := &MockGatewayPlanner{ctrl: ctrl}
+	mock.recorder = &MockGatewayPlannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGatewayPlanner) EXPECT() *MockGatewayPlannerMockRecorder {
+	return m.recorder
+}
+
+// PlanForGateways mocks base method.
+func (m *MockGatewayPlanner) PlanForGateways(ctx context.Context, gateways []string) ([]v1alpha1.PlannedChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanForGateways", ctx, gateways)
+	ret0, _ := ret[0].([]v1alpha1.PlannedChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanForGateways indicates an expected call of PlanForGateways.
+func (mr *MockGatewayPlannerMockRecorder) PlanForGateways(ctx, gateways any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProviderFactory)(nil).Get), config, eventsRecorder)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanForGateways", reflect.TypeOf((*MockGatewayPlanner)(nil).PlanForGateways), ctx, gateways)
 }
@@ -0,0 +1,53 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/dns/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CredentialsSecretKey is the key of the credentials secret holding the GCP credentials.
+const CredentialsSecretKey = "osServiceAccount.json"
+
+// NewCredentials returns the GCP credentials in the given credentials secret, either a service account key or an external
+// account configuration, for workload identity federation. The configuration of an external account has no project ID
+// unless a project_id is added to it, the project ID is then its quota_project_id if set.
+func NewCredentials(ctx context.Context, credentialsSecret *corev1.Secret) (*google.Credentials, error) {
+	authJSON, ok := credentialsSecret.Data[CredentialsSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("the gcp credentials %s is not in secret %s/%s: %w", CredentialsSecretKey,
+			credentialsSecret.Namespace, credentialsSecret.Name, provider.ErrInvalidCredentials)
+	}
+
+	var authInfo struct {
+		Type           string `json:"type"`
+		QuotaProjectID string `json:"quota_project_id"`
+	}
+
+	if err := json.Unmarshal(authJSON, &authInfo); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the gcp credentials %s: %w: %w", CredentialsSecretKey,
+			provider.ErrInvalidCredentials, err)
+	}
+
+	if authInfo.Type != "service_account" && authInfo.Type != "external_account" {
+		return nil, fmt.Errorf("the type %q of the gcp credentials %s isn't service_account nor external_account: %w",
+			authInfo.Type, CredentialsSecretKey, provider.ErrInvalidCredentials)
+	}
+
+	// since we're using a single creds var, we should specify all the required scopes when initializing
+	creds, err := google.CredentialsFromJSON(ctx, authJSON, dns.CloudPlatformScope)
+	if err != nil {
+		return nil, err
+	}
+
+	if creds.ProjectID == "" {
+		creds.ProjectID = authInfo.QuotaProjectID
+	}
+
+	return creds, nil
+}
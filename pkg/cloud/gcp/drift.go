@@ -0,0 +1,72 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"google.golang.org/api/googleapi"
+)
+
+// DetectDrift verifies that the firewall rules still open the Submariner ports, and that the gateway MachineSets still
+// deploy the gateway nodes.
+func (g *gcpProvider) DetectDrift(ctx context.Context) ([]string, error) {
+	drifts := []string{}
+
+	for _, rule := range g.rules() {
+		firewall, err := g.firewalls.Get(g.projectID, rule.Name).Context(ctx).Do()
+		if isNotFound(err) {
+			drifts = append(drifts, rule.Deleted())
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving the firewall rule %q: %w", rule.Name, err)
+		}
+
+		if firewall.Disabled {
+			drifts = append(drifts, fmt.Sprintf("the %s %q is disabled", rule.Kind, rule.Name))
+			continue
+		}
+
+		opened := []ports.Range{}
+
+		for _, allowed := range firewall.Allowed {
+			protocol := allowed.IPProtocol
+			if protocol == "all" {
+				protocol = ports.ProtocolAny
+			}
+
+			if len(allowed.Ports) == 0 {
+				opened = append(opened, ports.Range{Protocol: protocol})
+			}
+
+			for _, portRange := range allowed.Ports {
+				r, err := ports.ParseRange(protocol, portRange)
+				if err != nil {
+					return nil, err
+				}
+
+				opened = append(opened, r)
+			}
+		}
+
+		drifts = append(drifts, rule.Drift(opened)...)
+	}
+
+	machineSetDrifts, err := provider.DetectGatewayMachineSetsDrift(ctx, g.dynamicClient, g.infraID, g.gateways)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(drifts, machineSetDrifts...), nil
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
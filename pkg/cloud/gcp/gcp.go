@@ -3,62 +3,85 @@ package gcp
 import (
 	"context"
 	"fmt"
-	"strings"
+	"regexp"
 
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
 	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/cloud/reporter"
-	"github.com/stolostron/submariner-addon/pkg/constants"
 	submreporter "github.com/submariner-io/admiral/pkg/reporter"
 	"github.com/submariner-io/cloud-prepare/pkg/api"
 	cloudpreparegcp "github.com/submariner-io/cloud-prepare/pkg/gcp"
 	gcpclient "github.com/submariner-io/cloud-prepare/pkg/gcp/client"
 	"github.com/submariner-io/cloud-prepare/pkg/k8s"
 	"github.com/submariner-io/cloud-prepare/pkg/ocp"
-	"github.com/submariner-io/submariner/pkg/cni"
 	"golang.org/x/oauth2/google"
-	"google.golang.org/api/dns/v1"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/option"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
-const (
-	gcpCredentialsName = "osServiceAccount.json"
-	gwInstanceType     = "n1-standard-4"
-)
+const gwInstanceType = "n1-standard-4"
+
+// machineTypeRegexp matches the names of the predefined and custom machine types, such as n1-standard-4, e2-medium or
+// n2-custom-4-16384.
+var machineTypeRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)+$`)
 
 type gcpProvider struct {
-	infraID           string
-	nattPort          uint16
-	cniType           string
-	cloudPrepare      api.Cloud
-	reporter          submreporter.Interface
-	gwDeployer        api.GatewayDeployer
-	gateways          int
-	nattDiscoveryPort int64
+	ports         ports.Plan
+	infraID       string
+	cloudPrepare  api.Cloud
+	reporter      submreporter.Interface
+	gwDeployer    api.GatewayDeployer
+	gateways      int
+	instanceType  string
+	projectID     string
+	projects      *cloudresourcemanager.ProjectsService
+	firewalls     *compute.FirewallsService
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
 }
 
 //nolint:revive // Ignore unexported-return - we can't reference the Provider interface here.
-func NewProvider(info *provider.Info) (*gcpProvider, error) {
+func NewProvider(ctx context.Context, info *provider.Info) (*gcpProvider, error) {
 	if info.InfraID == "" {
 		return nil, fmt.Errorf("cluster infraID is empty")
 	}
 
-	instanceType := info.GatewayConfig.GCP.InstanceType
-	if instanceType != "" {
-		instanceType = gwInstanceType
+	instanceType, err := InstanceType(&info.GatewayConfig.GCP)
+	if err != nil {
+		return nil, err
 	}
 
 	if info.Gateways < 1 {
 		return nil, fmt.Errorf("the count of gateways is less than 1")
 	}
 
-	projectID, gcpClient, err := newClient(info.CredentialsSecret)
+	creds, gcpClient, err := newClient(ctx, info.CredentialsSecret)
 	if err != nil {
 		klog.Errorf("Unable to retrieve the gcpclient :%v", err)
 		return nil, err
 	}
 
+	projectID := creds.ProjectID
+	if projectID == "" {
+		return nil, fmt.Errorf("the gcp credentials %s have no project ID: %w", CredentialsSecretKey, provider.ErrInvalidCredentials)
+	}
+
+	resourceManager, err := cloudresourcemanager.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	computeService, err := compute.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
 	cloudInfo := cloudpreparegcp.CloudInfo{
 		InfraID:   info.InfraID,
 		Region:    info.Region,
@@ -75,41 +98,38 @@ func NewProvider(info *provider.Info) (*gcpProvider, error) {
 	gwDeployer := cloudpreparegcp.NewOcpGatewayDeployer(cloudInfo, msDeployer, instanceType, "", k8sClient)
 
 	return &gcpProvider{
-		infraID:           info.InfraID,
-		nattPort:          uint16(info.IPSecNATTPort),
-		cniType:           info.NetworkType,
-		cloudPrepare:      cloudPrepare,
-		gwDeployer:        gwDeployer,
-		reporter:          reporter.NewEventRecorderWrapper("GCPCloudProvider", info.EventRecorder),
-		nattDiscoveryPort: int64(info.NATTDiscoveryPort),
-		gateways:          info.Gateways,
+		ports:         ports.For(&info.SubmarinerConfigSpec, info.NetworkType),
+		infraID:       info.InfraID,
+		cloudPrepare:  cloudPrepare,
+		gwDeployer:    gwDeployer,
+		reporter:      reporter.NewEventRecorderWrapper("GCPCloudProvider", info.EventRecorder, info.StepLog),
+		gateways:      info.Gateways,
+		instanceType:  instanceType,
+		projectID:     projectID,
+		projects:      resourceManager.Projects,
+		firewalls:     computeService.Firewalls,
+		kubeClient:    info.KubeClient,
+		dynamicClient: info.DynamicClient,
 	}, nil
 }
 
 // PrepareSubmarinerClusterEnv prepares submariner cluster environment on GCP
 // The below tasks will be executed
-// 1. create the inbound and outbound firewall rules for submariner, below ports will be opened
-//   - IPsec IKE port (by default 500/UDP)
-//   - NAT traversal port (by default 4500/UDP)
-//   - 4800/UDP port to encapsulate Pod traffic from worker and master nodes to the Submariner Gateway nodes
-func (g *gcpProvider) PrepareSubmarinerClusterEnv() error {
+// 1. create the inbound and outbound firewall rules for submariner, the ports planned for the cable driver will be opened
+func (g *gcpProvider) PrepareSubmarinerClusterEnv(ctx context.Context) error {
 	if err := g.gwDeployer.Deploy(api.GatewayDeployInput{
-		PublicPorts: []api.PortSpec{
-			{Port: g.nattPort, Protocol: "udp"},
-			{Port: uint16(g.nattDiscoveryPort), Protocol: "udp"},
-			// ESP & AH protocols are used for private-ip to private-ip gateway communications
-			{Port: 0, Protocol: "esp"},
-			{Port: 0, Protocol: "ah"},
-		},
-		Gateways: g.gateways,
+		PublicPorts: g.ports.Public,
+		Gateways:    g.gateways,
 	}, g.reporter); err != nil {
 		return err
 	}
 
-	if !strings.EqualFold(g.cniType, cni.OVNKubernetes) {
-		if err := g.cloudPrepare.OpenPorts([]api.PortSpec{
-			{Port: constants.SubmarinerRoutePort, Protocol: "udp"},
-		}, g.reporter); err != nil {
+	if len(g.ports.Internal) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := g.cloudPrepare.OpenPorts(g.ports.Internal, g.reporter); err != nil {
 			return err
 		}
 	}
@@ -119,14 +139,46 @@ func (g *gcpProvider) PrepareSubmarinerClusterEnv() error {
 	return nil
 }
 
+// Plan returns the changes PrepareSubmarinerClusterEnv would make on GCP: the firewall rules opening the public and internal
+// ports, and the gateway MachineSets.
+func (g *gcpProvider) Plan(ctx context.Context) ([]configv1alpha1.PlannedChange, error) {
+	changes := []configv1alpha1.PlannedChange{}
+
+	for _, rule := range g.rules() {
+		changes = append(changes, ports.Change(rule.Kind, rule.Name, rule.Ports))
+	}
+
+	machineSets, err := provider.PlanGatewayMachineSets(ctx, g.dynamicClient, g.infraID, g.gateways, g.instanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, machineSets...), nil
+}
+
+// rules returns the firewall rules opening the public and internal ports.
+func (g *gcpProvider) rules() []ports.Rule {
+	rules := []ports.Rule{{Kind: "FirewallRule", Name: g.infraID + "-submariner-public-ports-ingress", Ports: g.ports.Public}}
+
+	if len(g.ports.Internal) > 0 {
+		rules = append(rules, ports.Rule{Kind: "FirewallRule", Name: g.infraID + "-submariner-internal-ports-ingress", Ports: g.ports.Internal})
+	}
+
+	return rules
+}
+
 // CleanUpSubmarinerClusterEnv clean up submariner cluster environment on GCP after the SubmarinerConfig was deleted
 // 1. delete the inbound and outbound firewall rules to close submariner ports.
-func (g *gcpProvider) CleanUpSubmarinerClusterEnv() error {
+func (g *gcpProvider) CleanUpSubmarinerClusterEnv(ctx context.Context) error {
 	err := g.gwDeployer.Cleanup(g.reporter)
 	if err != nil {
 		return err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	err = g.cloudPrepare.ClosePorts(g.reporter)
 	if err != nil {
 		return err
@@ -137,26 +189,32 @@ func (g *gcpProvider) CleanUpSubmarinerClusterEnv() error {
 	return nil
 }
 
-func newClient(credentialsSecret *corev1.Secret) (string, gcpclient.Interface, error) {
-	authJSON, ok := credentialsSecret.Data[gcpCredentialsName]
-	if !ok {
-		return "", nil, fmt.Errorf("the gcp credentials %s is not in secret %s/%s", gcpCredentialsName,
-			credentialsSecret.Namespace, credentialsSecret.Name)
+// InstanceType returns the machine type of the gateway nodes set in the given GCP configuration, or the default one, and
+// checks that it's a valid machine type name before the MachineSets are created.
+func InstanceType(gcpConfig *configv1alpha1.GCP) (string, error) {
+	if gcpConfig.InstanceType == "" {
+		return gwInstanceType, nil
+	}
+
+	if !machineTypeRegexp.MatchString(gcpConfig.InstanceType) {
+		return "", fmt.Errorf("the gateway instance type %q isn't a valid GCP machine type, such as %q", gcpConfig.InstanceType,
+			gwInstanceType)
 	}
 
-	ctx := context.TODO()
+	return gcpConfig.InstanceType, nil
+}
 
-	// since we're using a single creds var, we should specify all the required scopes when initializing
-	creds, err := google.CredentialsFromJSON(ctx, authJSON, dns.CloudPlatformScope)
+func newClient(ctx context.Context, credentialsSecret *corev1.Secret) (*google.Credentials, gcpclient.Interface, error) {
+	creds, err := NewCredentials(ctx, credentialsSecret)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
 	// Create a GCP client with the credentials.
 	computeClient, err := gcpclient.NewClient(creds.ProjectID, []option.ClientOption{option.WithCredentials(creds)})
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	return creds.ProjectID, computeClient, nil
+	return creds, computeClient, nil
 }
@@ -0,0 +1,55 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// requiredPermissions are the IAM permissions needed on the project to open the Submariner ports and to deploy the gateway nodes.
+var requiredPermissions = []string{
+	"compute.firewalls.create",
+	"compute.firewalls.delete",
+	"compute.firewalls.get",
+	"compute.firewalls.update",
+	"compute.instances.get",
+	"compute.instances.list",
+	"compute.networks.updatePolicy",
+}
+
+// CheckPermissions checks that the GCP credentials are granted the IAM permissions to describe the instances and to manage the
+// firewall rules of the project, and that the MachineSets of the gateway nodes can be created.
+func (g *gcpProvider) CheckPermissions(ctx context.Context) ([]string, error) {
+	missing, err := CheckIAMPermissions(ctx, g.projects, g.projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	machineSetMissing, err := provider.CheckMachineSetPermissions(ctx, g.kubeClient)
+
+	return append(missing, machineSetMissing...), err
+}
+
+// CheckIAMPermissions returns the IAM permissions needed to prepare the cluster which aren't granted on the given project.
+func CheckIAMPermissions(ctx context.Context, projects *cloudresourcemanager.ProjectsService, projectID string) ([]string, error) {
+	response, err := projects.TestIamPermissions(projectID, &cloudresourcemanager.TestIamPermissionsRequest{
+		Permissions: requiredPermissions,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error testing the IAM permissions on project %q", projectID)
+	}
+
+	granted := sets.New(response.Permissions...)
+	missing := []string{}
+
+	for _, permission := range requiredPermissions {
+		if !granted.Has(permission) {
+			missing = append(missing, permission)
+		}
+	}
+
+	return missing, nil
+}
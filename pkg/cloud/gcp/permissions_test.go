@@ -0,0 +1,109 @@
+package gcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/gcp"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/option"
+)
+
+const projectID = "test-project"
+
+var allPermissions = []string{
+	"compute.firewalls.create",
+	"compute.firewalls.delete",
+	"compute.firewalls.get",
+	"compute.firewalls.update",
+	"compute.instances.get",
+	"compute.instances.list",
+	"compute.networks.updatePolicy",
+}
+
+var _ = Describe("CheckIAMPermissions", func() {
+	var (
+		granted    []string
+		statusCode int
+		projects   *cloudresourcemanager.ProjectsService
+	)
+
+	BeforeEach(func() {
+		granted = allPermissions
+		statusCode = http.StatusOK
+
+		// The stubbed Resource Manager API grants the tested permissions which are in granted.
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/v1/projects/" + projectID + ":testIamPermissions"))
+
+			if statusCode != http.StatusOK {
+				w.WriteHeader(statusCode)
+				return
+			}
+
+			request := &cloudresourcemanager.TestIamPermissionsRequest{}
+			Expect(json.NewDecoder(r.Body).Decode(request)).To(Succeed())
+
+			response := &cloudresourcemanager.TestIamPermissionsResponse{}
+
+			for _, permission := range request.Permissions {
+				for _, grantedPermission := range granted {
+					if permission == grantedPermission {
+						response.Permissions = append(response.Permissions, permission)
+					}
+				}
+			}
+
+			Expect(json.NewEncoder(w).Encode(response)).To(Succeed())
+		}))
+		DeferCleanup(server.Close)
+
+		service, err := cloudresourcemanager.NewService(context.TODO(), option.WithEndpoint(server.URL),
+			option.WithoutAuthentication())
+		Expect(err).To(Succeed())
+
+		projects = service.Projects
+	})
+
+	DescribeTable("the granted permissions",
+		func(grantedPermissions, expected []string) {
+			granted = grantedPermissions
+
+			missing, err := gcp.CheckIAMPermissions(context.TODO(), projects, projectID)
+			Expect(err).To(Succeed())
+			Expect(missing).To(Equal(expected))
+		},
+		Entry("all", allPermissions, []string{}),
+		Entry("none", nil, allPermissions),
+		Entry("some", []string{"compute.firewalls.get", "compute.instances.get", "compute.instances.list"},
+			[]string{"compute.firewalls.create", "compute.firewalls.delete", "compute.firewalls.update", "compute.networks.updatePolicy"}),
+	)
+
+	When("the credentials are rejected", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusUnauthorized
+		})
+
+		It("should return an invalid credentials error", func() {
+			_, err := gcp.CheckIAMPermissions(context.TODO(), projects, projectID)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+
+	When("testing the permissions fails", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusInternalServerError
+		})
+
+		It("should return an error", func() {
+			_, err := gcp.CheckIAMPermissions(context.TODO(), projects, projectID)
+			Expect(err).To(HaveOccurred())
+			Expect(provider.IsInvalidCredentials(err)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,296 @@
+package gke
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/gcp"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const publicCIDR = "0.0.0.0/0"
+
+// ComputeAPI is the subset of the GCP compute API used to open the Submariner ports on the node firewalls.
+type ComputeAPI interface {
+	GetInstance(ctx context.Context, project, zone, name string) (*compute.Instance, error)
+	// ListInstances returns the instances of the given project matching the given filter, in all zones.
+	ListInstances(ctx context.Context, project, filter string) ([]*compute.Instance, error)
+	SetInstanceTags(ctx context.Context, project, zone, name string, tags *compute.Tags) error
+	GetFirewall(ctx context.Context, project, name string) (*compute.Firewall, error)
+	InsertFirewall(ctx context.Context, project string, rule *compute.Firewall) error
+	UpdateFirewall(ctx context.Context, project string, rule *compute.Firewall) error
+	DeleteFirewall(ctx context.Context, project, name string) error
+}
+
+type computeClient struct {
+	service *compute.Service
+}
+
+type firewall struct {
+	client      ComputeAPI
+	project     string
+	clusterName string
+}
+
+// NewFirewall returns a Firewall for the GKE cluster described by the given info, using its GCP service account. The given
+// context is used to create the compute client.
+func NewFirewall(ctx context.Context, info *provider.Info) (managed.Firewall, error) {
+	creds, err := gcp.NewCredentials(ctx, info.CredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if creds.ProjectID == "" {
+		return nil, fmt.Errorf("the gcp credentials don't specify a project: %w", provider.ErrInvalidCredentials)
+	}
+
+	service, err := compute.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFirewallWithClient(&computeClient{service: service}, creds.ProjectID, info.ClusterName), nil
+}
+
+// NewFirewallWithClient returns a Firewall which manages VPC firewall rules of the given project, using the given compute
+// client. The public ports only target the gateway instances, tagged with a network tag named after the cluster, while the
+// internal ports target the network tags of their node pools.
+func NewFirewallWithClient(client ComputeAPI, project, clusterName string) managed.Firewall {
+	return &firewall{client: client, project: project, clusterName: clusterName}
+}
+
+// OpenPorts tags the gateway instances, removes the tag from the instances which are no longer gateways, and creates or
+// updates the firewall rules for the given ports.
+func (f *firewall) OpenPorts(ctx context.Context, gateways []corev1.Node, ports managed.Ports) error {
+	gatewayTag := managed.ResourceName(f.clusterName, "gateway")
+
+	network, nodeTags, err := f.tagGateways(ctx, gateways, gatewayTag)
+	if err != nil {
+		return err
+	}
+
+	if err := f.untagInstances(ctx, gatewayTag, gateways); err != nil {
+		return err
+	}
+
+	if len(gateways) == 0 {
+		return nil
+	}
+
+	if len(ports.Public) > 0 {
+		err = f.ensureRule(ctx, &compute.Firewall{
+			Name:         managed.ResourceName(f.clusterName, "public"),
+			Network:      network,
+			Direction:    "INGRESS",
+			Allowed:      allowedFor(ports.Public),
+			SourceRanges: []string{publicCIDR},
+			TargetTags:   []string{gatewayTag},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(ports.Internal) > 0 {
+		return f.ensureRule(ctx, &compute.Firewall{
+			Name:       managed.ResourceName(f.clusterName, "internal"),
+			Network:    network,
+			Direction:  "INGRESS",
+			Allowed:    allowedFor(ports.Internal),
+			SourceTags: nodeTags,
+			TargetTags: nodeTags,
+		})
+	}
+
+	return nil
+}
+
+// ClosePorts deletes the Submariner rules by name and removes the gateway tag from the instances, they're removed even if no
+// node is labeled as gateway anymore.
+func (f *firewall) ClosePorts(ctx context.Context, _ []corev1.Node, _ managed.Ports) error {
+	for _, name := range []string{managed.ResourceName(f.clusterName, "public"), managed.ResourceName(f.clusterName, "internal")} {
+		err := f.client.DeleteFirewall(ctx, f.project, name)
+		if err != nil && !isNotFound(err) {
+			return errors.Wrapf(err, "error deleting firewall rule %q", name)
+		}
+	}
+
+	return f.untagInstances(ctx, managed.ResourceName(f.clusterName, "gateway"), nil)
+}
+
+func (f *firewall) ensureRule(ctx context.Context, rule *compute.Firewall) error {
+	_, err := f.client.GetFirewall(ctx, f.project, rule.Name)
+	if isNotFound(err) {
+		return errors.Wrapf(f.client.InsertFirewall(ctx, f.project, rule), "error creating firewall rule %q", rule.Name)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving firewall rule %q", rule.Name)
+	}
+
+	return errors.Wrapf(f.client.UpdateFirewall(ctx, f.project, rule), "error updating firewall rule %q", rule.Name)
+}
+
+// tagGateways adds the gateway tag to the gateway instances, and returns their network and the network tags of their node
+// pools.
+func (f *firewall) tagGateways(ctx context.Context, gateways []corev1.Node, gatewayTag string) (string, []string, error) {
+	var network string
+
+	tags := sets.New[string]()
+
+	for i := range gateways {
+		instanceProject, zone, name, err := parseProviderID(&gateways[i])
+		if err != nil {
+			return "", nil, err
+		}
+
+		instance, err := f.client.GetInstance(ctx, instanceProject, zone, name)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error retrieving instance %q", name)
+		}
+
+		if instance.Tags == nil || len(instance.Tags.Items) == 0 {
+			return "", nil, fmt.Errorf("instance %q has no network tags", name)
+		}
+
+		if len(instance.NetworkInterfaces) == 0 {
+			return "", nil, fmt.Errorf("instance %q has no network interface", name)
+		}
+
+		network = instance.NetworkInterfaces[0].Network
+		tags.Insert(instance.Tags.Items...)
+
+		if slices.Contains(instance.Tags.Items, gatewayTag) {
+			continue
+		}
+
+		err = f.client.SetInstanceTags(ctx, instanceProject, zone, name, &compute.Tags{
+			Items:       append(slices.Clone(instance.Tags.Items), gatewayTag),
+			Fingerprint: instance.Tags.Fingerprint,
+		})
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error tagging instance %q", name)
+		}
+	}
+
+	return network, sets.List(tags.Delete(gatewayTag)), nil
+}
+
+// untagInstances removes the gateway tag from the instances of the project which aren't one of the given gateways.
+func (f *firewall) untagInstances(ctx context.Context, gatewayTag string, gateways []corev1.Node) error {
+	instances, err := f.client.ListInstances(ctx, f.project, "tags.items="+gatewayTag)
+	if err != nil {
+		return errors.Wrapf(err, "error listing the instances tagged %q", gatewayTag)
+	}
+
+	names := sets.New[string]()
+
+	for i := range gateways {
+		if _, _, name, err := parseProviderID(&gateways[i]); err == nil {
+			names.Insert(name)
+		}
+	}
+
+	for _, instance := range instances {
+		if names.Has(instance.Name) || instance.Tags == nil {
+			continue
+		}
+
+		err := f.client.SetInstanceTags(ctx, f.project, path.Base(instance.Zone), instance.Name, &compute.Tags{
+			Items: slices.DeleteFunc(slices.Clone(instance.Tags.Items), func(tag string) bool {
+				return tag == gatewayTag
+			}),
+			Fingerprint: instance.Tags.Fingerprint,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error removing the gateway tag from instance %q", instance.Name)
+		}
+	}
+
+	return nil
+}
+
+// parseProviderID extracts the project, zone and instance name from a node provider ID of the form
+// gce://<project>/<zone>/<name>.
+func parseProviderID(node *corev1.Node) (string, string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(node.Spec.ProviderID, "gce://"), "/")
+	if !strings.HasPrefix(node.Spec.ProviderID, "gce://") || len(parts) != 3 {
+		return "", "", "", fmt.Errorf("node %q has an unexpected provider ID %q", node.Name, node.Spec.ProviderID)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+func allowedFor(ports []cpapi.PortSpec) []*compute.FirewallAllowed {
+	allowed := []*compute.FirewallAllowed{}
+
+	for _, port := range ports {
+		allowed = append(allowed, &compute.FirewallAllowed{
+			IPProtocol: port.Protocol,
+			Ports:      []string{strconv.Itoa(int(port.Port))},
+		})
+	}
+
+	return allowed
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+func (c *computeClient) GetInstance(ctx context.Context, project, zone, name string) (*compute.Instance, error) {
+	return c.service.Instances.Get(project, zone, name).Context(ctx).Do()
+}
+
+func (c *computeClient) ListInstances(ctx context.Context, project, filter string) ([]*compute.Instance, error) {
+	instances := []*compute.Instance{}
+
+	err := c.service.Instances.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.InstanceAggregatedList) error {
+		for _, scoped := range list.Items {
+			instances = append(instances, scoped.Instances...)
+		}
+
+		return nil
+	})
+
+	return instances, err
+}
+
+func (c *computeClient) SetInstanceTags(ctx context.Context, project, zone, name string, tags *compute.Tags) error {
+	_, err := c.service.Instances.SetTags(project, zone, name, tags).Context(ctx).Do()
+	return err
+}
+
+func (c *computeClient) GetFirewall(ctx context.Context, project, name string) (*compute.Firewall, error) {
+	return c.service.Firewalls.Get(project, name).Context(ctx).Do()
+}
+
+func (c *computeClient) InsertFirewall(ctx context.Context, project string, rule *compute.Firewall) error {
+	_, err := c.service.Firewalls.Insert(project, rule).Context(ctx).Do()
+	return err
+}
+
+func (c *computeClient) UpdateFirewall(ctx context.Context, project string, rule *compute.Firewall) error {
+	_, err := c.service.Firewalls.Update(project, rule.Name, rule).Context(ctx).Do()
+	return err
+}
+
+func (c *computeClient) DeleteFirewall(ctx context.Context, project, name string) error {
+	_, err := c.service.Firewalls.Delete(project, name).Context(ctx).Do()
+	return err
+}
@@ -0,0 +1,13 @@
+package gke_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGKE(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GKE Cloud Provider Suite")
+}
@@ -0,0 +1,278 @@
+package gke_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/gke"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	project     = "test-project"
+	clusterName = "test-cluster"
+	gatewayTag  = "submariner-test-cluster-gateway"
+)
+
+type fakeCompute struct {
+	instances map[string]*compute.Instance
+	firewalls map[string]*compute.Firewall
+	insertErr error
+}
+
+func (f *fakeCompute) GetInstance(_ context.Context, instanceProject, zone, name string) (*compute.Instance, error) {
+	instance, ok := f.instances[instanceProject+"/"+zone+"/"+name]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+
+	return instance, nil
+}
+
+func (f *fakeCompute) ListInstances(_ context.Context, instanceProject, filter string) ([]*compute.Instance, error) {
+	tag, found := strings.CutPrefix(filter, "tags.items=")
+	Expect(found).To(BeTrue(), "unexpected filter %q", filter)
+
+	instances := []*compute.Instance{}
+
+	for key, instance := range f.instances {
+		if strings.HasPrefix(key, instanceProject+"/") && instance.Tags != nil && slices.Contains(instance.Tags.Items, tag) {
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances, nil
+}
+
+func (f *fakeCompute) SetInstanceTags(_ context.Context, instanceProject, zone, name string, tags *compute.Tags) error {
+	instance, ok := f.instances[instanceProject+"/"+zone+"/"+name]
+	if !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+
+	Expect(tags.Fingerprint).To(Equal(instance.Tags.Fingerprint))
+	instance.Tags = tags
+
+	return nil
+}
+
+func (f *fakeCompute) GetFirewall(_ context.Context, firewallProject, name string) (*compute.Firewall, error) {
+	rule, ok := f.firewalls[firewallProject+"/"+name]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+
+	return rule, nil
+}
+
+func (f *fakeCompute) InsertFirewall(_ context.Context, firewallProject string, rule *compute.Firewall) error {
+	if f.insertErr != nil {
+		return f.insertErr
+	}
+
+	key := firewallProject + "/" + rule.Name
+	if _, ok := f.firewalls[key]; ok {
+		return fmt.Errorf("firewall rule %q already exists", rule.Name)
+	}
+
+	f.firewalls[key] = rule
+
+	return nil
+}
+
+func (f *fakeCompute) UpdateFirewall(_ context.Context, firewallProject string, rule *compute.Firewall) error {
+	key := firewallProject + "/" + rule.Name
+	if _, ok := f.firewalls[key]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+
+	f.firewalls[key] = rule
+
+	return nil
+}
+
+func (f *fakeCompute) DeleteFirewall(_ context.Context, firewallProject, name string) error {
+	key := firewallProject + "/" + name
+	if _, ok := f.firewalls[key]; !ok {
+		return &googleapi.Error{Code: http.StatusNotFound}
+	}
+
+	delete(f.firewalls, key)
+
+	return nil
+}
+
+var _ = Describe("GKE firewall", func() {
+	var (
+		client   *fakeCompute
+		firewall managed.Firewall
+		gateways []corev1.Node
+		ports    managed.Ports
+	)
+
+	BeforeEach(func() {
+		client = &fakeCompute{
+			instances: map[string]*compute.Instance{
+				project + "/us-east1-b/instance-1": newInstance("instance-1"),
+				project + "/us-east1-b/instance-2": newInstance("instance-2"),
+			},
+			firewalls: map[string]*compute.Firewall{},
+		}
+
+		firewall = gke.NewFirewallWithClient(client, project, clusterName)
+
+		gateways = []corev1.Node{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec:       corev1.NodeSpec{ProviderID: "gce://" + project + "/us-east1-b/instance-1"},
+		}}
+
+		ports = managed.Ports{
+			Public:   []cpapi.PortSpec{{Port: 4500, Protocol: "udp"}, {Port: 4490, Protocol: "udp"}},
+			Internal: []cpapi.PortSpec{{Port: 4800, Protocol: "udp"}},
+		}
+	})
+
+	Context("OpenPorts", func() {
+		It("should create the firewall rules, with the public ports only targeting the gateway instances", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.firewalls).To(HaveLen(2))
+			Expect(client.tagsOf("instance-1")).To(Equal([]string{"gke-test-node", gatewayTag}))
+			Expect(client.tagsOf("instance-2")).To(Equal([]string{"gke-test-node"}))
+
+			public := client.firewalls[project+"/submariner-"+clusterName+"-public"]
+			Expect(public).ToNot(BeNil())
+			Expect(public.Network).To(Equal("test-network"))
+			Expect(public.SourceRanges).To(Equal([]string{"0.0.0.0/0"}))
+			Expect(public.TargetTags).To(Equal([]string{gatewayTag}))
+			Expect(public.Allowed).To(HaveLen(2))
+			Expect(public.Allowed[0].Ports).To(Equal([]string{"4500"}))
+
+			internal := client.firewalls[project+"/submariner-"+clusterName+"-internal"]
+			Expect(internal).ToNot(BeNil())
+			Expect(internal.SourceTags).To(Equal([]string{"gke-test-node"}))
+			Expect(internal.TargetTags).To(Equal([]string{"gke-test-node"}))
+			Expect(internal.Allowed).To(HaveLen(1))
+			Expect(internal.Allowed[0].Ports).To(Equal([]string{"4800"}))
+		})
+
+		It("should update the existing firewall rules", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+			ports.Public = ports.Public[:1]
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.firewalls).To(HaveLen(2))
+			Expect(client.firewalls[project+"/submariner-"+clusterName+"-public"].Allowed).To(HaveLen(1))
+		})
+
+		When("a node isn't a gateway anymore", func() {
+			It("should remove the gateway tag from its instance", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+				gateways[0].Spec.ProviderID = "gce://" + project + "/us-east1-b/instance-2"
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.tagsOf("instance-1")).To(Equal([]string{"gke-test-node"}))
+				Expect(client.tagsOf("instance-2")).To(Equal([]string{"gke-test-node", gatewayTag}))
+			})
+		})
+
+		When("the cluster name is long", func() {
+			BeforeEach(func() {
+				firewall = gke.NewFirewallWithClient(client, project, strings.Repeat("a", 63))
+			})
+
+			It("should limit the names of the firewall rules and of the tag to 63 characters", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+				for _, rule := range client.firewalls {
+					Expect(len(rule.Name)).To(BeNumerically("<=", 63))
+				}
+
+				for _, tag := range client.tagsOf("instance-1") {
+					Expect(len(tag)).To(BeNumerically("<=", 63))
+				}
+			})
+		})
+
+		When("creating a firewall rule fails", func() {
+			BeforeEach(func() {
+				client.insertErr = errors.New("fake error")
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+
+		When("a node has an unexpected provider ID", func() {
+			BeforeEach(func() {
+				gateways[0].Spec.ProviderID = "aws:///us-east-1a/i-1"
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+
+		When("an instance has no network tags", func() {
+			BeforeEach(func() {
+				client.instances[project+"/us-east1-b/instance-1"].Tags = nil
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+	})
+
+	Context("ClosePorts", func() {
+		BeforeEach(func() {
+			client.firewalls[project+"/other"] = &compute.Firewall{Name: "other"}
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+		})
+
+		It("should only delete the Submariner firewall rules", func() {
+			Expect(firewall.ClosePorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.firewalls).To(HaveLen(1))
+			Expect(client.firewalls).To(HaveKey(project + "/other"))
+		})
+
+		When("no node is labeled as gateway anymore", func() {
+			It("should still delete the Submariner firewall rules and the gateway tag", func() {
+				Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+				Expect(client.firewalls).To(HaveLen(1))
+				Expect(client.tagsOf("instance-1")).To(Equal([]string{"gke-test-node"}))
+			})
+		})
+
+		When("the firewall rules were already deleted", func() {
+			It("should succeed", func() {
+				Expect(firewall.ClosePorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(firewall.ClosePorts(context.TODO(), gateways, ports)).To(Succeed())
+			})
+		})
+	})
+})
+
+func (f *fakeCompute) tagsOf(name string) []string {
+	return f.instances[project+"/us-east1-b/"+name].Tags.Items
+}
+
+func newInstance(name string) *compute.Instance {
+	return &compute.Instance{
+		Name:              name,
+		Zone:              "https://www.googleapis.com/compute/v1/projects/" + project + "/zones/us-east1-b",
+		Tags:              &compute.Tags{Items: []string{"gke-test-node"}, Fingerprint: "fingerprint-" + name},
+		NetworkInterfaces: []*compute.NetworkInterface{{Network: "test-network"}},
+	}
+}
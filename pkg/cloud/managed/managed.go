@@ -0,0 +1,161 @@
+package managed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"github.com/stolostron/submariner-addon/pkg/cloud/reporter"
+	submreporter "github.com/submariner-io/admiral/pkg/reporter"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	gatewayLabelSelector = "submariner.io/gateway=true"
+	// maxNameLength is the maximum length of the GCE firewall rule and network tag names, and of the IBM Cloud VPC resource names.
+	maxNameLength = 63
+	hashLength    = 8
+)
+
+// Ports lists the ports that must be opened for the gateway nodes.
+type Ports struct {
+	// Public ports must be reachable from the gateways of the other clusters.
+	Public []cpapi.PortSpec
+	// Internal ports only need to be reachable from the other nodes of the cluster.
+	Internal []cpapi.PortSpec
+}
+
+// Firewall manages the cloud firewall rules that protect the nodes of a managed cluster.
+type Firewall interface {
+	// OpenPorts ensures the given ports are open on the firewalls protecting the given gateway nodes.
+	OpenPorts(ctx context.Context, gateways []corev1.Node, ports Ports) error
+	// ClosePorts removes the rules created by OpenPorts, including those of nodes which are no longer gateways.
+	ClosePorts(ctx context.Context, gateways []corev1.Node, ports Ports) error
+}
+
+// ResourceName returns the name of a cloud resource created for the given cluster, submariner-<cluster>-<suffix>. Names
+// longer than 63 characters are shortened by truncating the cluster name and appending a hash of the full name.
+func ResourceName(clusterName, suffix string) string {
+	name := fmt.Sprintf("submariner-%s-%s", strings.ReplaceAll(clusterName, ".", "-"), suffix)
+	if len(name) <= maxNameLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	prefix := strings.TrimSuffix(name[:maxNameLength-len(suffix)-hashLength-2], "-")
+
+	return fmt.Sprintf("%s-%s-%s", prefix, hex.EncodeToString(hash[:])[:hashLength], suffix)
+}
+
+// managedProvider prepares managed clusters, where dedicated gateway nodes can't be deployed with MachineSets. The
+// gateways are existing nodes labeled by the config controller, the provider only opens the Submariner ports for them.
+type managedProvider struct {
+	name       string
+	kubeClient kubernetes.Interface
+	firewall   Firewall
+	reporter   submreporter.Interface
+	ports      Ports
+}
+
+//nolint:revive // Ignore unexported-return - we can't reference the Provider interface here.
+func NewProvider(name string, info *provider.Info, firewall Firewall) *managedProvider {
+	plan := ports.For(&info.SubmarinerConfigSpec, info.NetworkType)
+
+	return &managedProvider{
+		name:       name,
+		kubeClient: info.KubeClient,
+		firewall:   firewall,
+		reporter:   reporter.NewEventRecorderWrapper(name+"CloudProvider", info.EventRecorder, info.StepLog),
+		// The firewalls of the managed clusters only open ports, the IPsec connections are then always encapsulated in UDP.
+		ports: Ports{
+			Public:   ports.PortsOnly(plan.Public),
+			Internal: plan.Internal,
+		},
+	}
+}
+
+// PrepareSubmarinerClusterEnv opens the Submariner ports for the nodes labeled as gateways.
+func (m *managedProvider) PrepareSubmarinerClusterEnv(ctx context.Context) error {
+	gateways, err := m.listGateways(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(gateways) == 0 {
+		return fmt.Errorf("there are no nodes labeled as gateways")
+	}
+
+	m.reporter.Start("Opening the Submariner ports for %d gateway node(s) on %s", len(gateways), m.name)
+
+	if err := m.firewall.OpenPorts(ctx, gateways, m.ports); err != nil {
+		m.reporter.Failure("Failed to open the Submariner ports: %v", err)
+		return err
+	}
+
+	m.reporter.Success("The Submariner cluster environment has been set up on %s", m.name)
+
+	return nil
+}
+
+// Plan returns the firewall rules PrepareSubmarinerClusterEnv would create for each node labeled as gateway.
+func (m *managedProvider) Plan(ctx context.Context) ([]configv1alpha1.PlannedChange, error) {
+	gateways, err := m.listGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(gateways))
+	for i := range gateways {
+		names[i] = gateways[i].Name
+	}
+
+	return m.PlanForGateways(ctx, names)
+}
+
+// PlanForGateways returns the firewall rules PrepareSubmarinerClusterEnv would create for each of the given gateway nodes,
+// whether they are labeled yet or not.
+func (m *managedProvider) PlanForGateways(_ context.Context, gateways []string) ([]configv1alpha1.PlannedChange, error) {
+	changes := make([]configv1alpha1.PlannedChange, len(gateways))
+
+	for i := range gateways {
+		changes[i] = ports.Change("FirewallRule", gateways[i], append(append([]cpapi.PortSpec{}, m.ports.Public...),
+			m.ports.Internal...))
+		changes[i].Description += " for the gateway node on " + m.name
+	}
+
+	return changes, nil
+}
+
+// CleanUpSubmarinerClusterEnv closes the Submariner ports opened for the nodes labeled as gateways.
+func (m *managedProvider) CleanUpSubmarinerClusterEnv(ctx context.Context) error {
+	gateways, err := m.listGateways(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := m.firewall.ClosePorts(ctx, gateways, m.ports); err != nil {
+		m.reporter.Failure("Failed to close the Submariner ports: %v", err)
+		return err
+	}
+
+	m.reporter.Success("The Submariner cluster environment has been cleaned up on %s", m.name)
+
+	return nil
+}
+
+func (m *managedProvider) listGateways(ctx context.Context) ([]corev1.Node, error) {
+	nodes, err := m.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: gatewayLabelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.Items, nil
+}
@@ -0,0 +1,186 @@
+package managed_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/library-go/pkg/operator/events"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	"github.com/submariner-io/submariner/pkg/cni"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeFake "k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeFirewall struct {
+	opened  []string
+	closed  []string
+	ports   managed.Ports
+	failErr error
+}
+
+func (f *fakeFirewall) OpenPorts(_ context.Context, gateways []corev1.Node, ports managed.Ports) error {
+	f.ports = ports
+
+	for i := range gateways {
+		f.opened = append(f.opened, gateways[i].Name)
+	}
+
+	return f.failErr
+}
+
+func (f *fakeFirewall) ClosePorts(_ context.Context, gateways []corev1.Node, _ managed.Ports) error {
+	for i := range gateways {
+		f.closed = append(f.closed, gateways[i].Name)
+	}
+
+	return f.failErr
+}
+
+var _ = Describe("Managed provider", func() {
+	var (
+		firewall   *fakeFirewall
+		info       *provider.Info
+		kubeClient *kubeFake.Clientset
+	)
+
+	BeforeEach(func() {
+		firewall = &fakeFirewall{}
+		kubeClient = kubeFake.NewSimpleClientset(newNode("node-1", true), newNode("node-2", false))
+
+		info = &provider.Info{
+			KubeClient:    kubeClient,
+			EventRecorder: events.NewLoggingEventRecorder("test"),
+			SubmarinerConfigSpec: configv1alpha1.SubmarinerConfigSpec{
+				IPSecNATTPort:     constants.SubmarinerNatTPort,
+				NATTDiscoveryPort: constants.SubmarinerNatTDiscoveryPort,
+			},
+		}
+	})
+
+	Context("PrepareSubmarinerClusterEnv", func() {
+		It("should open the ports for the gateway nodes", func() {
+			Expect(managed.NewProvider("EKS", info, firewall).PrepareSubmarinerClusterEnv(context.TODO())).To(Succeed())
+			Expect(firewall.opened).To(Equal([]string{"node-1"}))
+			Expect(firewall.ports.Public).To(Equal([]cpapi.PortSpec{
+				{Port: uint16(constants.SubmarinerNatTPort), Protocol: "udp"},
+				{Port: uint16(constants.SubmarinerNatTDiscoveryPort), Protocol: "udp"},
+				{Port: uint16(constants.SubmarinerIKEPort), Protocol: "udp"},
+			}))
+			Expect(firewall.ports.Internal).To(Equal([]cpapi.PortSpec{{Port: constants.SubmarinerRoutePort, Protocol: "udp"}}))
+		})
+
+		When("the CNI is OVNKubernetes", func() {
+			BeforeEach(func() {
+				info.NetworkType = cni.OVNKubernetes
+			})
+
+			It("should not open the route port", func() {
+				Expect(managed.NewProvider("EKS", info, firewall).PrepareSubmarinerClusterEnv(context.TODO())).To(Succeed())
+				Expect(firewall.ports.Internal).To(BeEmpty())
+			})
+		})
+
+		When("no node is labeled as a gateway", func() {
+			BeforeEach(func() {
+				Expect(kubeClient.CoreV1().Nodes().Delete(context.TODO(), "node-1", metav1.DeleteOptions{})).To(Succeed())
+			})
+
+			It("should return an error", func() {
+				Expect(managed.NewProvider("EKS", info, firewall).PrepareSubmarinerClusterEnv(context.TODO())).ToNot(Succeed())
+				Expect(firewall.opened).To(BeEmpty())
+			})
+		})
+
+		When("the firewall fails", func() {
+			BeforeEach(func() {
+				firewall.failErr = errors.New("fake error")
+			})
+
+			It("should return an error", func() {
+				Expect(managed.NewProvider("EKS", info, firewall).PrepareSubmarinerClusterEnv(context.TODO())).ToNot(Succeed())
+			})
+		})
+	})
+
+	Context("CleanUpSubmarinerClusterEnv", func() {
+		It("should close the ports for the gateway nodes", func() {
+			Expect(managed.NewProvider("EKS", info, firewall).CleanUpSubmarinerClusterEnv(context.TODO())).To(Succeed())
+			Expect(firewall.closed).To(Equal([]string{"node-1"}))
+		})
+	})
+
+	Context("Plan", func() {
+		It("should plan the firewall rules of the gateway nodes without opening the ports", func() {
+			changes, err := managed.NewProvider("EKS", info, firewall).Plan(context.TODO())
+			Expect(err).To(Succeed())
+			Expect(changes).To(Equal([]configv1alpha1.PlannedChange{{
+				Action:      configv1alpha1.PlannedChangeActionCreate,
+				Kind:        "FirewallRule",
+				Name:        "node-1",
+				Description: "Open 4500/udp, 4900/udp, 500/udp, 4800/udp for the gateway node on EKS",
+			}}))
+			Expect(firewall.opened).To(BeEmpty())
+		})
+	})
+
+	Context("PlanForGateways", func() {
+		It("should plan the firewall rules of the given nodes without labeling them", func() {
+			changes, err := managed.NewProvider("EKS", info, firewall).PlanForGateways(context.TODO(), []string{"node-2"})
+			Expect(err).To(Succeed())
+			Expect(changes).To(Equal([]configv1alpha1.PlannedChange{{
+				Action:      configv1alpha1.PlannedChangeActionCreate,
+				Kind:        "FirewallRule",
+				Name:        "node-2",
+				Description: "Open 4500/udp, 4900/udp, 500/udp, 4800/udp for the gateway node on EKS",
+			}}))
+			Expect(firewall.opened).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("ResourceName", func() {
+	It("should name the resource after the cluster", func() {
+		Expect(managed.ResourceName("east", "public")).To(Equal("submariner-east-public"))
+	})
+
+	When("the cluster name contains dots", func() {
+		It("should replace them", func() {
+			Expect(managed.ResourceName("east.example", "public")).To(Equal("submariner-east-example-public"))
+		})
+	})
+
+	When("the name is longer than 63 characters", func() {
+		It("should truncate it and keep the names of different clusters distinct", func() {
+			long := strings.Repeat("a", 60)
+
+			name := managed.ResourceName(long+"-1", "internal")
+			Expect(len(name)).To(BeNumerically("<=", 63))
+			Expect(name).To(HavePrefix("submariner-aaa"))
+			Expect(name).To(HaveSuffix("-internal"))
+			Expect(name).ToNot(Equal(managed.ResourceName(long+"-2", "internal")))
+		})
+	})
+})
+
+func newNode(name string, isGateway bool) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{},
+		},
+	}
+
+	if isGateway {
+		node.Labels["submariner.io/gateway"] = "true"
+	}
+
+	return node
+}
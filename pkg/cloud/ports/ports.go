@@ -0,0 +1,221 @@
+package ports
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	"github.com/submariner-io/submariner/pkg/cni"
+)
+
+const (
+	CableDriverLibreswan  = "libreswan"
+	CableDriverStrongswan = "strongswan"
+	CableDriverWireGuard  = "wireguard"
+	CableDriverVXLAN      = "vxlan"
+
+	ProtocolUDP = "udp"
+	ProtocolESP = "esp"
+	ProtocolAH  = "ah"
+	// ProtocolAny matches all the protocols in the ranges of opened ports.
+	ProtocolAny = "*"
+)
+
+// Plan lists the ports that must be opened for the gateway nodes.
+type Plan struct {
+	// Public ports must be reachable from the gateways of the other clusters.
+	Public []cpapi.PortSpec
+	// Internal ports only need to be reachable from the other nodes of the cluster.
+	Internal []cpapi.PortSpec
+}
+
+// For returns the ports required by the gateways deployed with the given configuration on a cluster with the given CNI:
+//   - every cable driver tunnels over the UDP port set as IPSecNATTPort, and the gateways discover the NAT between them
+//     with the UDP NATTDiscoveryPort;
+//   - the IPsec cable drivers also negotiate on the UDP IPSecIKEPort and, for private-ip to private-ip connections, use
+//     the ESP and AH protocols. A load balancer only forwards the tunnel and NAT discovery ports though, the IPsec
+//     connections are then always encapsulated in UDP;
+//   - unless the CNI is OVNKubernetes, the route agents reach the gateways with VXLAN over an internal UDP port.
+func For(spec *configv1alpha1.SubmarinerConfigSpec, networkType string) Plan {
+	plan := Plan{
+		Public: []cpapi.PortSpec{
+			{Port: portOrDefault(spec.IPSecNATTPort, constants.SubmarinerNatTPort), Protocol: ProtocolUDP},
+			{Port: portOrDefault(spec.NATTDiscoveryPort, constants.SubmarinerNatTDiscoveryPort), Protocol: ProtocolUDP},
+		},
+	}
+
+	if isIPsec(spec.CableDriver) && !spec.LoadBalancerEnable {
+		plan.Public = append(plan.Public,
+			cpapi.PortSpec{Port: portOrDefault(spec.IPSecIKEPort, constants.SubmarinerIKEPort), Protocol: ProtocolUDP},
+			cpapi.PortSpec{Port: 0, Protocol: ProtocolESP},
+			cpapi.PortSpec{Port: 0, Protocol: ProtocolAH})
+	}
+
+	if !strings.EqualFold(networkType, cni.OVNKubernetes) {
+		plan.Internal = []cpapi.PortSpec{{Port: constants.SubmarinerRoutePort, Protocol: ProtocolUDP}}
+	}
+
+	return plan
+}
+
+// WithProtocolNumbers returns the given ports with the ESP and AH protocols replaced by their IANA protocol numbers, for the
+// clouds which don't accept their names.
+func WithProtocolNumbers(ports []cpapi.PortSpec) []cpapi.PortSpec {
+	numbers := map[string]string{ProtocolESP: "50", ProtocolAH: "51"}
+	result := make([]cpapi.PortSpec, len(ports))
+
+	for i, port := range ports {
+		result[i] = port
+		if number, ok := numbers[port.Protocol]; ok {
+			result[i].Protocol = number
+		}
+	}
+
+	return result
+}
+
+// PortsOnly returns the given ports without the protocols which have no port, such as ESP and AH, for the
+// firewalls which only open ports.
+func PortsOnly(ports []cpapi.PortSpec) []cpapi.PortSpec {
+	result := []cpapi.PortSpec{}
+
+	for _, port := range ports {
+		if port.Port != 0 {
+			result = append(result, port)
+		}
+	}
+
+	return result
+}
+
+// Change returns the planned change of the resource of the given kind and name, created to open the given ports.
+func Change(kind, name string, ports []cpapi.PortSpec) configv1alpha1.PlannedChange {
+	return configv1alpha1.PlannedChange{
+		Action:      configv1alpha1.PlannedChangeActionCreate,
+		Kind:        kind,
+		Name:        name,
+		Description: "Open " + String(ports),
+	}
+}
+
+// String returns the given ports as a list such as "4500/udp, 4900/udp, esp".
+func String(ports []cpapi.PortSpec) string {
+	opened := make([]string, len(ports))
+
+	for i, port := range ports {
+		opened[i] = port.Protocol
+		if port.Port != 0 {
+			opened[i] = fmt.Sprintf("%d/%s", port.Port, port.Protocol)
+		}
+	}
+
+	return strings.Join(opened, ", ")
+}
+
+// Rule is a cloud resource, such as a security group or a firewall rule, opening ports for the gateway nodes.
+type Rule struct {
+	Kind  string
+	Name  string
+	Ports []cpapi.PortSpec
+}
+
+// Deleted returns the description of the drift of the rule when it was deleted.
+func (r *Rule) Deleted() string {
+	return fmt.Sprintf("the %s %q was deleted", r.Kind, r.Name)
+}
+
+// Drift returns the description of the drift of the rule given the ranges of ports it currently opens, if any of its ports
+// isn't opened anymore.
+func (r *Rule) Drift(opened []Range) []string {
+	missing := Missing(r.Ports, opened)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("the %s %q doesn't open %s anymore", r.Kind, r.Name, String(missing))}
+}
+
+// Range is a range of ports opened for a protocol by a firewall rule.
+type Range struct {
+	// Protocol is the protocol of the ports, or ProtocolAny for all the protocols.
+	Protocol string
+	// From and To are the first and last ports of the range, all the ports are opened if both are 0.
+	From uint16
+	To   uint16
+}
+
+// ParseRange returns the range of ports of the given protocol described as a single port such as "4500", a range such as
+// "4500-4510", or all the ports with "*" or an empty string.
+func ParseRange(protocol, ports string) (Range, error) {
+	r := Range{Protocol: protocol}
+
+	if ports == "" || ports == "*" {
+		return r, nil
+	}
+
+	from, to, isRange := strings.Cut(ports, "-")
+	if !isRange {
+		to = from
+	}
+
+	first, err := strconv.ParseUint(strings.TrimSpace(from), 10, 16)
+	if err != nil {
+		return r, fmt.Errorf("invalid port range %q: %w", ports, err)
+	}
+
+	last, err := strconv.ParseUint(strings.TrimSpace(to), 10, 16)
+	if err != nil {
+		return r, fmt.Errorf("invalid port range %q: %w", ports, err)
+	}
+
+	r.From, r.To = uint16(first), uint16(last)
+
+	return r, nil
+}
+
+// Missing returns the given ports which aren't opened by any of the given ranges, the protocols being compared regardless of
+// their case.
+func Missing(ports []cpapi.PortSpec, opened []Range) []cpapi.PortSpec {
+	missing := []cpapi.PortSpec{}
+
+	for _, port := range ports {
+		found := false
+
+		for _, r := range opened {
+			if r.Protocol != ProtocolAny && !strings.EqualFold(r.Protocol, port.Protocol) {
+				continue
+			}
+
+			if port.Port == 0 || (r.From == 0 && r.To == 0) || (r.From <= port.Port && port.Port <= r.To) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, port)
+		}
+	}
+
+	return missing
+}
+
+func isIPsec(cableDriver string) bool {
+	switch strings.ToLower(cableDriver) {
+	case "", CableDriverLibreswan, CableDriverStrongswan:
+		return true
+	}
+
+	return false
+}
+
+func portOrDefault(port, defaultPort int) uint16 {
+	if port == 0 {
+		return uint16(defaultPort)
+	}
+
+	return uint16(port)
+}
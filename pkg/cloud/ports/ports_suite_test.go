@@ -0,0 +1,13 @@
+package ports_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPorts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cloud Ports Suite")
+}
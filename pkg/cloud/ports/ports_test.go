@@ -0,0 +1,155 @@
+package ports_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	"github.com/submariner-io/submariner/pkg/cni"
+)
+
+var (
+	nattPort      = cpapi.PortSpec{Port: 4500, Protocol: "udp"}
+	discoveryPort = cpapi.PortSpec{Port: 4900, Protocol: "udp"}
+	ikePort       = cpapi.PortSpec{Port: 500, Protocol: "udp"}
+	esp           = cpapi.PortSpec{Port: 0, Protocol: "esp"}
+	ah            = cpapi.PortSpec{Port: 0, Protocol: "ah"}
+	routePort     = cpapi.PortSpec{Port: 4800, Protocol: "udp"}
+)
+
+var _ = Describe("For", func() {
+	DescribeTable("should plan the ports",
+		func(spec configv1alpha1.SubmarinerConfigSpec, networkType string, expected ports.Plan) {
+			Expect(ports.For(&spec, networkType)).To(Equal(expected))
+		},
+		Entry("for the default cable driver",
+			configv1alpha1.SubmarinerConfigSpec{},
+			"OpenShiftSDN",
+			ports.Plan{
+				Public:   []cpapi.PortSpec{nattPort, discoveryPort, ikePort, esp, ah},
+				Internal: []cpapi.PortSpec{routePort},
+			}),
+		Entry("for libreswan with custom ports",
+			configv1alpha1.SubmarinerConfigSpec{
+				CableDriver:       "libreswan",
+				IPSecIKEPort:      501,
+				IPSecNATTPort:     4501,
+				NATTDiscoveryPort: 4901,
+			},
+			"OpenShiftSDN",
+			ports.Plan{
+				Public: []cpapi.PortSpec{
+					{Port: 4501, Protocol: "udp"},
+					{Port: 4901, Protocol: "udp"},
+					{Port: 501, Protocol: "udp"},
+					esp,
+					ah,
+				},
+				Internal: []cpapi.PortSpec{routePort},
+			}),
+		Entry("for strongswan",
+			configv1alpha1.SubmarinerConfigSpec{CableDriver: "strongswan"},
+			"OpenShiftSDN",
+			ports.Plan{
+				Public:   []cpapi.PortSpec{nattPort, discoveryPort, ikePort, esp, ah},
+				Internal: []cpapi.PortSpec{routePort},
+			}),
+		Entry("for libreswan with a load balancer",
+			configv1alpha1.SubmarinerConfigSpec{CableDriver: "libreswan", LoadBalancerEnable: true},
+			"OpenShiftSDN",
+			ports.Plan{
+				Public:   []cpapi.PortSpec{nattPort, discoveryPort},
+				Internal: []cpapi.PortSpec{routePort},
+			}),
+		Entry("for wireguard",
+			configv1alpha1.SubmarinerConfigSpec{CableDriver: "wireguard", IPSecNATTPort: 5871},
+			"OpenShiftSDN",
+			ports.Plan{
+				Public:   []cpapi.PortSpec{{Port: 5871, Protocol: "udp"}, discoveryPort},
+				Internal: []cpapi.PortSpec{routePort},
+			}),
+		Entry("for vxlan",
+			configv1alpha1.SubmarinerConfigSpec{CableDriver: "vxlan"},
+			"OpenShiftSDN",
+			ports.Plan{
+				Public:   []cpapi.PortSpec{nattPort, discoveryPort},
+				Internal: []cpapi.PortSpec{routePort},
+			}),
+		Entry("for the OVNKubernetes CNI",
+			configv1alpha1.SubmarinerConfigSpec{CableDriver: "vxlan"},
+			cni.OVNKubernetes,
+			ports.Plan{
+				Public: []cpapi.PortSpec{nattPort, discoveryPort},
+			}),
+	)
+})
+
+var _ = Describe("WithProtocolNumbers", func() {
+	It("should replace the ESP and AH protocols with their numbers", func() {
+		Expect(ports.WithProtocolNumbers([]cpapi.PortSpec{nattPort, esp, ah})).To(Equal([]cpapi.PortSpec{
+			nattPort,
+			{Port: 0, Protocol: "50"},
+			{Port: 0, Protocol: "51"},
+		}))
+	})
+})
+
+var _ = Describe("PortsOnly", func() {
+	It("should remove the protocols without a port", func() {
+		Expect(ports.PortsOnly([]cpapi.PortSpec{nattPort, esp, ikePort, ah})).To(Equal([]cpapi.PortSpec{nattPort, ikePort}))
+	})
+})
+
+var _ = Describe("Change", func() {
+	It("should plan the creation of the resource opening the ports", func() {
+		Expect(ports.Change("FirewallRule", "test-rule", []cpapi.PortSpec{nattPort, discoveryPort, esp})).To(Equal(
+			configv1alpha1.PlannedChange{
+				Action:      configv1alpha1.PlannedChangeActionCreate,
+				Kind:        "FirewallRule",
+				Name:        "test-rule",
+				Description: "Open 4500/udp, 4900/udp, esp",
+			}))
+	})
+})
+
+var _ = Describe("Missing", func() {
+	It("should return the ports not opened by the ranges", func() {
+		Expect(ports.Missing([]cpapi.PortSpec{nattPort, discoveryPort, ikePort, esp, ah}, []ports.Range{
+			{Protocol: "UDP", From: 4500, To: 4500},
+			{Protocol: "udp", From: 4800, To: 4950},
+			{Protocol: "esp"},
+		})).To(Equal([]cpapi.PortSpec{ikePort, ah}))
+	})
+
+	It("should consider all the ports of a range without ports, and all the protocols of the any protocol", func() {
+		Expect(ports.Missing([]cpapi.PortSpec{nattPort, esp}, []ports.Range{{Protocol: "udp"}})).To(Equal([]cpapi.PortSpec{esp}))
+		Expect(ports.Missing([]cpapi.PortSpec{nattPort, esp}, []ports.Range{{Protocol: ports.ProtocolAny}})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ParseRange", func() {
+	DescribeTable("should parse the range",
+		func(portRange string, expected ports.Range) {
+			Expect(ports.ParseRange("udp", portRange)).To(Equal(expected))
+		},
+		Entry("of a single port", "4500", ports.Range{Protocol: "udp", From: 4500, To: 4500}),
+		Entry("of several ports", "4500-4510", ports.Range{Protocol: "udp", From: 4500, To: 4510}),
+		Entry("of all the ports", "*", ports.Range{Protocol: "udp"}),
+	)
+
+	It("should fail on an invalid port", func() {
+		_, err := ports.ParseRange("udp", "4500-http")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Rule", func() {
+	rule := &ports.Rule{Kind: "FirewallRule", Name: "test-rule", Ports: []cpapi.PortSpec{nattPort, discoveryPort}}
+
+	It("should describe the ports it doesn't open anymore", func() {
+		Expect(rule.Drift([]ports.Range{{Protocol: "udp", From: 4500, To: 4500}})).To(Equal(
+			[]string{`the FirewallRule "test-rule" doesn't open 4900/udp anymore`}))
+		Expect(rule.Drift([]ports.Range{{Protocol: "udp"}})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// DetectGatewayMachineSetsDrift returns the drift of the MachineSets which deploy the given count of dedicated gateway nodes,
+// the MachineSets which were deleted or scaled to 0 since.
+func DetectGatewayMachineSetsDrift(ctx context.Context, dynamicClient dynamic.Interface, infraID string, gateways int) ([]string, error) {
+	machineSets, err := dynamicClient.Resource(machineSetGVR).Namespace(machineSetsNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "error listing the MachineSets")
+	}
+
+	prefix := GatewayMachineSetPrefix(infraID)
+	drifts := []string{}
+	found := 0
+
+	if err == nil {
+		sort.Slice(machineSets.Items, func(i, j int) bool {
+			return machineSets.Items[i].GetName() < machineSets.Items[j].GetName()
+		})
+
+		for i := range machineSets.Items {
+			if !strings.HasPrefix(machineSets.Items[i].GetName(), prefix) {
+				continue
+			}
+
+			found++
+
+			replicas, hasReplicas, _ := unstructured.NestedInt64(machineSets.Items[i].Object, "spec", "replicas")
+			if hasReplicas && replicas == 0 {
+				drifts = append(drifts, fmt.Sprintf("the gateway MachineSet %q is scaled to 0", machineSets.Items[i].GetName()))
+			}
+		}
+	}
+
+	if found < gateways {
+		drifts = append(drifts, fmt.Sprintf("%d of the %d gateway MachineSet(s) are missing", gateways-found, gateways))
+	}
+
+	return drifts, nil
+}
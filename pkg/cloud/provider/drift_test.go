@@ -0,0 +1,69 @@
+package provider_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+var _ = Describe("DetectGatewayMachineSetsDrift", func() {
+	var machineSets []runtime.Object
+
+	BeforeEach(func() {
+		machineSets = []runtime.Object{
+			newMachineSet("test-infra-worker-us-east-1a"),
+			newGatewayMachineSet("test-infra-submariner-gw-us-east-1a", 1),
+			newGatewayMachineSet("test-infra-submariner-gw-us-east-1b", 1),
+		}
+	})
+
+	detectDrift := func() []string {
+		dynamicClient := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machinesets"}: "MachineSetList",
+		}, machineSets...)
+
+		drifts, err := provider.DetectGatewayMachineSetsDrift(context.TODO(), dynamicClient, "test-infra", 2)
+		Expect(err).To(Succeed())
+
+		return drifts
+	}
+
+	When("the gateway MachineSets are deployed", func() {
+		It("should not detect a drift", func() {
+			Expect(detectDrift()).To(BeEmpty())
+		})
+	})
+
+	When("a gateway MachineSet is scaled to 0", func() {
+		BeforeEach(func() {
+			machineSets[2] = newGatewayMachineSet("test-infra-submariner-gw-us-east-1b", 0)
+		})
+
+		It("should detect the drift", func() {
+			Expect(detectDrift()).To(Equal([]string{`the gateway MachineSet "test-infra-submariner-gw-us-east-1b" is scaled to 0`}))
+		})
+	})
+
+	When("a gateway MachineSet is deleted", func() {
+		BeforeEach(func() {
+			machineSets = machineSets[:2]
+		})
+
+		It("should detect the drift", func() {
+			Expect(detectDrift()).To(Equal([]string{"1 of the 2 gateway MachineSet(s) are missing"}))
+		})
+	})
+})
+
+func newGatewayMachineSet(name string, replicas int64) *unstructured.Unstructured {
+	machineSet := newMachineSet(name)
+	Expect(unstructured.SetNestedField(machineSet.Object, replicas, "spec", "replicas")).To(Succeed())
+
+	return machineSet
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ErrInvalidCredentials is wrapped by the errors due to cloud credentials which are missing from the credentials secret or
+// malformed.
+var ErrInvalidCredentials = errors.New("invalid cloud credentials")
+
+// ErrUnsupported is wrapped by the errors of the providers which can't prepare the cloud of a cluster, the ports must then
+// be opened by the user.
+var ErrUnsupported = errors.New("the cloud environment can't be prepared")
+
+// awsCredentialsErrorCodes are the AWS API error codes returned for invalid or expired credentials.
+var awsCredentialsErrorCodes = sets.New("AuthFailure", "ExpiredToken", "ExpiredTokenException", "IncompleteSignature",
+	"InvalidAccessKeyId", "InvalidClientTokenId", "SignatureDoesNotMatch", "UnrecognizedClientException")
+
+// IsInvalidCredentials returns whether the given error is due to invalid or expired cloud credentials, rather than to another
+// cloud error.
+func IsInvalidCredentials(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrInvalidCredentials) {
+		return true
+	}
+
+	// AWS, see smithy.APIError
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) && awsCredentialsErrorCodes.Has(apiErr.ErrorCode()) {
+		return true
+	}
+
+	// OpenStack, see gophercloud.StatusCodeError
+	var statusCodeErr interface{ GetStatusCode() int }
+	if errors.As(err, &statusCodeErr) && statusCodeErr.GetStatusCode() == http.StatusUnauthorized {
+		return true
+	}
+
+	// GCP
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return true
+	}
+
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) && googleErr.Code == http.StatusUnauthorized {
+		return true
+	}
+
+	// Azure
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) {
+		return true
+	}
+
+	var responseErr *azcore.ResponseError
+
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusUnauthorized
+}
@@ -14,6 +14,7 @@ type Info struct {
 	KubeClient        kubernetes.Interface
 	DynamicClient     dynamic.Interface
 	EventRecorder     events.Recorder
+	StepLog           *StepLog
 	CredentialsSecret *corev1.Secret
 	configv1alpha1.SubmarinerConfigSpec
 	configv1alpha1.ManagedClusterInfo
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// machineSetsNamespace is the namespace of the MachineSets which deploy the dedicated gateway nodes on OpenShift.
+const machineSetsNamespace = "openshift-machine-api"
+
+// CheckMachineSetPermissions returns the permissions to manage the MachineSets which deploy the dedicated gateway nodes, missing
+// from the given client.
+func CheckMachineSetPermissions(ctx context.Context, kubeClient kubernetes.Interface) ([]string, error) {
+	missing := []string{}
+
+	for _, verb := range []string{"get", "create", "update", "delete"} {
+		review, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: machineSetsNamespace,
+					Verb:      verb,
+					Group:     "machine.openshift.io",
+					Resource:  "machinesets",
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reviewing the permission to %s MachineSets", verb)
+		}
+
+		if !review.Status.Allowed {
+			missing = append(missing, fmt.Sprintf("machine.openshift.io/machinesets/%s", verb))
+		}
+	}
+
+	return missing, nil
+}
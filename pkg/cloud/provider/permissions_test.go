@@ -0,0 +1,70 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeFake "k8s.io/client-go/kubernetes/fake"
+	clientTesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("CheckMachineSetPermissions", func() {
+	var (
+		kubeClient    *kubeFake.Clientset
+		allowedVerbs  map[string]bool
+		reviewFailure error
+	)
+
+	BeforeEach(func() {
+		kubeClient = kubeFake.NewSimpleClientset()
+		allowedVerbs = map[string]bool{"get": true, "create": true, "update": true, "delete": true}
+		reviewFailure = nil
+
+		kubeClient.PrependReactor("create", "selfsubjectaccessreviews",
+			func(action clientTesting.Action) (bool, runtime.Object, error) {
+				review := action.(clientTesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				Expect(review.Spec.ResourceAttributes.Group).To(Equal("machine.openshift.io"))
+				Expect(review.Spec.ResourceAttributes.Resource).To(Equal("machinesets"))
+				review.Status.Allowed = allowedVerbs[review.Spec.ResourceAttributes.Verb]
+
+				return true, review, reviewFailure
+			})
+	})
+
+	When("all the MachineSet permissions are granted", func() {
+		It("should return no missing permission", func() {
+			missing, err := provider.CheckMachineSetPermissions(context.TODO(), kubeClient)
+			Expect(err).To(Succeed())
+			Expect(missing).To(BeEmpty())
+		})
+	})
+
+	When("some MachineSet permissions are denied", func() {
+		BeforeEach(func() {
+			allowedVerbs["create"] = false
+			allowedVerbs["delete"] = false
+		})
+
+		It("should return the missing permissions", func() {
+			missing, err := provider.CheckMachineSetPermissions(context.TODO(), kubeClient)
+			Expect(err).To(Succeed())
+			Expect(missing).To(Equal([]string{"machine.openshift.io/machinesets/create", "machine.openshift.io/machinesets/delete"}))
+		})
+	})
+
+	When("the access review fails", func() {
+		BeforeEach(func() {
+			reviewFailure = errors.New("fake error")
+		})
+
+		It("should return an error", func() {
+			_, err := provider.CheckMachineSetPermissions(context.TODO(), kubeClient)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// KindMachineSet is the kind of the planned changes of the MachineSets which deploy the dedicated gateway nodes.
+const KindMachineSet = "MachineSet"
+
+var machineSetGVR = schema.GroupVersionResource{
+	Group:    "machine.openshift.io",
+	Version:  "v1beta1",
+	Resource: "machinesets",
+}
+
+// GatewayMachineSetPrefix returns the prefix of the names of the MachineSets which deploy the dedicated gateway nodes of the
+// cluster with the given infrastructure ID, one per zone.
+func GatewayMachineSetPrefix(infraID string) string {
+	return infraID + "-submariner-gw-"
+}
+
+// PlanGatewayMachineSets returns the changes of the MachineSets required to deploy the given count of dedicated gateway nodes,
+// each MachineSet deploying a gateway node of the given instance type in a distinct zone. The existing gateway MachineSets
+// beyond the count of gateways are deleted.
+func PlanGatewayMachineSets(ctx context.Context, dynamicClient dynamic.Interface, infraID string, gateways int,
+	instanceType string,
+) ([]configv1alpha1.PlannedChange, error) {
+	machineSets, err := dynamicClient.Resource(machineSetGVR).Namespace(machineSetsNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "error listing the MachineSets")
+	}
+
+	prefix := GatewayMachineSetPrefix(infraID)
+	existing := []string{}
+
+	if err == nil {
+		for i := range machineSets.Items {
+			if strings.HasPrefix(machineSets.Items[i].GetName(), prefix) {
+				existing = append(existing, machineSets.Items[i].GetName())
+			}
+		}
+	}
+
+	sort.Strings(existing)
+
+	changes := []configv1alpha1.PlannedChange{}
+
+	if missing := gateways - len(existing); missing > 0 {
+		changes = append(changes, configv1alpha1.PlannedChange{
+			Action: configv1alpha1.PlannedChangeActionCreate,
+			Kind:   KindMachineSet,
+			Name:   prefix + "<zone>",
+			Description: fmt.Sprintf("Deploy %d gateway node(s) of instance type %s, one per zone without a gateway node", missing,
+				instanceType),
+		})
+	}
+
+	for i := gateways; i < len(existing); i++ {
+		changes = append(changes, configv1alpha1.PlannedChange{
+			Action:      configv1alpha1.PlannedChangeActionDelete,
+			Kind:        KindMachineSet,
+			Name:        existing[i],
+			Description: fmt.Sprintf("Remove the gateway node beyond the %d gateway(s) required", gateways),
+		})
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,87 @@
+package provider_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+var _ = Describe("PlanGatewayMachineSets", func() {
+	var (
+		machineSets []runtime.Object
+		gateways    int
+	)
+
+	BeforeEach(func() {
+		machineSets = []runtime.Object{newMachineSet("test-infra-worker-us-east-1a")}
+		gateways = 2
+	})
+
+	plan := func() []configv1alpha1.PlannedChange {
+		dynamicClient := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machinesets"}: "MachineSetList",
+		}, machineSets...)
+
+		changes, err := provider.PlanGatewayMachineSets(context.TODO(), dynamicClient, "test-infra", gateways, "m5n.large")
+		Expect(err).To(Succeed())
+
+		return changes
+	}
+
+	When("there are no gateway MachineSets", func() {
+		It("should plan the creation of the gateway MachineSets", func() {
+			changes := plan()
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Action).To(Equal(configv1alpha1.PlannedChangeActionCreate))
+			Expect(changes[0].Kind).To(Equal(provider.KindMachineSet))
+			Expect(changes[0].Description).To(ContainSubstring("2 gateway node(s) of instance type m5n.large"))
+		})
+	})
+
+	When("some gateway MachineSets already exist", func() {
+		BeforeEach(func() {
+			machineSets = append(machineSets, newMachineSet("test-infra-submariner-gw-us-east-1a"))
+		})
+
+		It("should only plan the creation of the missing gateway MachineSets", func() {
+			changes := plan()
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Action).To(Equal(configv1alpha1.PlannedChangeActionCreate))
+			Expect(changes[0].Description).To(ContainSubstring("1 gateway node(s)"))
+		})
+	})
+
+	When("there are more gateway MachineSets than gateways", func() {
+		BeforeEach(func() {
+			gateways = 1
+			machineSets = append(machineSets, newMachineSet("test-infra-submariner-gw-us-east-1b"),
+				newMachineSet("test-infra-submariner-gw-us-east-1a"))
+		})
+
+		It("should plan the deletion of the extra gateway MachineSets", func() {
+			Expect(plan()).To(Equal([]configv1alpha1.PlannedChange{{
+				Action:      configv1alpha1.PlannedChangeActionDelete,
+				Kind:        provider.KindMachineSet,
+				Name:        "test-infra-submariner-gw-us-east-1b",
+				Description: "Remove the gateway node beyond the 1 gateway(s) required",
+			}}))
+		})
+	})
+})
+
+func newMachineSet(name string) *unstructured.Unstructured {
+	machineSet := &unstructured.Unstructured{}
+	machineSet.SetAPIVersion("machine.openshift.io/v1beta1")
+	machineSet.SetKind("MachineSet")
+	machineSet.SetNamespace("openshift-machine-api")
+	machineSet.SetName(name)
+
+	return machineSet
+}
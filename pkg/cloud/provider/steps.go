@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"sync"
+
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepLog records the steps of a preparation of the Submariner cluster environment, as reported by cloud-prepare, keeping the
+// last MaxPreparationSteps ones. It's safe for concurrent use, and a nil StepLog records nothing.
+type StepLog struct {
+	mutex  sync.Mutex
+	steps  []configv1alpha1.PreparationStep
+	closed bool
+}
+
+func NewStepLog() *StepLog {
+	return &StepLog{}
+}
+
+// Start records the start of a step. The steps can be nested, the last step started is ended first.
+func (l *StepLog) Start(name string) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return
+	}
+
+	l.append(configv1alpha1.PreparationStep{
+		Name:      name,
+		StartTime: metav1.Now(),
+		Outcome:   configv1alpha1.PreparationStepOutcomeInProgress,
+	})
+}
+
+// Succeed records the success of the last step in progress with the given message. Without a step in progress, the message is
+// recorded as a step which succeeded.
+func (l *StepLog) Succeed(message string) {
+	l.endOrRecord(configv1alpha1.PreparationStepOutcomeSucceeded, message)
+}
+
+// Fail records the failure of the last step in progress with the given message. Without a step in progress, the message is recorded
+// as a step which failed.
+func (l *StepLog) Fail(message string) {
+	l.endOrRecord(configv1alpha1.PreparationStepOutcomeFailed, message)
+}
+
+// Warn records the given warning as the message of the last step in progress, if any.
+func (l *StepLog) Warn(message string) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return
+	}
+
+	if i := l.lastInProgress(); i >= 0 {
+		l.steps[i].Message = message
+	}
+}
+
+// Close stops recording the steps, the steps reported afterwards, e.g. by a preparation which timed out and keeps running in
+// the background, are dropped.
+func (l *StepLog) Close() {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.closed = true
+}
+
+// Steps returns a copy of the recorded steps, the most recent last.
+func (l *StepLog) Steps() []configv1alpha1.PreparationStep {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	steps := make([]configv1alpha1.PreparationStep, len(l.steps))
+	for i := range l.steps {
+		l.steps[i].DeepCopyInto(&steps[i])
+	}
+
+	return steps
+}
+
+func (l *StepLog) endOrRecord(outcome, message string) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed || l.end(outcome, message) {
+		return
+	}
+
+	now := metav1.Now()
+
+	l.append(configv1alpha1.PreparationStep{
+		Name:      message,
+		StartTime: now,
+		EndTime:   &now,
+		Outcome:   outcome,
+	})
+}
+
+// end ends the last step in progress with the given outcome and message, and returns whether there was one.
+func (l *StepLog) end(outcome, message string) bool {
+	i := l.lastInProgress()
+	if i < 0 {
+		return false
+	}
+
+	now := metav1.Now()
+	l.steps[i].EndTime = &now
+	l.steps[i].Outcome = outcome
+	l.steps[i].Message = message
+
+	return true
+}
+
+func (l *StepLog) lastInProgress() int {
+	for i := len(l.steps) - 1; i >= 0; i-- {
+		if l.steps[i].Outcome == configv1alpha1.PreparationStepOutcomeInProgress {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (l *StepLog) append(step configv1alpha1.PreparationStep) {
+	l.steps = append(l.steps, step)
+
+	if len(l.steps) > configv1alpha1.MaxPreparationSteps {
+		l.steps = l.steps[len(l.steps)-configv1alpha1.MaxPreparationSteps:]
+	}
+}
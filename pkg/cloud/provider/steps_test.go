@@ -0,0 +1,111 @@
+package provider_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+)
+
+var _ = Describe("StepLog", func() {
+	var stepLog *provider.StepLog
+
+	BeforeEach(func() {
+		stepLog = provider.NewStepLog()
+	})
+
+	When("steps are started and ended", func() {
+		It("should record their outcome and end message", func() {
+			stepLog.Start("Opening port 4500 protocol udp")
+			stepLog.Succeed("Opened port 4500 protocol udp")
+			stepLog.Start("Deploying gateway MachineSet")
+			stepLog.Fail("Failed to deploy gateway MachineSet: quota exceeded")
+
+			steps := stepLog.Steps()
+			Expect(steps).To(HaveLen(2))
+			Expect(steps[0].Name).To(Equal("Opening port 4500 protocol udp"))
+			Expect(steps[0].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeSucceeded))
+			Expect(steps[0].Message).To(Equal("Opened port 4500 protocol udp"))
+			Expect(steps[0].EndTime).ToNot(BeNil())
+			Expect(steps[1].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeFailed))
+			Expect(steps[1].Message).To(Equal("Failed to deploy gateway MachineSet: quota exceeded"))
+		})
+	})
+
+	When("steps are nested", func() {
+		It("should end the last step started first", func() {
+			stepLog.Start("Deploying the gateways")
+			stepLog.Start("Opening the public ports")
+			stepLog.Succeed("Opened the public ports")
+
+			steps := stepLog.Steps()
+			Expect(steps).To(HaveLen(2))
+			Expect(steps[0].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeInProgress))
+			Expect(steps[0].EndTime).To(BeNil())
+			Expect(steps[1].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeSucceeded))
+		})
+	})
+
+	When("a warning is reported during a step", func() {
+		It("should record it as the message of the step", func() {
+			stepLog.Start("Deploying gateway MachineSet")
+			stepLog.Warn("The instance type isn't available in the zone")
+
+			steps := stepLog.Steps()
+			Expect(steps).To(HaveLen(1))
+			Expect(steps[0].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeInProgress))
+			Expect(steps[0].Message).To(Equal("The instance type isn't available in the zone"))
+		})
+	})
+
+	When("a step ends without being started", func() {
+		It("should record it as an ended step", func() {
+			stepLog.Succeed("The Submariner cluster environment has been set up")
+
+			steps := stepLog.Steps()
+			Expect(steps).To(HaveLen(1))
+			Expect(steps[0].Name).To(Equal("The Submariner cluster environment has been set up"))
+			Expect(steps[0].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeSucceeded))
+			Expect(steps[0].EndTime).ToNot(BeNil())
+		})
+	})
+
+	When("more steps than the maximum are recorded", func() {
+		It("should only keep the last ones", func() {
+			for i := 0; i < configv1alpha1.MaxPreparationSteps+3; i++ {
+				stepLog.Start(fmt.Sprintf("step-%d", i))
+				stepLog.Succeed("")
+			}
+
+			steps := stepLog.Steps()
+			Expect(steps).To(HaveLen(configv1alpha1.MaxPreparationSteps))
+			Expect(steps[0].Name).To(Equal("step-3"))
+		})
+	})
+
+	When("the step log is closed", func() {
+		It("should drop the steps reported afterwards", func() {
+			stepLog.Start("Opening port 4500 protocol udp")
+			stepLog.Close()
+			stepLog.Succeed("Opened port 4500 protocol udp")
+			stepLog.Start("Deploying gateway MachineSet")
+
+			steps := stepLog.Steps()
+			Expect(steps).To(HaveLen(1))
+			Expect(steps[0].Name).To(Equal("Opening port 4500 protocol udp"))
+			Expect(steps[0].Outcome).To(Equal(configv1alpha1.PreparationStepOutcomeInProgress))
+		})
+	})
+
+	When("the step log is nil", func() {
+		It("should record nothing", func() {
+			var nilLog *provider.StepLog
+
+			nilLog.Start("step")
+			nilLog.Succeed("done")
+			Expect(nilLog.Steps()).To(BeEmpty())
+		})
+	})
+})
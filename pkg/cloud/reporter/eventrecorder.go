@@ -4,35 +4,42 @@ import (
 	"fmt"
 
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/submariner-io/admiral/pkg/reporter"
 )
 
 type eventRecorderReporter struct {
 	reason        string
 	eventRecorder events.Recorder
+	stepLog       *provider.StepLog
 }
 
-// NewEventRecorderWrapper creates an event-recorder-based reporter.
-func NewEventRecorderWrapper(reason string, recorder events.Recorder) reporter.Interface {
+// NewEventRecorderWrapper creates an event-recorder-based reporter, which also records the reported operations as steps in the
+// given step log if set.
+func NewEventRecorderWrapper(reason string, recorder events.Recorder, stepLog *provider.StepLog) reporter.Interface {
 	return &reporter.Adapter{Basic: &eventRecorderReporter{
 		reason:        reason,
 		eventRecorder: recorder,
+		stepLog:       stepLog,
 	}}
 }
 
 // Start will report that an operation started on the cloud.
 func (g eventRecorderReporter) Start(message string, args ...interface{}) {
 	g.eventRecorder.Eventf(g.reason, fmt.Sprintf(message, args...))
+	g.stepLog.Start(fmt.Sprintf(message, args...))
 }
 
 // Success will report that the last operation on the cloud has succeeded.
 func (g eventRecorderReporter) Success(message string, args ...interface{}) {
 	g.eventRecorder.Eventf(g.reason, message, args...)
+	g.stepLog.Succeed(fmt.Sprintf(message, args...))
 }
 
 // Failure will report that the last operation on the cloud has failed.
 func (g eventRecorderReporter) Failure(message string, args ...interface{}) {
 	g.eventRecorder.Warningf(g.reason, message, args...)
+	g.stepLog.Fail(fmt.Sprintf(message, args...))
 }
 
 func (g eventRecorderReporter) End() {
@@ -41,4 +48,5 @@ func (g eventRecorderReporter) End() {
 
 func (g eventRecorderReporter) Warning(message string, args ...interface{}) {
 	g.eventRecorder.Warningf(g.reason, message, args...)
+	g.stepLog.Warn(fmt.Sprintf(message, args...))
 }
@@ -0,0 +1,64 @@
+package rhos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+)
+
+// DetectDrift verifies that the security groups still open the Submariner ports, and that the gateway MachineSets still
+// deploy the gateway nodes.
+func (r *rhosProvider) DetectDrift(ctx context.Context) ([]string, error) {
+	networkClient, err := openstack.NewNetworkV2(r.providerClient, gophercloud.EndpointOpts{Region: r.region})
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []string{}
+
+	for _, rule := range r.rules() {
+		pages, err := groups.List(networkClient, groups.ListOpts{Name: rule.Name}).AllPages()
+		if err != nil {
+			return nil, fmt.Errorf("error listing the security group %q: %w", rule.Name, err)
+		}
+
+		found, err := groups.ExtractGroups(pages)
+		if err != nil {
+			return nil, fmt.Errorf("error listing the security group %q: %w", rule.Name, err)
+		}
+
+		if len(found) == 0 {
+			drifts = append(drifts, rule.Deleted())
+			continue
+		}
+
+		opened := []ports.Range{}
+
+		for _, groupRule := range found[0].Rules {
+			if groupRule.Direction != "ingress" {
+				continue
+			}
+
+			protocol := groupRule.Protocol
+			if protocol == "" {
+				protocol = ports.ProtocolAny
+			}
+
+			opened = append(opened, ports.Range{Protocol: protocol, From: uint16(groupRule.PortRangeMin), To: uint16(groupRule.PortRangeMax)})
+		}
+
+		drifts = append(drifts, rule.Drift(opened)...)
+	}
+
+	machineSetDrifts, err := provider.DetectGatewayMachineSetsDrift(ctx, r.dynamicClient, r.infraID, r.gateways)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(drifts, machineSetDrifts...), nil
+}
@@ -0,0 +1,71 @@
+package rhos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/pagination"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+)
+
+// CheckPermissions checks that the RHOS credentials can list the servers and the security groups of the project, and that the
+// MachineSets of the gateway nodes can be created. OpenStack has no dry run and no API to query the policy of a user, so the
+// permissions to modify the security groups and to create servers can't be checked without mutating the project.
+func (r *rhosProvider) CheckPermissions(ctx context.Context) ([]string, error) {
+	missing, err := CheckProjectPermissions(r.providerClient, r.region)
+	if err != nil {
+		return nil, err
+	}
+
+	machineSetMissing, err := provider.CheckMachineSetPermissions(ctx, r.kubeClient)
+
+	return append(missing, machineSetMissing...), err
+}
+
+// CheckProjectPermissions returns the policy rules of the project which forbid the given client to list the security groups
+// and the servers in the given region.
+func CheckProjectPermissions(providerClient *gophercloud.ProviderClient, region string) ([]string, error) {
+	endpointOpts := gophercloud.EndpointOpts{Region: region}
+	missing := []string{}
+
+	networkClient, err := openstack.NewNetworkV2(providerClient, endpointOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = groups.List(networkClient, groups.ListOpts{Limit: 1}).EachPage(firstPageOnly)
+	if isForbidden(err) {
+		missing = append(missing, "network:security_groups:list")
+	} else if err != nil {
+		return nil, err
+	}
+
+	computeClient, err := openstack.NewComputeV2(providerClient, endpointOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = servers.List(computeClient, servers.ListOpts{Limit: 1}).EachPage(firstPageOnly)
+	if isForbidden(err) {
+		missing = append(missing, "compute:servers:list")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+func firstPageOnly(_ pagination.Page) (bool, error) {
+	return false, nil
+}
+
+func isForbidden(err error) bool {
+	var statusCodeErr gophercloud.StatusCodeError
+
+	return errors.As(err, &statusCodeErr) && statusCodeErr.GetStatusCode() == http.StatusForbidden
+}
@@ -0,0 +1,97 @@
+package rhos_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	"github.com/stolostron/submariner-addon/pkg/cloud/rhos"
+)
+
+const region = "regionOne"
+
+var _ = Describe("CheckProjectPermissions", func() {
+	var (
+		statusCodes    map[string]int
+		providerClient *gophercloud.ProviderClient
+	)
+
+	BeforeEach(func() {
+		statusCodes = map[string]int{}
+
+		// The stubbed network and compute APIs return empty lists, or the status code configured for their path.
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if statusCode, ok := statusCodes[r.URL.Path]; ok {
+				w.WriteHeader(statusCode)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+
+			switch r.URL.Path {
+			case "/network/v2.0/security-groups":
+				_, _ = w.Write([]byte(`{"security_groups": []}`))
+			case "/compute/servers/detail":
+				_, _ = w.Write([]byte(`{"servers": []}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+
+		providerClient = &gophercloud.ProviderClient{
+			HTTPClient: *server.Client(),
+			EndpointLocator: func(opts gophercloud.EndpointOpts) (string, error) {
+				Expect(opts.Region).To(Equal(region))
+				return server.URL + "/" + opts.Type + "/", nil
+			},
+		}
+	})
+
+	DescribeTable("the responses",
+		func(responses map[string]int, expected []string) {
+			statusCodes = responses
+
+			missing, err := rhos.CheckProjectPermissions(providerClient, region)
+			Expect(err).To(Succeed())
+			Expect(missing).To(Equal(expected))
+		},
+		Entry("allowing all the requests", map[string]int{}, []string{}),
+		Entry("forbidding to list the security groups", map[string]int{
+			"/network/v2.0/security-groups": http.StatusForbidden,
+		}, []string{"network:security_groups:list"}),
+		Entry("forbidding to list the servers", map[string]int{
+			"/compute/servers/detail": http.StatusForbidden,
+		}, []string{"compute:servers:list"}),
+		Entry("forbidding all the requests", map[string]int{
+			"/network/v2.0/security-groups": http.StatusForbidden,
+			"/compute/servers/detail":       http.StatusForbidden,
+		}, []string{"network:security_groups:list", "compute:servers:list"}),
+	)
+
+	When("the credentials are rejected", func() {
+		BeforeEach(func() {
+			statusCodes["/network/v2.0/security-groups"] = http.StatusUnauthorized
+		})
+
+		It("should return an invalid credentials error", func() {
+			_, err := rhos.CheckProjectPermissions(providerClient, region)
+			Expect(provider.IsInvalidCredentials(err)).To(BeTrue())
+		})
+	})
+
+	When("a request fails", func() {
+		BeforeEach(func() {
+			statusCodes["/compute/servers/detail"] = http.StatusInternalServerError
+		})
+
+		It("should return an error", func() {
+			_, err := rhos.CheckProjectPermissions(providerClient, region)
+			Expect(err).To(HaveOccurred())
+			Expect(provider.IsInvalidCredentials(err)).To(BeFalse())
+		})
+	})
+})
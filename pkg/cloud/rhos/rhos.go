@@ -1,26 +1,27 @@
 package rhos
 
 import (
+	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
 	"github.com/gophercloud/utils/openstack/clientconfig"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/cloud/ports"
 	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/cloud/reporter"
-	"github.com/stolostron/submariner-addon/pkg/constants"
 	submreporter "github.com/submariner-io/admiral/pkg/reporter"
 	"github.com/submariner-io/cloud-prepare/pkg/api"
 	"github.com/submariner-io/cloud-prepare/pkg/k8s"
 	"github.com/submariner-io/cloud-prepare/pkg/ocp"
 	cloudpreparerhos "github.com/submariner-io/cloud-prepare/pkg/rhos"
-	"github.com/submariner-io/submariner/pkg/cni"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 )
@@ -32,14 +33,17 @@ const (
 )
 
 type rhosProvider struct {
-	infraID           string
-	nattPort          uint16
-	cniType           string
-	cloudPrepare      api.Cloud
-	reporter          submreporter.Interface
-	gwDeployer        api.GatewayDeployer
-	gateways          int
-	nattDiscoveryPort int64
+	ports          ports.Plan
+	infraID        string
+	cloudPrepare   api.Cloud
+	reporter       submreporter.Interface
+	gwDeployer     api.GatewayDeployer
+	gateways       int
+	instanceType   string
+	region         string
+	providerClient *gophercloud.ProviderClient
+	kubeClient     kubernetes.Interface
+	dynamicClient  dynamic.Interface
 }
 
 //nolint:revive // Ignore unexported-return - we can't reference the Provider interface here.
@@ -78,40 +82,37 @@ func NewProvider(info *provider.Info) (*rhosProvider, error) {
 	gwDeployer := cloudpreparerhos.NewOcpGatewayDeployer(cloudInfo, msDeployer, projectID, instanceType, "", cloudEntry)
 
 	return &rhosProvider{
-		infraID:           info.InfraID,
-		nattPort:          uint16(info.IPSecNATTPort),
-		cniType:           info.NetworkType,
-		cloudPrepare:      cloudPrepare,
-		gwDeployer:        gwDeployer,
-		reporter:          reporter.NewEventRecorderWrapper("RHOSCloudProvider", info.EventRecorder),
-		nattDiscoveryPort: int64(info.NATTDiscoveryPort),
-		gateways:          info.Gateways,
+		ports:          ports.For(&info.SubmarinerConfigSpec, info.NetworkType),
+		infraID:        info.InfraID,
+		cloudPrepare:   cloudPrepare,
+		gwDeployer:     gwDeployer,
+		reporter:       reporter.NewEventRecorderWrapper("RHOSCloudProvider", info.EventRecorder, info.StepLog),
+		gateways:       info.Gateways,
+		instanceType:   instanceType,
+		region:         info.Region,
+		providerClient: providerClient,
+		kubeClient:     info.KubeClient,
+		dynamicClient:  info.DynamicClient,
 	}, nil
 }
 
 // PrepareSubmarinerClusterEnv prepares submariner cluster environment on RHOS
 // The below tasks will be executed
-// 1. create the inbound and outbound firewall rules for submariner, below ports will be opened
-//   - NAT traversal port (by default 4500/UDP)
-//   - 4800/UDP port to encapsulate Pod traffic from worker and master nodes to the Submariner Gateway nodes
-//   - ESP & AH protocols for private-ip to private-ip gateway communications
-func (r *rhosProvider) PrepareSubmarinerClusterEnv() error {
+// 1. create the inbound and outbound firewall rules for submariner, the ports planned for the cable driver will be opened
+func (r *rhosProvider) PrepareSubmarinerClusterEnv(ctx context.Context) error {
 	if err := r.gwDeployer.Deploy(api.GatewayDeployInput{
-		PublicPorts: []api.PortSpec{
-			{Port: r.nattPort, Protocol: "udp"},
-			{Port: uint16(r.nattDiscoveryPort), Protocol: "udp"},
-			{Port: 0, Protocol: "esp"},
-			{Port: 0, Protocol: "ah"},
-		},
-		Gateways: r.gateways,
+		PublicPorts: r.ports.Public,
+		Gateways:    r.gateways,
 	}, r.reporter); err != nil {
 		return err
 	}
 
-	if !strings.EqualFold(r.cniType, cni.OVNKubernetes) {
-		if err := r.cloudPrepare.OpenPorts([]api.PortSpec{
-			{Port: constants.SubmarinerRoutePort, Protocol: "udp"},
-		}, r.reporter); err != nil {
+	if len(r.ports.Internal) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := r.cloudPrepare.OpenPorts(r.ports.Internal, r.reporter); err != nil {
 			return err
 		}
 	}
@@ -121,15 +122,49 @@ func (r *rhosProvider) PrepareSubmarinerClusterEnv() error {
 	return nil
 }
 
+// Plan returns the changes PrepareSubmarinerClusterEnv would make on RHOS: the gateway security group opening the public
+// ports, the rules opening the internal ports in the worker and master security groups, and the gateway MachineSets.
+func (r *rhosProvider) Plan(ctx context.Context) ([]configv1alpha1.PlannedChange, error) {
+	changes := []configv1alpha1.PlannedChange{}
+
+	for _, rule := range r.rules() {
+		changes = append(changes, ports.Change(rule.Kind, rule.Name, rule.Ports))
+	}
+
+	machineSets, err := provider.PlanGatewayMachineSets(ctx, r.dynamicClient, r.infraID, r.gateways, r.instanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, machineSets...), nil
+}
+
+// rules returns the gateway security group opening the public ports, and the security groups of the cluster in which the
+// rules opening the internal ports are added.
+func (r *rhosProvider) rules() []ports.Rule {
+	rules := []ports.Rule{{Kind: "SecurityGroup", Name: r.infraID + "-submariner-gw-sg", Ports: r.ports.Public}}
+
+	if len(r.ports.Internal) > 0 {
+		rules = append(rules, ports.Rule{Kind: "SecurityGroupRule", Name: r.infraID + "-worker", Ports: r.ports.Internal},
+			ports.Rule{Kind: "SecurityGroupRule", Name: r.infraID + "-master", Ports: r.ports.Internal})
+	}
+
+	return rules
+}
+
 // CleanUpSubmarinerClusterEnv clean up submariner cluster environment on RHOS after the SubmarinerConfig was deleted
 // 1. delete any dedicated gateways that were previously deployed.
 // 2. delete the inbound and outbound firewall rules to close submariner ports.
-func (r *rhosProvider) CleanUpSubmarinerClusterEnv() error {
+func (r *rhosProvider) CleanUpSubmarinerClusterEnv(ctx context.Context) error {
 	err := r.gwDeployer.Cleanup(r.reporter)
 	if err != nil {
 		return err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	err = r.cloudPrepare.ClosePorts(r.reporter)
 	if err != nil {
 		return err
@@ -143,12 +178,12 @@ func (r *rhosProvider) CleanUpSubmarinerClusterEnv() error {
 func newClient(credentialsSecret *corev1.Secret) (string, string, *gophercloud.ProviderClient, error) {
 	cloudsYAML, ok := credentialsSecret.Data[cloudsYAMLName]
 	if !ok {
-		return "", "", nil, errors.New("cloud yaml is not found in the credentials")
+		return "", "", nil, fmt.Errorf("cloud yaml is not found in the credentials: %w", provider.ErrInvalidCredentials)
 	}
 
 	cloudName, ok := credentialsSecret.Data[cloudName]
 	if !ok {
-		return "", "", nil, errors.New("cloud name is not found in the credentials")
+		return "", "", nil, fmt.Errorf("cloud name is not found in the credentials: %w", provider.ErrInvalidCredentials)
 	}
 
 	var cloudsAll clientconfig.Clouds
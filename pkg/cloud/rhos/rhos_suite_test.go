@@ -0,0 +1,13 @@
+package rhos_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRHOS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RHOS Cloud Provider Suite")
+}
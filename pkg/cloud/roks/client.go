@@ -0,0 +1,312 @@
+package roks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	iamTokenURL = "https://iam.cloud.ibm.com/identity/token"
+	vpcVersion  = "2024-04-30"
+)
+
+// Rule is an IBM Cloud VPC security group rule.
+type Rule struct {
+	ID        string `json:"id,omitempty"`
+	Direction string `json:"direction"`
+	IPVersion string `json:"ip_version,omitempty"`
+	Protocol  string `json:"protocol"`
+	PortMin   int    `json:"port_min"`
+	PortMax   int    `json:"port_max"`
+	Remote    Remote `json:"remote"`
+}
+
+// Remote is the source of the traffic allowed by a Rule, either a CIDR block or a security group.
+type Remote struct {
+	CIDRBlock string `json:"cidr_block,omitempty"`
+	ID        string `json:"id,omitempty"`
+}
+
+// Instance is the IBM Cloud VPC instance of a worker.
+type Instance struct {
+	VPC               string
+	NetworkInterfaces []NetworkInterface
+}
+
+// NetworkInterface is a network interface of an Instance, with the IDs of its security groups.
+type NetworkInterface struct {
+	ID             string
+	SecurityGroups []string
+}
+
+// VPCAPI is the subset of the IBM Cloud VPC API used to open the Submariner ports in a security group targeting the gateway
+// workers.
+type VPCAPI interface {
+	GetInstance(ctx context.Context, instanceID string) (*Instance, error)
+	// FindSecurityGroup returns the ID of the security group with the given name, or an empty string if there's none.
+	FindSecurityGroup(ctx context.Context, name string) (string, error)
+	CreateSecurityGroup(ctx context.Context, name, vpcID string) (string, error)
+	DeleteSecurityGroup(ctx context.Context, securityGroupID string) error
+	ListRules(ctx context.Context, securityGroupID string) ([]Rule, error)
+	CreateRule(ctx context.Context, securityGroupID string, rule *Rule) error
+	DeleteRule(ctx context.Context, securityGroupID, ruleID string) error
+	ListTargets(ctx context.Context, securityGroupID string) ([]string, error)
+	AddTarget(ctx context.Context, securityGroupID, targetID string) error
+	RemoveTarget(ctx context.Context, securityGroupID, targetID string) error
+}
+
+type reference struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type vpcClient struct {
+	apiKey      string
+	endpoint    string
+	httpClient  *http.Client
+	mutex       sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newVPCClient(apiKey, region string) *vpcClient {
+	return &vpcClient{
+		apiKey:     apiKey,
+		endpoint:   fmt.Sprintf("https://%s.iaas.cloud.ibm.com/v1", region),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *vpcClient) GetInstance(ctx context.Context, instanceID string) (*Instance, error) {
+	var instance struct {
+		VPC reference `json:"vpc"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/instances/"+instanceID, nil, &instance); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		NetworkInterfaces []struct {
+			ID             string      `json:"id"`
+			SecurityGroups []reference `json:"security_groups"`
+		} `json:"network_interfaces"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/instances/"+instanceID+"/network_interfaces", nil, &result); err != nil {
+		return nil, err
+	}
+
+	found := &Instance{VPC: instance.VPC.ID}
+
+	for i := range result.NetworkInterfaces {
+		networkInterface := NetworkInterface{ID: result.NetworkInterfaces[i].ID}
+
+		for _, group := range result.NetworkInterfaces[i].SecurityGroups {
+			networkInterface.SecurityGroups = append(networkInterface.SecurityGroups, group.ID)
+		}
+
+		found.NetworkInterfaces = append(found.NetworkInterfaces, networkInterface)
+	}
+
+	return found, nil
+}
+
+func (c *vpcClient) FindSecurityGroup(ctx context.Context, name string) (string, error) {
+	path := "/security_groups"
+
+	for {
+		var result struct {
+			SecurityGroups []reference `json:"security_groups"`
+			Next           *struct {
+				Href string `json:"href"`
+			} `json:"next"`
+		}
+
+		if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return "", err
+		}
+
+		for _, group := range result.SecurityGroups {
+			if group.Name == name {
+				return group.ID, nil
+			}
+		}
+
+		if result.Next == nil {
+			return "", nil
+		}
+
+		next, err := url.Parse(result.Next.Href)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid next page %q", result.Next.Href)
+		}
+
+		path = "/security_groups?start=" + url.QueryEscape(next.Query().Get("start"))
+	}
+}
+
+func (c *vpcClient) CreateSecurityGroup(ctx context.Context, name, vpcID string) (string, error) {
+	var result reference
+
+	err := c.do(ctx, http.MethodPost, "/security_groups", map[string]interface{}{
+		"name": name,
+		"vpc":  reference{ID: vpcID},
+	}, &result)
+
+	return result.ID, err
+}
+
+func (c *vpcClient) DeleteSecurityGroup(ctx context.Context, securityGroupID string) error {
+	return c.do(ctx, http.MethodDelete, "/security_groups/"+securityGroupID, nil, nil)
+}
+
+func (c *vpcClient) ListRules(ctx context.Context, securityGroupID string) ([]Rule, error) {
+	var result struct {
+		Rules []Rule `json:"rules"`
+	}
+
+	err := c.do(ctx, http.MethodGet, "/security_groups/"+securityGroupID+"/rules", nil, &result)
+
+	return result.Rules, err
+}
+
+func (c *vpcClient) CreateRule(ctx context.Context, securityGroupID string, rule *Rule) error {
+	return c.do(ctx, http.MethodPost, "/security_groups/"+securityGroupID+"/rules", rule, nil)
+}
+
+func (c *vpcClient) DeleteRule(ctx context.Context, securityGroupID, ruleID string) error {
+	return c.do(ctx, http.MethodDelete, "/security_groups/"+securityGroupID+"/rules/"+ruleID, nil, nil)
+}
+
+func (c *vpcClient) ListTargets(ctx context.Context, securityGroupID string) ([]string, error) {
+	var result struct {
+		Targets []reference `json:"targets"`
+	}
+
+	// The gateways are few, the first page of 100 targets is enough.
+	if err := c.do(ctx, http.MethodGet, "/security_groups/"+securityGroupID+"/targets?limit=100", nil, &result); err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+
+	for _, target := range result.Targets {
+		ids = append(ids, target.ID)
+	}
+
+	return ids, nil
+}
+
+func (c *vpcClient) AddTarget(ctx context.Context, securityGroupID, targetID string) error {
+	return c.do(ctx, http.MethodPut, "/security_groups/"+securityGroupID+"/targets/"+targetID, nil, nil)
+}
+
+func (c *vpcClient) RemoveTarget(ctx context.Context, securityGroupID, targetID string) error {
+	return c.do(ctx, http.MethodDelete, "/security_groups/"+securityGroupID+"/targets/"+targetID, nil, nil)
+}
+
+func (c *vpcClient) do(ctx context.Context, method, path string, body, result interface{}) error {
+	token, err := c.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method,
+		fmt.Sprintf("%s%s%sversion=%s&generation=2", c.endpoint, path, separator, vpcVersion), reader)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Content-Type", "application/json")
+
+	return c.send(request, result)
+}
+
+func (c *vpcClient) getToken(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ibm:params:oauth:grant-type:apikey")
+	form.Set("apikey", c.apiKey)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, iamTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err := c.send(request, &result); err != nil {
+		return "", errors.Wrap(err, "error retrieving the IAM token")
+	}
+
+	c.token = result.AccessToken
+	// Renew the token a minute before it expires
+	c.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+
+	return c.token, nil
+}
+
+func (c *vpcClient) send(request *http.Request, result interface{}) error {
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s %s returned %d: %s", request.Method, request.URL.Path, response.StatusCode, string(data))
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,222 @@
+package roks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	//#nosec G101 -- This is the name of a key that will store a secret, but not a default secret
+	apiKeySecretKey = "ibmcloud_api_key"
+	instanceIDLabel = "ibm-cloud.kubernetes.io/vpc-instance-id"
+	publicCIDR      = "0.0.0.0/0"
+)
+
+type firewall struct {
+	client      VPCAPI
+	clusterName string
+}
+
+// NewFirewall returns a Firewall for the ROKS cluster described by the given info, using its IBM Cloud API key.
+func NewFirewall(info *provider.Info) (managed.Firewall, error) {
+	if info.Region == "" {
+		return nil, fmt.Errorf("cluster region is empty")
+	}
+
+	apiKey, ok := info.CredentialsSecret.Data[apiKeySecretKey]
+	if !ok {
+		return nil, fmt.Errorf("the ibm cloud credentials key %s is not in secret %s/%s: %w", apiKeySecretKey, info.ClusterName,
+			info.CredentialsSecret.Name, provider.ErrInvalidCredentials)
+	}
+
+	return NewFirewallWithClient(newVPCClient(string(apiKey), info.Region), info.ClusterName), nil
+}
+
+// NewFirewallWithClient returns a Firewall which adds the Submariner rules to a VPC security group named after the given
+// cluster, targeting the network interfaces of the gateway workers, using the given client. The security group only
+// contains Submariner rules, so the rules and targets which are no longer desired can be removed.
+func NewFirewallWithClient(client VPCAPI, clusterName string) managed.Firewall {
+	return &firewall{client: client, clusterName: clusterName}
+}
+
+// OpenPorts ensures the security group of the cluster allows the given ports and targets the gateways, removing the rules
+// and the targets which are no longer desired.
+func (f *firewall) OpenPorts(ctx context.Context, gateways []corev1.Node, ports managed.Ports) error {
+	vpcID, targets, workerGroups, err := f.interfacesFor(ctx, gateways)
+	if err != nil {
+		return err
+	}
+
+	name := managed.ResourceName(f.clusterName, "gateways")
+
+	groupID, err := f.client.FindSecurityGroup(ctx, name)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving security group %q", name)
+	}
+
+	if groupID == "" {
+		if len(gateways) == 0 {
+			return nil
+		}
+
+		groupID, err = f.client.CreateSecurityGroup(ctx, name, vpcID)
+		if err != nil {
+			return errors.Wrapf(err, "error creating security group %q", name)
+		}
+	}
+
+	if err := f.ensureRules(ctx, groupID, rulesFor(workerGroups.Delete(groupID), ports)); err != nil {
+		return err
+	}
+
+	return f.ensureTargets(ctx, groupID, targets)
+}
+
+// ClosePorts deletes the security group of the cluster, they're removed even if no node is labeled as gateway anymore.
+func (f *firewall) ClosePorts(ctx context.Context, _ []corev1.Node, _ managed.Ports) error {
+	name := managed.ResourceName(f.clusterName, "gateways")
+
+	groupID, err := f.client.FindSecurityGroup(ctx, name)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving security group %q", name)
+	}
+
+	if groupID == "" {
+		return nil
+	}
+
+	// A security group can only be deleted once it has no targets.
+	if err := f.ensureTargets(ctx, groupID, sets.New[string]()); err != nil {
+		return err
+	}
+
+	return errors.Wrapf(f.client.DeleteSecurityGroup(ctx, groupID), "error deleting security group %q", name)
+}
+
+func (f *firewall) ensureRules(ctx context.Context, groupID string, desired []Rule) error {
+	existing, err := f.client.ListRules(ctx, groupID)
+	if err != nil {
+		return errors.Wrapf(err, "error listing the rules of security group %q", groupID)
+	}
+
+	for i := range existing {
+		if findRule(desired, &existing[i]) != nil {
+			continue
+		}
+
+		if err := f.client.DeleteRule(ctx, groupID, existing[i].ID); err != nil {
+			return errors.Wrapf(err, "error deleting rule %q in security group %q", existing[i].ID, groupID)
+		}
+	}
+
+	for i := range desired {
+		if findRule(existing, &desired[i]) != nil {
+			continue
+		}
+
+		if err := f.client.CreateRule(ctx, groupID, &desired[i]); err != nil {
+			return errors.Wrapf(err, "error creating a rule in security group %q", groupID)
+		}
+	}
+
+	return nil
+}
+
+func (f *firewall) ensureTargets(ctx context.Context, groupID string, desired sets.Set[string]) error {
+	targets, err := f.client.ListTargets(ctx, groupID)
+	if err != nil {
+		return errors.Wrapf(err, "error listing the targets of security group %q", groupID)
+	}
+
+	existing := sets.New(targets...)
+
+	for _, target := range sets.List(existing.Difference(desired)) {
+		if err := f.client.RemoveTarget(ctx, groupID, target); err != nil {
+			return errors.Wrapf(err, "error removing target %q from security group %q", target, groupID)
+		}
+	}
+
+	for _, target := range sets.List(desired.Difference(existing)) {
+		if err := f.client.AddTarget(ctx, groupID, target); err != nil {
+			return errors.Wrapf(err, "error adding target %q to security group %q", target, groupID)
+		}
+	}
+
+	return nil
+}
+
+// interfacesFor returns the VPC, the network interfaces and the security groups of the network interfaces of the gateways.
+func (f *firewall) interfacesFor(ctx context.Context, gateways []corev1.Node) (string, sets.Set[string], sets.Set[string], error) {
+	var vpcID string
+
+	interfaceIDs := sets.New[string]()
+	groupIDs := sets.New[string]()
+
+	for i := range gateways {
+		instanceID, ok := gateways[i].Labels[instanceIDLabel]
+		if !ok {
+			return "", nil, nil, fmt.Errorf("node %q doesn't have the %q label", gateways[i].Name, instanceIDLabel)
+		}
+
+		instance, err := f.client.GetInstance(ctx, instanceID)
+		if err != nil {
+			return "", nil, nil, errors.Wrapf(err, "error retrieving instance %q", instanceID)
+		}
+
+		vpcID = instance.VPC
+
+		for _, networkInterface := range instance.NetworkInterfaces {
+			interfaceIDs.Insert(networkInterface.ID)
+			groupIDs.Insert(networkInterface.SecurityGroups...)
+		}
+	}
+
+	return vpcID, interfaceIDs, groupIDs, nil
+}
+
+// rulesFor returns the rules opening the public ports to any address, and the internal ports to the given worker security
+// groups.
+func rulesFor(workerGroups sets.Set[string], ports managed.Ports) []Rule {
+	rules := []Rule{}
+
+	for _, port := range ports.Public {
+		rules = append(rules, ruleFor(port, Remote{CIDRBlock: publicCIDR}))
+	}
+
+	for _, groupID := range sets.List(workerGroups) {
+		for _, port := range ports.Internal {
+			rules = append(rules, ruleFor(port, Remote{ID: groupID}))
+		}
+	}
+
+	return rules
+}
+
+func ruleFor(port cpapi.PortSpec, remote Remote) Rule {
+	return Rule{
+		Direction: "inbound",
+		IPVersion: "ipv4",
+		Protocol:  port.Protocol,
+		PortMin:   int(port.Port),
+		PortMax:   int(port.Port),
+		Remote:    remote,
+	}
+}
+
+func findRule(rules []Rule, expected *Rule) *Rule {
+	for i := range rules {
+		if rules[i].Direction == expected.Direction && rules[i].Protocol == expected.Protocol &&
+			rules[i].PortMin == expected.PortMin && rules[i].PortMax == expected.PortMax && rules[i].Remote == expected.Remote {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package roks_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestROKS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ROKS Cloud Provider Suite")
+}
@@ -0,0 +1,251 @@
+package roks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/submariner-addon/pkg/cloud/managed"
+	"github.com/stolostron/submariner-addon/pkg/cloud/roks"
+	cpapi "github.com/submariner-io/cloud-prepare/pkg/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	clusterName = "east"
+	groupName   = "submariner-east-gateways"
+)
+
+type fakeVPC struct {
+	instances map[string]*roks.Instance
+	groups    map[string]string
+	rules     map[string][]roks.Rule
+	targets   map[string][]string
+	createErr error
+	nextID    int
+}
+
+func (f *fakeVPC) GetInstance(_ context.Context, instanceID string) (*roks.Instance, error) {
+	instance, ok := f.instances[instanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance %q not found", instanceID)
+	}
+
+	return instance, nil
+}
+
+func (f *fakeVPC) FindSecurityGroup(_ context.Context, name string) (string, error) {
+	for id, groupName := range f.groups {
+		if groupName == name {
+			return id, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (f *fakeVPC) CreateSecurityGroup(_ context.Context, name, vpcID string) (string, error) {
+	Expect(vpcID).To(Equal("vpc-1"))
+
+	f.nextID++
+	id := fmt.Sprintf("sg-%d", f.nextID)
+	f.groups[id] = name
+
+	return id, nil
+}
+
+func (f *fakeVPC) DeleteSecurityGroup(_ context.Context, securityGroupID string) error {
+	if len(f.targets[securityGroupID]) > 0 {
+		return fmt.Errorf("security group %q still has targets", securityGroupID)
+	}
+
+	delete(f.groups, securityGroupID)
+	delete(f.rules, securityGroupID)
+
+	return nil
+}
+
+func (f *fakeVPC) ListRules(_ context.Context, securityGroupID string) ([]roks.Rule, error) {
+	return f.rules[securityGroupID], nil
+}
+
+func (f *fakeVPC) CreateRule(_ context.Context, securityGroupID string, rule *roks.Rule) error {
+	if f.createErr != nil {
+		return f.createErr
+	}
+
+	f.nextID++
+
+	created := *rule
+	created.ID = fmt.Sprintf("rule-%d", f.nextID)
+	f.rules[securityGroupID] = append(f.rules[securityGroupID], created)
+
+	return nil
+}
+
+func (f *fakeVPC) DeleteRule(_ context.Context, securityGroupID, ruleID string) error {
+	f.rules[securityGroupID] = slices.DeleteFunc(f.rules[securityGroupID], func(rule roks.Rule) bool {
+		return rule.ID == ruleID
+	})
+
+	return nil
+}
+
+func (f *fakeVPC) ListTargets(_ context.Context, securityGroupID string) ([]string, error) {
+	return f.targets[securityGroupID], nil
+}
+
+func (f *fakeVPC) AddTarget(_ context.Context, securityGroupID, targetID string) error {
+	f.targets[securityGroupID] = append(f.targets[securityGroupID], targetID)
+
+	return nil
+}
+
+func (f *fakeVPC) RemoveTarget(_ context.Context, securityGroupID, targetID string) error {
+	f.targets[securityGroupID] = slices.DeleteFunc(f.targets[securityGroupID], func(id string) bool {
+		return id == targetID
+	})
+
+	return nil
+}
+
+// rulesOf returns the ports opened by the rules of the Submariner security group, as <port>/<remote>.
+func (f *fakeVPC) rulesOf() []string {
+	opened := []string{}
+
+	for id, name := range f.groups {
+		if name != groupName {
+			continue
+		}
+
+		for _, rule := range f.rules[id] {
+			opened = append(opened, fmt.Sprintf("%d/%s%s", rule.PortMin, rule.Remote.CIDRBlock, rule.Remote.ID))
+		}
+	}
+
+	return opened
+}
+
+func (f *fakeVPC) targetsOf() []string {
+	for id, name := range f.groups {
+		if name == groupName {
+			return f.targets[id]
+		}
+	}
+
+	return nil
+}
+
+var _ = Describe("ROKS firewall", func() {
+	var (
+		client   *fakeVPC
+		firewall managed.Firewall
+		gateways []corev1.Node
+		ports    managed.Ports
+	)
+
+	BeforeEach(func() {
+		client = &fakeVPC{
+			instances: map[string]*roks.Instance{
+				"instance-1": {VPC: "vpc-1", NetworkInterfaces: []roks.NetworkInterface{{ID: "nic-1", SecurityGroups: []string{"sg-workers"}}}},
+				"instance-2": {VPC: "vpc-1", NetworkInterfaces: []roks.NetworkInterface{{ID: "nic-2", SecurityGroups: []string{"sg-workers"}}}},
+			},
+			groups:  map[string]string{"sg-workers": "kube-workers"},
+			rules:   map[string][]roks.Rule{"sg-workers": {{ID: "other", Direction: "inbound", Protocol: "tcp", PortMin: 22, PortMax: 22}}},
+			targets: map[string][]string{},
+		}
+
+		firewall = roks.NewFirewallWithClient(client, clusterName)
+
+		gateways = []corev1.Node{newNode("node-1", "instance-1"), newNode("node-2", "instance-2")}
+
+		ports = managed.Ports{
+			Public:   []cpapi.PortSpec{{Port: 4500, Protocol: "udp"}, {Port: 4490, Protocol: "udp"}},
+			Internal: []cpapi.PortSpec{{Port: 4800, Protocol: "udp"}},
+		}
+	})
+
+	Context("OpenPorts", func() {
+		It("should create the rules in a security group of the cluster targeting the gateway workers", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.rulesOf()).To(ConsistOf("4500/0.0.0.0/0", "4490/0.0.0.0/0", "4800/sg-workers"))
+			Expect(client.targetsOf()).To(ConsistOf("nic-1", "nic-2"))
+			Expect(client.rules["sg-workers"]).To(HaveLen(1))
+		})
+
+		It("should not duplicate existing rules", func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+			Expect(client.rulesOf()).To(HaveLen(3))
+			Expect(client.targetsOf()).To(HaveLen(2))
+		})
+
+		When("a port changed", func() {
+			It("should delete the rule of the previous port", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+
+				ports.Public = ports.Public[:1]
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(client.rulesOf()).To(ConsistOf("4500/0.0.0.0/0", "4800/sg-workers"))
+			})
+		})
+
+		When("a node isn't a gateway anymore", func() {
+			It("should remove its network interface from the targets", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+				Expect(firewall.OpenPorts(context.TODO(), gateways[1:], ports)).To(Succeed())
+				Expect(client.targetsOf()).To(ConsistOf("nic-2"))
+			})
+		})
+
+		When("creating a rule fails", func() {
+			BeforeEach(func() {
+				client.createErr = errors.New("fake error")
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+
+		When("a node doesn't have the VPC instance ID label", func() {
+			BeforeEach(func() {
+				gateways[0].Labels = map[string]string{}
+			})
+
+			It("should return an error", func() {
+				Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).ToNot(Succeed())
+			})
+		})
+	})
+
+	Context("ClosePorts", func() {
+		BeforeEach(func() {
+			Expect(firewall.OpenPorts(context.TODO(), gateways, ports)).To(Succeed())
+		})
+
+		It("should delete the security group of the cluster, even without gateways", func() {
+			Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+			Expect(client.groups).To(Equal(map[string]string{"sg-workers": "kube-workers"}))
+			Expect(client.rules["sg-workers"]).To(HaveLen(1))
+		})
+
+		It("should succeed when the security group was already deleted", func() {
+			Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+			Expect(firewall.ClosePorts(context.TODO(), nil, ports)).To(Succeed())
+		})
+	})
+})
+
+func newNode(name, instanceID string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"ibm-cloud.kubernetes.io/vpc-instance-id": instanceID},
+		},
+	}
+}
@@ -9,9 +9,16 @@ import (
 
 func NewController() *cobra.Command {
 	addOnOptions := hub.NewAddOnOptions()
-	cmd := controllercmd.
-		NewControllerCommandConfig("submariner-controller", version.Get(), addOnOptions.RunControllerManager).
-		NewCommand()
+
+	// The lease is named after the component and created in the namespace of the controller
+	leaderElection := hub.LeaderElection()
+
+	cmdConfig := controllercmd.NewControllerCommandConfig(hub.ControllerName, version.Get(), addOnOptions.RunControllerManager)
+	cmdConfig.LeaseDuration = leaderElection.LeaseDuration
+	cmdConfig.RenewDeadline = leaderElection.RenewDeadline
+	cmdConfig.RetryPeriod = leaderElection.RetryPeriod
+
+	cmd := cmdConfig.NewCommand()
 	cmd.Use = "controller"
 	cmd.Short = "Start the ACM Submariner Controller"
 
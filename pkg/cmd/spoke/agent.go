@@ -11,6 +11,7 @@ func NewAgent() *cobra.Command {
 	agentOptions := spoke.NewAgentOptions()
 	cmd := controllercmd.
 		NewControllerCommandConfig("submariner-agent", version.Get(), agentOptions.RunAgent).
+		WithHealthChecks(agentOptions.HealthChecks()...).
 		NewCommand()
 	cmd.Use = "agent"
 	cmd.Short = "Start the ACM Submariner Agent"
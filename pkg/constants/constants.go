@@ -9,11 +9,25 @@ const (
 	ProductROSA       = "ROSA"
 	ProductARO        = "ARO"
 	ProductROKS       = "ROKS"
+	ProductEKS        = "EKS"
+	ProductGKE        = "GKE"
+	ProductAKS        = "AKS"
+	ProductIKS        = "IKS"
 	OCPVersionForOVNK = "4.11.0-rc"
 
 	IPSecPSKSecretName = "submariner-ipsec-psk"
 
+	// InstallModeLabel is set on the submariner-operator Deployment when the operator is installed from manifests
+	// rather than through Operator Lifecycle Manager.
+	InstallModeLabel = "submarineraddon.open-cluster-management.io/install-mode"
+
+	SubmarinerIKEPort           = 500
 	SubmarinerNatTPort          = 4500
 	SubmarinerNatTDiscoveryPort = 4900
 	SubmarinerRoutePort         = 4800
 )
+
+// IsOpenShiftProduct returns whether the given managed cluster product is an OpenShift offering.
+func IsOpenShiftProduct(product string) bool {
+	return product == ProductOCP || product == ProductROSA || product == ProductARO || product == ProductROKS
+}
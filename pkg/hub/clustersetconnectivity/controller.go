@@ -0,0 +1,335 @@
+package clustersetconnectivity
+
+import (
+	"context"
+	"sort"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/pkg/errors"
+	connectivityv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/clustersetconnectivity/v1alpha1"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	connectivityclient "github.com/stolostron/submariner-addon/pkg/client/clustersetconnectivity/clientset/versioned"
+	configinformer "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions/submarinerconfig/v1alpha1"
+	configlister "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/listers/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/hub/submarinerbroker"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
+	"github.com/submariner-io/admiral/pkg/log"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("ClusterSetConnectivityController")}
+
+var connected = string(submarinerv1.Connected)
+
+// clusterSetConnectivityController aggregates the connections reported by the clusters of every ManagedClusterSet into a
+// ClusterSetConnectivity in the broker namespace of the ManagedClusterSet.
+type clusterSetConnectivityController struct {
+	connectivityClient connectivityclient.Interface
+	clusterLister      clusterlisterv1.ManagedClusterLister
+	clusterSetLister   clusterlisterv1beta2.ManagedClusterSetLister
+	addOnLister        addonlisterv1alpha1.ManagedClusterAddOnLister
+	configLister       configlister.SubmarinerConfigLister
+}
+
+func NewController(connectivityClient connectivityclient.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
+	addOnInformer addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	configInformer configinformer.SubmarinerConfigInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &clusterSetConnectivityController{
+		connectivityClient: connectivityClient,
+		clusterLister:      clusterInformer.Lister(),
+		clusterSetLister:   clusterSetInformer.Lister(),
+		addOnLister:        addOnInformer.Lister(),
+		configLister:       configInformer.Lister(),
+	}
+
+	name := "ClusterSetConnectivityController"
+	syncCtx := factory.NewSyncContext(name, recorder)
+
+	// A ManagedCluster moving to another ManagedClusterSet affects the connectivity of both ManagedClusterSets.
+	_, err := clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			enqueueClusterSetOf(syncCtx.Queue(), obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			enqueueClusterSetOf(syncCtx.Queue(), oldObj)
+			enqueueClusterSetOf(syncCtx.Queue(), newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			enqueueClusterSetOf(syncCtx.Queue(), obj)
+		},
+	})
+	if err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "error adding the ManagedCluster event handler"))
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
+			accessor, _ := meta.Accessor(obj)
+			logger.V(log.DEBUG).Infof("Queuing ManagedClusterSet %q", accessor.GetName())
+
+			return accessor.GetName()
+		}, clusterSetInformer.Informer()).
+		WithBareInformers(clusterInformer.Informer()).
+		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
+			accessor, _ := meta.Accessor(obj)
+			if accessor.GetName() != constants.SubmarinerAddOnName {
+				return ""
+			}
+
+			return c.clusterSetOf(accessor.GetNamespace())
+		}, addOnInformer.Informer()).
+		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
+			accessor, _ := meta.Accessor(obj)
+			if accessor.GetName() != constants.SubmarinerConfigName {
+				return ""
+			}
+
+			return c.clusterSetOf(accessor.GetNamespace())
+		}, configInformer.Informer()).
+		WithSync(metrics.InstrumentSync(name, c.sync)).
+		ToController(name, recorder)
+}
+
+// enqueueClusterSetOf queues the ManagedClusterSet the given ManagedCluster is labeled with, if any.
+func enqueueClusterSetOf(queue workqueue.RateLimitingInterface, obj interface{}) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	if clusterSetName := accessor.GetLabels()[clusterv1beta2.ClusterSetLabel]; clusterSetName != "" {
+		logger.V(log.DEBUG).Infof("Queuing ManagedClusterSet %q for cluster %q", clusterSetName, accessor.GetName())
+		queue.Add(clusterSetName)
+	}
+}
+
+// clusterSetOf returns the name of the ManagedClusterSet of the given cluster, if any.
+func (c *clusterSetConnectivityController) clusterSetOf(clusterName string) string {
+	cluster, err := c.clusterLister.Get(clusterName)
+	if err != nil {
+		return ""
+	}
+
+	clusterSetName := cluster.Labels[clusterv1beta2.ClusterSetLabel]
+	if clusterSetName != "" {
+		logger.V(log.DEBUG).Infof("Queuing ManagedClusterSet %q for cluster %q", clusterSetName, clusterName)
+	}
+
+	return clusterSetName
+}
+
+func (c *clusterSetConnectivityController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	if syncCtx.QueueKey() == "" || syncCtx.QueueKey() == factory.DefaultQueueKey {
+		return nil
+	}
+
+	logger.V(log.TRACE).Infof("Entering sync for %q", syncCtx.QueueKey())
+	defer logger.V(log.TRACE).Infof("Exiting sync for %q", syncCtx.QueueKey())
+
+	clusterSet, err := c.clusterSetLister.Get(syncCtx.QueueKey())
+	if apierrors.IsNotFound(err) {
+		// The ClusterSetConnectivity is deleted with the broker namespace.
+		metrics.DeleteClusters(syncCtx.QueueKey())
+
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	status, err := c.buildStatus(clusterSet.Name)
+	if err != nil {
+		return err
+	}
+
+	metrics.SetClusters(clusterSet.Name, len(status.Clusters))
+
+	brokerNS := clusterSet.GetAnnotations()[submarinerbroker.SubmBrokerNamespaceKey]
+	if brokerNS == "" || !clusterSet.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	return c.applyStatus(ctx, brokerNS, clusterSet.Name, status)
+}
+
+// buildStatus builds the connectivity of the clusters of the given ManagedClusterSet from the gateway status published in
+// their SubmarinerConfig.
+func (c *clusterSetConnectivityController) buildStatus(clusterSetName string) (*connectivityv1alpha1.ClusterSetConnectivityStatus,
+	error,
+) {
+	clusters, err := c.clusterLister.List(labels.SelectorFromSet(labels.Set{clusterv1beta2.ClusterSetLabel: clusterSetName}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the clusters of ManagedClusterSet %q", clusterSetName)
+	}
+
+	status := &connectivityv1alpha1.ClusterSetConnectivityStatus{}
+	connections := map[string]map[string]*configv1alpha1.GatewayConnectionStatus{}
+
+	for _, cluster := range clusters {
+		addOn, err := c.addOnLister.ManagedClusterAddOns(cluster.Name).Get(constants.SubmarinerAddOnName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !addOn.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		status.Clusters = append(status.Clusters, cluster.Name)
+
+		config, err := c.configLister.SubmarinerConfigs(cluster.Name).Get(constants.SubmarinerConfigName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		connections[cluster.Name] = activeConnectionsOf(config)
+	}
+
+	sort.Strings(status.Clusters)
+
+	statusOf := func(source, destination string) string {
+		if connection, found := connections[source][destination]; found {
+			return connection.Status
+		}
+
+		return connectivityv1alpha1.ConnectionMissing
+	}
+
+	for _, source := range status.Clusters {
+		row := connectivityv1alpha1.ConnectivityRow{Cluster: source}
+
+		for _, destination := range status.Clusters {
+			if destination == source {
+				continue
+			}
+
+			cell := connectivityv1alpha1.ConnectivityCell{
+				Cluster: destination,
+				Status:  statusOf(source, destination),
+			}
+
+			if connection, found := connections[source][destination]; found && connection.LatencyRTT != nil {
+				cell.AverageRTT = connection.LatencyRTT.Average
+			}
+
+			row.Connections = append(row.Connections, cell)
+
+			if cell.Status == connected && statusOf(destination, source) != connected {
+				status.AsymmetricPairs = append(status.AsymmetricPairs, connectivityv1alpha1.ClusterPair{
+					Source:      source,
+					Destination: destination,
+				})
+			}
+		}
+
+		status.Matrix = append(status.Matrix, row)
+	}
+
+	for i, first := range status.Clusters {
+		for _, second := range status.Clusters[i+1:] {
+			there, back := statusOf(first, second), statusOf(second, first)
+
+			switch {
+			case there == connected && back == connected:
+				status.HealthyLinks++
+			case there == connectivityv1alpha1.ConnectionMissing && back == connectivityv1alpha1.ConnectionMissing:
+				status.MissingLinks++
+			default:
+				status.DegradedLinks++
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// activeConnectionsOf returns the connections of the active gateway of the given SubmarinerConfig, by remote cluster.
+func activeConnectionsOf(config *configv1alpha1.SubmarinerConfig) map[string]*configv1alpha1.GatewayConnectionStatus {
+	connections := map[string]*configv1alpha1.GatewayConnectionStatus{}
+
+	for i := range config.Status.Gateways {
+		gateway := &config.Status.Gateways[i]
+		if gateway.HAStatus != string(submarinerv1.HAStatusActive) {
+			continue
+		}
+
+		for j := range gateway.Connections {
+			connections[gateway.Connections[j].ClusterID] = &gateway.Connections[j]
+		}
+	}
+
+	return connections
+}
+
+func (c *clusterSetConnectivityController) applyStatus(ctx context.Context, brokerNS, name string,
+	status *connectivityv1alpha1.ClusterSetConnectivityStatus,
+) error {
+	client := c.connectivityClient.SubmarineraddonV1alpha1().ClusterSetConnectivities(brokerNS)
+
+	connectivity, err := client.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		connectivity, err = client.Create(ctx, &connectivityv1alpha1.ClusterSetConnectivity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: brokerNS,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "error creating ClusterSetConnectivity %q in namespace %q", name, brokerNS)
+		}
+
+		logger.Infof("Created ClusterSetConnectivity %q in namespace %q", name, brokerNS)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving ClusterSetConnectivity %q in namespace %q", name, brokerNS)
+	}
+
+	if equality.Semantic.DeepEqual(&connectivity.Status, status) {
+		return nil
+	}
+
+	connectivity = connectivity.DeepCopy()
+	connectivity.Status = *status
+
+	_, err = client.UpdateStatus(ctx, connectivity, metav1.UpdateOptions{})
+
+	return errors.Wrapf(err, "error updating the status of ClusterSetConnectivity %q in namespace %q", name, brokerNS)
+}
@@ -0,0 +1,341 @@
+package clustersetconnectivity_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/library-go/pkg/operator/events"
+	connectivityv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/clustersetconnectivity/v1alpha1"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	fakeconnectivityclient "github.com/stolostron/submariner-addon/pkg/client/clustersetconnectivity/clientset/versioned/fake"
+	fakeconfigclient "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/clientset/versioned/fake"
+	configinformers "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/hub/clustersetconnectivity"
+	"github.com/stolostron/submariner-addon/pkg/hub/submarinerbroker"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+const (
+	clusterSetName = "east"
+	brokerNS       = "east-broker"
+)
+
+var _ = Describe("Controller", func() {
+	t := newTestDriver()
+
+	When("the clusters of a ManagedClusterSet report their connections", func() {
+		BeforeEach(func() {
+			t.addCluster("cluster1", gateway("active", connection("cluster2", "connected", "1ms"), connection("cluster3", "connected", "")))
+			t.addCluster("cluster2", gateway("active", connection("cluster1", "connected", "2ms")))
+			t.addCluster("cluster3", gateway("active", connection("cluster1", "error", "")))
+		})
+
+		It("should create the ClusterSetConnectivity with the connectivity matrix", func() {
+			status := t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+				return len(status.Matrix) == 3
+			})
+
+			Expect(status.Clusters).To(Equal([]string{"cluster1", "cluster2", "cluster3"}))
+			Expect(status.Matrix).To(Equal([]connectivityv1alpha1.ConnectivityRow{
+				{
+					Cluster: "cluster1",
+					Connections: []connectivityv1alpha1.ConnectivityCell{
+						{Cluster: "cluster2", Status: "connected", AverageRTT: "1ms"},
+						{Cluster: "cluster3", Status: "connected"},
+					},
+				},
+				{
+					Cluster: "cluster2",
+					Connections: []connectivityv1alpha1.ConnectivityCell{
+						{Cluster: "cluster1", Status: "connected", AverageRTT: "2ms"},
+						{Cluster: "cluster3", Status: connectivityv1alpha1.ConnectionMissing},
+					},
+				},
+				{
+					Cluster: "cluster3",
+					Connections: []connectivityv1alpha1.ConnectivityCell{
+						{Cluster: "cluster1", Status: "error"},
+						{Cluster: "cluster2", Status: connectivityv1alpha1.ConnectionMissing},
+					},
+				},
+			}))
+		})
+
+		It("should report the asymmetric pairs and count the links", func() {
+			status := t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+				return len(status.Matrix) == 3
+			})
+
+			Expect(status.AsymmetricPairs).To(Equal([]connectivityv1alpha1.ClusterPair{{Source: "cluster1", Destination: "cluster3"}}))
+			Expect(status.HealthyLinks).To(Equal(int32(1)))
+			Expect(status.DegradedLinks).To(Equal(int32(1)))
+			Expect(status.MissingLinks).To(Equal(int32(1)))
+		})
+
+		Context("and a connection is subsequently established", func() {
+			It("should update the ClusterSetConnectivity", func() {
+				t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+					return status.DegradedLinks == 1
+				})
+
+				t.setGateways("cluster3", gateway("active", connection("cluster1", "connected", "")))
+
+				status := t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+					return status.HealthyLinks == 2
+				})
+
+				Expect(status.AsymmetricPairs).To(BeEmpty())
+				Expect(status.DegradedLinks).To(BeZero())
+				Expect(status.MissingLinks).To(Equal(int32(1)))
+			})
+		})
+	})
+
+	When("a cluster moves to another ManagedClusterSet", func() {
+		BeforeEach(func() {
+			t.addCluster("cluster1", gateway("active", connection("cluster2", "connected", "")))
+			t.addCluster("cluster2", gateway("active", connection("cluster1", "connected", "")))
+		})
+
+		It("should remove it from the ClusterSetConnectivity of its previous ManagedClusterSet", func() {
+			t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+				return len(status.Clusters) == 2
+			})
+
+			cluster, err := t.clusterClient.ClusterV1().ManagedClusters().Get(context.TODO(), "cluster2", metav1.GetOptions{})
+			Expect(err).To(Succeed())
+
+			cluster.Labels[clusterv1beta2.ClusterSetLabel] = "west"
+			_, err = t.clusterClient.ClusterV1().ManagedClusters().Update(context.TODO(), cluster, metav1.UpdateOptions{})
+			Expect(err).To(Succeed())
+
+			status := t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+				return len(status.Clusters) == 1
+			})
+
+			Expect(status.Clusters).To(Equal([]string{"cluster1"}))
+		})
+	})
+
+	When("a cluster reports connections from a passive gateway", func() {
+		BeforeEach(func() {
+			t.addCluster("cluster1", gateway("active"), gateway("passive", connection("cluster2", "connected", "")))
+			t.addCluster("cluster2")
+		})
+
+		It("should ignore them", func() {
+			status := t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+				return len(status.Clusters) == 2
+			})
+
+			Expect(status.Matrix[0].Connections).To(Equal([]connectivityv1alpha1.ConnectivityCell{
+				{Cluster: "cluster2", Status: connectivityv1alpha1.ConnectionMissing},
+			}))
+			Expect(status.MissingLinks).To(Equal(int32(1)))
+		})
+	})
+
+	When("a cluster of the ManagedClusterSet doesn't have the submariner addon", func() {
+		BeforeEach(func() {
+			t.addCluster("cluster1")
+			t.addCluster("cluster2")
+			t.noAddOn["cluster2"] = true
+		})
+
+		It("should exclude it", func() {
+			status := t.awaitConnectivity(func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool {
+				return len(status.Clusters) == 1
+			})
+
+			Expect(status.Clusters).To(Equal([]string{"cluster1"}))
+			Expect(status.MissingLinks).To(BeZero())
+		})
+	})
+
+	When("the ManagedClusterSet doesn't have a broker namespace", func() {
+		BeforeEach(func() {
+			t.clusterSet.Annotations = nil
+			t.addCluster("cluster1")
+		})
+
+		It("should not create the ClusterSetConnectivity", func() {
+			Consistently(func() bool {
+				_, err := t.connectivityClient.SubmarineraddonV1alpha1().ClusterSetConnectivities(brokerNS).Get(context.TODO(),
+					clusterSetName, metav1.GetOptions{})
+
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+	})
+})
+
+type testDriver struct {
+	clusterClient      *clusterfake.Clientset
+	addOnClient        *addonfake.Clientset
+	configClient       *fakeconfigclient.Clientset
+	connectivityClient *fakeconnectivityclient.Clientset
+	clusterSet         *clusterv1beta2.ManagedClusterSet
+	configs            []*configv1alpha1.SubmarinerConfig
+	noAddOn            map[string]bool
+	stop               context.CancelFunc
+}
+
+func newTestDriver() *testDriver {
+	t := &testDriver{}
+
+	BeforeEach(func() {
+		t.clusterSet = &clusterv1beta2.ManagedClusterSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        clusterSetName,
+				Annotations: map[string]string{submarinerbroker.SubmBrokerNamespaceKey: brokerNS},
+			},
+		}
+
+		t.configs = nil
+		t.noAddOn = map[string]bool{}
+		t.clusterClient = clusterfake.NewSimpleClientset()
+		t.addOnClient = addonfake.NewSimpleClientset()
+		t.configClient = fakeconfigclient.NewSimpleClientset()
+		t.connectivityClient = fakeconnectivityclient.NewSimpleClientset()
+	})
+
+	JustBeforeEach(func() {
+		_, err := t.clusterClient.ClusterV1beta2().ManagedClusterSets().Create(context.TODO(), t.clusterSet, metav1.CreateOptions{})
+		Expect(err).To(Succeed())
+
+		for _, config := range t.configs {
+			_, err := t.clusterClient.ClusterV1().ManagedClusters().Create(context.TODO(), &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   config.Namespace,
+					Labels: map[string]string{clusterv1beta2.ClusterSetLabel: clusterSetName},
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+
+			if !t.noAddOn[config.Namespace] {
+				_, err = t.addOnClient.AddonV1alpha1().ManagedClusterAddOns(config.Namespace).Create(context.TODO(),
+					&addonv1alpha1.ManagedClusterAddOn{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      constants.SubmarinerAddOnName,
+							Namespace: config.Namespace,
+						},
+					}, metav1.CreateOptions{})
+				Expect(err).To(Succeed())
+			}
+
+			_, err = t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace).Create(context.TODO(), config,
+				metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+		}
+
+		clusterInformerFactory := clusterinformers.NewSharedInformerFactory(t.clusterClient, 0)
+		addOnInformerFactory := addoninformers.NewSharedInformerFactory(t.addOnClient, 0)
+		configInformerFactory := configinformers.NewSharedInformerFactory(t.configClient, 0)
+
+		controller := clustersetconnectivity.NewController(
+			t.connectivityClient,
+			clusterInformerFactory.Cluster().V1().ManagedClusters(),
+			clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+			addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
+			configInformerFactory.Submarineraddon().V1alpha1().SubmarinerConfigs(),
+			events.NewLoggingEventRecorder("test"))
+
+		var ctx context.Context
+
+		ctx, t.stop = context.WithCancel(context.TODO())
+
+		clusterInformerFactory.Start(ctx.Done())
+		addOnInformerFactory.Start(ctx.Done())
+		configInformerFactory.Start(ctx.Done())
+
+		cache.WaitForCacheSync(ctx.Done(),
+			clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().HasSynced,
+			clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().HasSynced,
+			addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer().HasSynced,
+			configInformerFactory.Submarineraddon().V1alpha1().SubmarinerConfigs().Informer().HasSynced)
+
+		go controller.Run(ctx, 1)
+	})
+
+	AfterEach(func() {
+		t.stop()
+	})
+
+	return t
+}
+
+func (t *testDriver) addCluster(name string, gateways ...configv1alpha1.GatewayStatus) {
+	t.configs = append(t.configs, &configv1alpha1.SubmarinerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.SubmarinerConfigName,
+			Namespace: name,
+		},
+		Status: configv1alpha1.SubmarinerConfigStatus{
+			Gateways: gateways,
+		},
+	})
+}
+
+func (t *testDriver) setGateways(clusterName string, gateways ...configv1alpha1.GatewayStatus) {
+	config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+		constants.SubmarinerConfigName, metav1.GetOptions{})
+	Expect(err).To(Succeed())
+
+	config.Status.Gateways = gateways
+
+	_, err = t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).UpdateStatus(context.TODO(), config,
+		metav1.UpdateOptions{})
+	Expect(err).To(Succeed())
+}
+
+func (t *testDriver) awaitConnectivity(
+	matches func(status *connectivityv1alpha1.ClusterSetConnectivityStatus) bool,
+) *connectivityv1alpha1.ClusterSetConnectivityStatus {
+	var status *connectivityv1alpha1.ClusterSetConnectivityStatus
+
+	Eventually(func() bool {
+		connectivity, err := t.connectivityClient.SubmarineraddonV1alpha1().ClusterSetConnectivities(brokerNS).Get(context.TODO(),
+			clusterSetName, metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+
+		status = &connectivity.Status
+
+		return matches(status)
+	}).Should(BeTrue(), "ClusterSetConnectivity not found or not as expected")
+
+	return status
+}
+
+func gateway(haStatus string, connections ...configv1alpha1.GatewayConnectionStatus) configv1alpha1.GatewayStatus {
+	return configv1alpha1.GatewayStatus{
+		NodeName:    "gateway-" + haStatus,
+		HAStatus:    haStatus,
+		Connections: connections,
+	}
+}
+
+func connection(clusterID, status, averageRTT string) configv1alpha1.GatewayConnectionStatus {
+	connection := configv1alpha1.GatewayConnectionStatus{
+		ClusterID: clusterID,
+		Status:    status,
+	}
+
+	if averageRTT != "" {
+		connection.LatencyRTT = &configv1alpha1.LatencyRTTStats{Average: averageRTT}
+	}
+
+	return connection
+}
@@ -0,0 +1,26 @@
+package hub
+
+import (
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ControllerName is the name of the hub controller manager, which also names its leader election lease.
+const ControllerName = "submariner-controller"
+
+// LeaderElection returns the durations of the leader election of the replicas of the hub controller manager, over the
+// ControllerName-lock Lease in the namespace of the controller. Only the leader runs the controllers and the addon manager;
+// a replica taking over re-lists all the resources and only updates the ManifestWorks which differ from the expected ones.
+//
+// The durations allow for 20s of clock skew and tolerate 30s of kube-apiserver disruption. A replica takes over within
+// 10s when the leader releases the lease on shutdown, and within 70s when the leader dies: once the lease expires after 60s,
+// at its next retry.
+func LeaderElection() configv1.LeaderElection {
+	return configv1.LeaderElection{
+		LeaseDuration: metav1.Duration{Duration: 60 * time.Second},
+		RenewDeadline: metav1.Duration{Duration: 40 * time.Second},
+		RetryPeriod:   metav1.Duration{Duration: 10 * time.Second},
+	}
+}
@@ -8,11 +8,14 @@ import (
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/spf13/cobra"
+	connectivityclient "github.com/stolostron/submariner-addon/pkg/client/clustersetconnectivity/clientset/versioned"
 	configclient "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/clientset/versioned"
 	configinformers "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions"
+	"github.com/stolostron/submariner-addon/pkg/hub/clustersetconnectivity"
 	"github.com/stolostron/submariner-addon/pkg/hub/submarineraddonagent"
 	"github.com/stolostron/submariner-addon/pkg/hub/submarineragent"
 	"github.com/stolostron/submariner-addon/pkg/hub/submarinerbroker"
+	brokerinfo "github.com/stolostron/submariner-addon/pkg/hub/submarinerbrokerinfo"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	submarinerv1alpha1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
@@ -40,17 +43,21 @@ import (
 )
 
 const (
-	containerName                = "submariner-addon"
-	defaultNamespace             = "open-cluster-management"
-	accessToBrokerCRDClusterRole = "access-to-brokers-submariner-crd"
+	containerName                 = "submariner-addon"
+	defaultNamespace              = "open-cluster-management"
+	accessToBrokerCRDClusterRole  = "access-to-brokers-submariner-crd"
+	defaultAgentControllerWorkers = 5
 )
 
 type AddOnOptions struct {
-	AgentImage string
+	AgentImage             string
+	AgentControllerWorkers int
 }
 
 func NewAddOnOptions() *AddOnOptions {
-	return &AddOnOptions{}
+	return &AddOnOptions{
+		AgentControllerWorkers: defaultAgentControllerWorkers,
+	}
 }
 
 func (o *AddOnOptions) AddFlags(cmd *cobra.Command) {
@@ -58,6 +65,8 @@ func (o *AddOnOptions) AddFlags(cmd *cobra.Command) {
 	// TODO if downstream building supports to set downstream image, we could use this flag
 	// to set agent image on building phase
 	flags.StringVar(&o.AgentImage, "agent-image", o.AgentImage, "The image of addon agent.")
+	flags.IntVar(&o.AgentControllerWorkers, "agent-controller-workers", o.AgentControllerWorkers,
+		"The number of managed clusters the agent controller reconciles concurrently.")
 }
 
 func (o *AddOnOptions) Complete(ctx context.Context, kubeClient kubernetes.Interface) error {
@@ -123,6 +132,11 @@ func (o *AddOnOptions) RunControllerManager(ctx context.Context, controllerConte
 		return err
 	}
 
+	connectivityClient, err := connectivityclient.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+
 	apiExtensionClient, err := apiextensionsclientset.NewForConfig(controllerContext.KubeConfig)
 	if err != nil {
 		return err
@@ -179,6 +193,8 @@ func (o *AddOnOptions) RunControllerManager(ctx context.Context, controllerConte
 		addOnInformers.Addon().V1alpha1(),
 		controllerContext.EventRecorder)
 
+	brokerInfoCache := brokerinfo.NewCache(kubeClient, dynamicClient)
+
 	submarinerAgentController := submarineragent.NewSubmarinerAgentController(
 		kubeClient,
 		dynamicClient,
@@ -187,6 +203,7 @@ func (o *AddOnOptions) RunControllerManager(ctx context.Context, controllerConte
 		workClient,
 		configClient,
 		addOnClient,
+		brokerInfoCache,
 		clusterInformers.Cluster().V1().ManagedClusters(),
 		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
 		workInformers.Work().V1().ManifestWorks(),
@@ -197,6 +214,15 @@ func (o *AddOnOptions) RunControllerManager(ctx context.Context, controllerConte
 		controllerContext.EventRecorder,
 	)
 
+	clusterSetConnectivityController := clustersetconnectivity.NewController(
+		connectivityClient,
+		clusterInformers.Cluster().V1().ManagedClusters(),
+		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+		addOnInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+		configInformers.Submarineraddon().V1alpha1().SubmarinerConfigs(),
+		controllerContext.EventRecorder,
+	)
+
 	clusterInformers.Start(ctx.Done())
 	workInformers.Start(ctx.Done())
 	kubeInformers.Start(ctx.Done())
@@ -204,9 +230,14 @@ func (o *AddOnOptions) RunControllerManager(ctx context.Context, controllerConte
 	apiExtensionsInformers.Start(ctx.Done())
 	addOnInformers.Start(ctx.Done())
 
+	if err := brokerInfoCache.Start(ctx); err != nil {
+		return err
+	}
+
 	go submarinerBrokerCRDsController.Run(ctx, 1)
 	go submarinerBrokerController.Run(ctx, 1)
-	go submarinerAgentController.Run(ctx, 1)
+	go submarinerAgentController.Run(ctx, max(o.AgentControllerWorkers, 1))
+	go clusterSetConnectivityController.Run(ctx, 1)
 
 	mgr, err := addonmanager.New(controllerContext.KubeConfig)
 	if err != nil {
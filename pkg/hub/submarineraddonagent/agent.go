@@ -50,6 +50,8 @@ const (
 	addonDeploymentConfigResource = "addondeploymentconfigs"
 	addonDeploymentConfigGroup    = "addon.open-cluster-management.io"
 	selfManagedClusterLabelKey    = "local-cluster"
+	productClaim                  = "product.open-cluster-management.io"
+	platformClaim                 = "platform.open-cluster-management.io"
 )
 
 const (
@@ -137,6 +139,8 @@ func (a *addOnAgent) Manifests(cluster *clusterv1.ManagedCluster, addon *addonap
 		OpenShiftProfilePort  string
 		NodeSelector          map[string]string
 		Tolerations           []corev1.Toleration
+		AWSWebIdentityToken   bool
+		AzureIdentityToken    bool
 	}{
 		KubeConfigSecret:      fmt.Sprintf("%s-hub-kubeconfig", a.GetAgentAddonOptions().AddonName),
 		AddonInstallNamespace: installNamespace,
@@ -148,6 +152,8 @@ func (a *addOnAgent) Manifests(cluster *clusterv1.ManagedCluster, addon *addonap
 		OpenShiftProfilePort:  os.Getenv("OPENSHIFT_PROFILE_PORT"),
 		NodeSelector:          make(map[string]string),
 		Tolerations:           make([]corev1.Toleration, 0),
+		AWSWebIdentityToken:   runsOnAWS(cluster),
+		AzureIdentityToken:    runsOnAzure(cluster),
 	}
 
 	nodePlacements, err := a.getNodePlacements(addon)
@@ -181,6 +187,31 @@ func (a *addOnAgent) Manifests(cluster *clusterv1.ManagedCluster, addon *addonap
 	return objects, nil
 }
 
+// runsOnAWS returns whether the managed cluster runs on AWS, the agent is then given a service account token for AWS STS.
+func runsOnAWS(cluster *clusterv1.ManagedCluster) bool {
+	product := clusterClaim(cluster, productClaim)
+
+	return clusterClaim(cluster, platformClaim) == "AWS" || product == constants.ProductEKS || product == constants.ProductROSA
+}
+
+// runsOnAzure returns whether the managed cluster runs on Azure, the agent is then given a service account token for the Azure
+// workload identity.
+func runsOnAzure(cluster *clusterv1.ManagedCluster) bool {
+	product := clusterClaim(cluster, productClaim)
+
+	return clusterClaim(cluster, platformClaim) == "Azure" || product == constants.ProductAKS || product == constants.ProductARO
+}
+
+func clusterClaim(cluster *clusterv1.ManagedCluster, name string) string {
+	for _, claim := range cluster.Status.ClusterClaims {
+		if claim.Name == name {
+			return claim.Value
+		}
+	}
+
+	return ""
+}
+
 // GetAgentAddonOptions returns the options of submariner-addon agent.
 func (a *addOnAgent) GetAgentAddonOptions() agent.AgentAddonOptions {
 	return agent.AgentAddonOptions{
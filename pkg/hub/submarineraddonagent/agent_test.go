@@ -88,6 +88,54 @@ var _ = Describe("Manifests", func() {
 			verifyManifestObjs(objs, defaultManifestObjStrings(defaultNamespace))
 		})
 	})
+
+	Context("on a cluster on AWS", func() {
+		It("should mount a service account token for AWS STS", func() {
+			objs, err := t.addOnAgent.Manifests(newManagedCluster("platform.open-cluster-management.io", "AWS"),
+				&addonapiv1alpha1.ManagedClusterAddOn{})
+			Expect(err).To(Succeed())
+			Expect(volumeNames(objs)).To(ContainElement("aws-web-identity-token"))
+			Expect(volumeNames(objs)).ToNot(ContainElement("azure-identity-token"))
+		})
+	})
+
+	Context("on an EKS cluster", func() {
+		It("should mount a service account token for AWS STS", func() {
+			objs, err := t.addOnAgent.Manifests(newManagedCluster("product.open-cluster-management.io", "EKS"),
+				&addonapiv1alpha1.ManagedClusterAddOn{})
+			Expect(err).To(Succeed())
+			Expect(volumeNames(objs)).To(ContainElement("aws-web-identity-token"))
+		})
+	})
+
+	Context("on a cluster on Azure", func() {
+		It("should mount a service account token for the Azure workload identity", func() {
+			objs, err := t.addOnAgent.Manifests(newManagedCluster("platform.open-cluster-management.io", "Azure"),
+				&addonapiv1alpha1.ManagedClusterAddOn{})
+			Expect(err).To(Succeed())
+			Expect(volumeNames(objs)).To(ContainElement("azure-identity-token"))
+			Expect(volumeNames(objs)).ToNot(ContainElement("aws-web-identity-token"))
+		})
+	})
+
+	Context("on an AKS cluster", func() {
+		It("should mount a service account token for the Azure workload identity", func() {
+			objs, err := t.addOnAgent.Manifests(newManagedCluster("product.open-cluster-management.io", "AKS"),
+				&addonapiv1alpha1.ManagedClusterAddOn{})
+			Expect(err).To(Succeed())
+			Expect(volumeNames(objs)).To(ContainElement("azure-identity-token"))
+		})
+	})
+
+	Context("on a cluster on another cloud", func() {
+		It("should not mount any service account token", func() {
+			objs, err := t.addOnAgent.Manifests(newManagedCluster("platform.open-cluster-management.io", "GCP"),
+				&addonapiv1alpha1.ManagedClusterAddOn{})
+			Expect(err).To(Succeed())
+			Expect(volumeNames(objs)).ToNot(ContainElement("aws-web-identity-token"))
+			Expect(volumeNames(objs)).ToNot(ContainElement("azure-identity-token"))
+		})
+	})
 })
 
 var _ = Describe("GetAgentAddonOptions", func() {
@@ -304,6 +352,38 @@ func defaultManifestObjStrings(ns string) []string {
 	}
 }
 
+func newManagedCluster(claim, value string) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		Status: clusterv1.ManagedClusterStatus{
+			ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: claim, Value: value}},
+		},
+	}
+}
+
+// volumeNames returns the names of the volumes mounted in the containers of the agent deployment.
+func volumeNames(objs []runtime.Object) []string {
+	names := []string{}
+
+	for _, obj := range objs {
+		deployment, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			names = append(names, volume.Name)
+		}
+
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			for _, mount := range container.VolumeMounts {
+				Expect(names).To(ContainElement(mount.Name))
+			}
+		}
+	}
+
+	return names
+}
+
 type csrHolder struct {
 	SignerName   string
 	CN           string
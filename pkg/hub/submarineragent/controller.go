@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -23,6 +24,7 @@ import (
 	"github.com/stolostron/submariner-addon/pkg/constants"
 	brokerinfo "github.com/stolostron/submariner-addon/pkg/hub/submarinerbrokerinfo"
 	"github.com/stolostron/submariner-addon/pkg/manifestwork"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/federate"
 	"github.com/submariner-io/admiral/pkg/finalizer"
@@ -42,8 +44,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	addonclient "open-cluster-management.io/api/client/addon/clientset/versioned"
@@ -60,6 +64,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
+	clustersdkv1beta2 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
@@ -77,6 +82,7 @@ const (
 	BackupLabelValue              = "submariner"
 	addonDeploymentConfigResource = "addondeploymentconfigs"
 	addonDeploymentConfigGroup    = "addon.open-cluster-management.io"
+	submarinerAgentDegraded       = "SubmarinerAgentDegraded"
 )
 
 var clusterRBACFiles = []string{
@@ -97,6 +103,14 @@ var operatorSkipFiles = []string{
 	"manifests/operator/submariner-operator-subscription.yaml",
 }
 
+var operatorManifestsFiles = []string{
+	"manifests/crds/submariner.io_submariners.yaml",
+	"manifests/crds/submariner.io_servicediscoveries.yaml",
+	"manifests/crds/submariner.io_brokers.yaml",
+	"manifests/operator/submariner-operator-rbac.yaml",
+	"manifests/operator/submariner-operator-deployment.yaml",
+}
+
 //go:embed manifests
 var manifestFiles embed.FS
 
@@ -128,6 +142,7 @@ type submarinerAgentController struct {
 	manifestWorkClient     workclient.Interface
 	configClient           configclient.Interface
 	addOnClient            addonclient.Interface
+	brokerInfoCache        *brokerinfo.Cache
 	clusterLister          clusterlisterv1.ManagedClusterLister
 	clusterSetLister       clusterlisterv1beta2.ManagedClusterSetLister
 	manifestWorkLister     worklister.ManifestWorkLister
@@ -137,6 +152,7 @@ type submarinerAgentController struct {
 	deploymentConfigLister addonlisterv1alpha1.AddOnDeploymentConfigLister
 	eventRecorder          events.Recorder
 	resourceCache          resourceapply.ResourceCache
+	queue                  *clusterQueue
 }
 
 // NewSubmarinerAgentController returns a submarinerAgentController instance.
@@ -148,6 +164,7 @@ func NewSubmarinerAgentController(
 	manifestWorkClient workclient.Interface,
 	configClient configclient.Interface,
 	addOnClient addonclient.Interface,
+	brokerInfoCache *brokerinfo.Cache,
 	clusterInformer clusterinformerv1.ManagedClusterInformer,
 	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
 	manifestWorkInformer workinformer.ManifestWorkInformer,
@@ -165,6 +182,7 @@ func NewSubmarinerAgentController(
 		manifestWorkClient:     manifestWorkClient,
 		configClient:           configClient,
 		addOnClient:            addOnClient,
+		brokerInfoCache:        brokerInfoCache,
 		clusterLister:          clusterInformer.Lister(),
 		clusterSetLister:       clusterSetInformer.Lister(),
 		manifestWorkLister:     manifestWorkInformer.Lister(),
@@ -173,71 +191,93 @@ func NewSubmarinerAgentController(
 		addOnLister:            addOnInformer.Lister(),
 		deploymentConfigLister: deploymentConfigInformer.Lister(),
 		eventRecorder:          recorder.WithComponentSuffix("submariner-agent-controller"),
-		resourceCache:          resourceapply.NewResourceCache(),
+		resourceCache:          resource.NewSyncResourceCache(),
 	}
 
-	return factory.New().
-		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
-			accessor, _ := meta.Accessor(obj)
-			logger.V(log.DEBUG).Infof("Queuing ManagedCluster %q", accessor.GetName())
-
-			return accessor.GetName()
-		}, clusterInformer.Informer()).
-		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
-			// TODO: we may consider to use addon to deploy the submariner on the managed cluster instead of
-			// using manifestwork, one problem should be considered - how to get the IPSECPSK
-			accessor, _ := meta.Accessor(obj)
-			if accessor.GetName() != OperatorManifestWorkName && accessor.GetName() != SubmarinerCRManifestWorkName {
-				return ""
-			}
+	name := "SubmarinerAgentController"
+	syncCtx := factory.NewSyncContext(name, recorder)
+	c.queue = newClusterQueue(syncCtx.Queue())
 
-			logger.V(log.DEBUG).Infof("Queuing ManifestWork \"%s/%s\"", accessor.GetNamespace(), accessor.GetName())
+	addEventHandler(clusterInformer.Informer(), c.queue.eventHandler(func(obj metav1.Object) string {
+		logger.V(log.DEBUG).Infof("Queuing ManagedCluster %q", obj.GetName())
 
-			return accessor.GetNamespace()
-		}, manifestWorkInformer.Informer()).
-		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
-			// TODO: we may consider to use addon to set up the submariner env on the managed cluster instead of
-			// using manifestwork, one problem should be considered - how to get the cloud credentials
-			accessor, _ := meta.Accessor(obj)
-			if accessor.GetName() != constants.SubmarinerConfigName {
-				return ""
-			}
+		return obj.GetName()
+	}))
 
-			logger.V(log.DEBUG).Infof("Queuing SubmarinerConfig for managed cluster %q", accessor.GetNamespace())
+	addEventHandler(manifestWorkInformer.Informer(), c.queue.eventHandler(func(obj metav1.Object) string {
+		// TODO: we may consider to use addon to deploy the submariner on the managed cluster instead of
+		// using manifestwork, one problem should be considered - how to get the IPSECPSK
+		if obj.GetName() != OperatorManifestWorkName && obj.GetName() != SubmarinerCRManifestWorkName {
+			return ""
+		}
 
-			return accessor.GetNamespace()
-		}, configInformer.Informer()).
-		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
-			accessor, _ := meta.Accessor(obj)
-			if accessor.GetName() != constants.SubmarinerAddOnName {
-				return ""
-			}
+		logger.V(log.DEBUG).Infof("Queuing ManifestWork \"%s/%s\"", obj.GetNamespace(), obj.GetName())
 
-			logger.V(log.DEBUG).Infof("Queuing ManagedClusterAddOn %q for cluster %q", accessor.GetName(), accessor.GetNamespace())
+		return obj.GetNamespace()
+	}))
 
-			return accessor.GetNamespace()
-		}, addOnInformer.Informer()).
-		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
-			accessor, _ := meta.Accessor(obj)
-			if accessor.GetName() != constants.SubmarinerAddOnName {
-				return ""
-			}
+	addEventHandler(configInformer.Informer(), c.queue.eventHandler(func(obj metav1.Object) string {
+		// TODO: we may consider to use addon to set up the submariner env on the managed cluster instead of
+		// using manifestwork, one problem should be considered - how to get the cloud credentials
+		if obj.GetName() != constants.SubmarinerConfigName {
+			return ""
+		}
+
+		logger.V(log.DEBUG).Infof("Queuing SubmarinerConfig for managed cluster %q", obj.GetNamespace())
+
+		return obj.GetNamespace()
+	}))
+
+	addEventHandler(addOnInformer.Informer(), c.queue.eventHandler(func(obj metav1.Object) string {
+		if obj.GetName() != constants.SubmarinerAddOnName {
+			return ""
+		}
 
-			logger.V(log.DEBUG).Infof("Queuing ClusterManagementAddon %q", accessor.GetName())
+		logger.V(log.DEBUG).Infof("Queuing ManagedClusterAddOn %q for cluster %q", obj.GetName(), obj.GetNamespace())
 
-			return factory.DefaultQueueKey
-		}, clusterAddOnInformer.Informer()).
-		WithInformers(clusterSetInformer.Informer()).
-		WithSync(c.sync).
-		ToController("SubmarinerAgentController", recorder)
+		return obj.GetNamespace()
+	}))
+
+	addEventHandler(clusterAddOnInformer.Informer(), c.queue.eventHandler(func(obj metav1.Object) string {
+		if obj.GetName() != constants.SubmarinerAddOnName {
+			return ""
+		}
+
+		logger.V(log.DEBUG).Infof("Queuing ClusterManagementAddon %q", obj.GetName())
+
+		return factory.DefaultQueueKey
+	}))
+
+	// A change of a ManagedClusterSet only affects its members; they're queued as resyncs since the ManagedClusterSet itself
+	// doesn't change their deployment.
+	addEventHandler(clusterSetInformer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc: c.onManagedClusterSetChange,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.onManagedClusterSetChange(newObj)
+		},
+		DeleteFunc: c.onManagedClusterSetChange,
+	})
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithBareInformers(clusterInformer.Informer(), manifestWorkInformer.Informer(), configInformer.Informer(),
+			addOnInformer.Informer(), clusterAddOnInformer.Informer(), clusterSetInformer.Informer()).
+		WithSync(metrics.InstrumentSync(name, c.sync)).
+		ToController(name, recorder)
+}
+
+func addEventHandler(informer cache.SharedIndexInformer, handler cache.ResourceEventHandler) {
+	if _, err := informer.AddEventHandler(handler); err != nil {
+		utilruntime.HandleError(errors.Wrap(err, "error adding an informer event handler"))
+	}
 }
 
 func (c *submarinerAgentController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
 	key := syncCtx.QueueKey()
 
-	// if the sync is triggered by change of ManagedClusterSet or ClusterManagementAddon, reconcile all managed clusters
+	// if the sync is triggered by change of the ClusterManagementAddon, reconcile all managed clusters
 	if key == factory.DefaultQueueKey {
-		return c.onManagedClusterSetChange(syncCtx)
+		return c.onClusterManagementAddOnChange()
 	}
 
 	clusterName := key
@@ -250,19 +290,44 @@ func (c *submarinerAgentController) sync(ctx context.Context, syncCtx factory.Sy
 	return c.syncManagedCluster(ctx, clusterName, config, syncCtx)
 }
 
-func (c *submarinerAgentController) onManagedClusterSetChange(syncCtx factory.SyncContext) error {
+func (c *submarinerAgentController) onClusterManagementAddOnChange() error {
 	managedClusters, err := c.clusterLister.List(labels.Everything())
 	if err != nil {
 		return err
 	}
+
 	for _, managedCluster := range managedClusters {
 		// enqueue the managed cluster to reconcile
-		syncCtx.Queue().Add(managedCluster.Name)
+		c.queue.addResync(managedCluster.Name)
 	}
 
 	return nil
 }
 
+// onManagedClusterSetChange queues the members of the changed ManagedClusterSet, as selected by its cluster selector.
+func (c *submarinerAgentController) onManagedClusterSetChange(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	clusterSet, ok := obj.(*clusterv1beta2.ManagedClusterSet)
+	if !ok {
+		return
+	}
+
+	managedClusters, err := clustersdkv1beta2.GetClustersFromClusterSet(clusterSet, c.clusterLister)
+	if err != nil {
+		utilruntime.HandleError(errors.Wrapf(err, "error listing the managed clusters of ManagedClusterSet %q", clusterSet.Name))
+		return
+	}
+
+	logger.V(log.DEBUG).Infof("Queuing the %d managed clusters of ManagedClusterSet %q", len(managedClusters), clusterSet.Name)
+
+	for _, managedCluster := range managedClusters {
+		c.queue.addResync(managedCluster.Name)
+	}
+}
+
 // syncManagedCluster syncs one managed cluster.
 func (c *submarinerAgentController) syncManagedCluster(
 	ctx context.Context,
@@ -371,6 +436,7 @@ func (c *submarinerAgentController) cleanUpSubmarinerAgent(ctx context.Context,
 
 		logger.Infof("ManifestWork %q for cluster %q did not complete deletion after %v - finishing clean up",
 			SubmarinerCRManifestWorkName, managedClusterName, time.Millisecond*time.Duration(elapsed))
+		metrics.RecordManifestWorkDeletionTimeout()
 	}
 
 	if err := c.deleteClusterBrokerResources(ctx, managedClusterName, clusterSetName); err != nil {
@@ -434,13 +500,13 @@ func (c *submarinerAgentController) deploySubmarinerAgent(
 	// create submariner broker info with submariner config
 	brokerInfo, err := brokerinfo.Get(
 		ctx,
-		c.kubeClient,
-		c.dynamicClient,
+		c.brokerInfoCache,
 		c.controllerClient,
 		managedCluster.Name,
 		brokerNamespace,
 		submarinerConfig,
 		managedClusterAddOn.Spec.InstallNamespace,
+		getClusterProduct(managedCluster),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create submariner brokerInfo of cluster %v : %w", managedCluster.Name, err)
@@ -461,7 +527,7 @@ func (c *submarinerAgentController) deploySubmarinerAgent(
 	skipOperatorGroup := false
 
 	if submarinerConfig != nil {
-		err := c.updateSubmarinerConfigStatus(ctx, submarinerConfig, managedCluster)
+		err := c.updateSubmarinerConfigStatus(ctx, submarinerConfig, managedCluster, managedClusterAddOn)
 		if err != nil {
 			return err
 		}
@@ -470,12 +536,17 @@ func (c *submarinerAgentController) deploySubmarinerAgent(
 	}
 
 	// Apply submariner operator manifest work
-	operatorManifestWork, err := newOperatorManifestWork(managedCluster, brokerInfo, skipOperatorGroup)
+	operatorManifestWork, err := newOperatorManifestWork(managedCluster, brokerInfo, getInstallMode(managedCluster, submarinerConfig),
+		skipOperatorGroup)
 	if err != nil {
 		return err
 	}
 
-	if err := manifestwork.Apply(ctx, c.manifestWorkClient, operatorManifestWork, c.eventRecorder); err != nil {
+	appliedOperatorWork, err := manifestwork.Apply(ctx, c.manifestWorkClient, c.manifestWorkLister, operatorManifestWork,
+		c.eventRecorder)
+	metrics.RecordManifestWorkApply(OperatorManifestWorkName, err)
+
+	if err != nil {
 		return err
 	}
 
@@ -485,17 +556,60 @@ func (c *submarinerAgentController) deploySubmarinerAgent(
 		return err
 	}
 
-	return manifestwork.Apply(ctx, c.manifestWorkClient, submarinerManifestWork, c.eventRecorder)
+	appliedSubmarinerWork, err := manifestwork.Apply(ctx, c.manifestWorkClient, c.manifestWorkLister, submarinerManifestWork,
+		c.eventRecorder)
+	metrics.RecordManifestWorkApply(SubmarinerCRManifestWorkName, err)
+
+	if err != nil {
+		return err
+	}
+
+	if submarinerConfig != nil {
+		return c.updateAppliedManifestWorks(ctx, submarinerConfig, appliedOperatorWork, appliedSubmarinerWork)
+	}
+
+	return nil
+}
+
+// updateAppliedManifestWorks records the revisions of the given applied ManifestWorks in the status of the SubmarinerConfig.
+func (c *submarinerAgentController) updateAppliedManifestWorks(ctx context.Context, submarinerConfig *configv1alpha1.SubmarinerConfig,
+	works ...*workv1.ManifestWork,
+) error {
+	appliedWorks := make([]configv1alpha1.AppliedManifestWork, len(works))
+
+	for i, work := range works {
+		appliedWorks[i] = configv1alpha1.AppliedManifestWork{
+			Name:        work.Name,
+			ContentHash: work.Annotations[manifestwork.ContentHashAnnotation],
+			Generation:  work.Generation,
+		}
+	}
+
+	if slices.EqualFunc(submarinerConfig.Status.AppliedManifestWorks, appliedWorks,
+		func(recorded, applied configv1alpha1.AppliedManifestWork) bool {
+			return recorded.Name == applied.Name && recorded.ContentHash == applied.ContentHash &&
+				recorded.Generation == applied.Generation
+		}) {
+		return nil
+	}
+
+	_, _, err := submarinerconfig.UpdateStatus(ctx,
+		c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(submarinerConfig.Namespace), submarinerConfig.Name,
+		submarinerconfig.UpdateAppliedManifestWorksFn(appliedWorks))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", err)
+
+	return err
 }
 
 func (c *submarinerAgentController) updateSubmarinerConfigStatus(ctx context.Context, submarinerConfig *configv1alpha1.SubmarinerConfig,
-	managedCluster *clusterv1.ManagedCluster,
+	managedCluster *clusterv1.ManagedCluster, managedClusterAddOn *addonv1alpha1.ManagedClusterAddOn,
 ) error {
 	condition := &metav1.Condition{
-		Type:    configv1alpha1.SubmarinerConfigConditionApplied,
-		Status:  metav1.ConditionTrue,
-		Reason:  "SubmarinerConfigApplied",
-		Message: "SubmarinerConfig was applied",
+		Type:               configv1alpha1.SubmarinerConfigConditionApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SubmarinerConfigApplied",
+		Message:            "SubmarinerConfig was applied",
+		ObservedGeneration: submarinerConfig.Generation,
 	}
 
 	managedClusterInfo := getManagedClusterInfo(managedCluster)
@@ -507,7 +621,10 @@ func (c *submarinerAgentController) updateSubmarinerConfigStatus(ctx context.Con
 
 	_, updated, err := submarinerconfig.UpdateStatus(ctx,
 		c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(submarinerConfig.Namespace), submarinerConfig.Name,
-		submarinerconfig.UpdateStatusFn(condition, managedClusterInfo))
+		submarinerconfig.UpdateStatusFn(condition, managedClusterInfo),
+		submarinerconfig.UpdateConditionFn(agentDeployedCondition(managedClusterAddOn, submarinerConfig.Generation)),
+		submarinerconfig.UpdateObservedGenerationFn(submarinerConfig.Generation))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", err)
 
 	if updated {
 		c.eventRecorder.Eventf("SubmarinerConfigApplied", "SubmarinerConfig %q was applied for managed cluster %q",
@@ -517,6 +634,32 @@ func (c *submarinerAgentController) updateSubmarinerConfigStatus(ctx context.Con
 	return err
 }
 
+// agentDeployedCondition returns the SubmarinerAgentDeployed condition of the SubmarinerConfig, reflecting the
+// SubmarinerAgentDegraded condition reported by the managed cluster on the given ManagedClusterAddOn.
+func agentDeployedCondition(managedClusterAddOn *addonv1alpha1.ManagedClusterAddOn, generation int64) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:               configv1alpha1.SubmarinerConfigConditionAgentDeployed,
+		Status:             metav1.ConditionFalse,
+		Reason:             configv1alpha1.SubmarinerAgentDeployedReasonUnavailable,
+		Message:            "The Submariner agent didn't report its status yet",
+		ObservedGeneration: generation,
+	}
+
+	degraded := meta.FindStatusCondition(managedClusterAddOn.Status.Conditions, submarinerAgentDegraded)
+	if degraded == nil {
+		return condition
+	}
+
+	condition.Message = degraded.Message
+
+	if degraded.Status == metav1.ConditionFalse {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SubmarinerAgentDeployed"
+	}
+
+	return condition
+}
+
 func (c *submarinerAgentController) updateManagedClusterAddOnStatus(ctx context.Context,
 	managedClusterAddon *addonv1alpha1.ManagedClusterAddOn, brokerNamespace string, missing bool,
 ) error {
@@ -541,6 +684,8 @@ func (c *submarinerAgentController) updateManagedClusterAddOnStatus(ctx context.
 
 	_, updated, err := addon.UpdateStatus(ctx, c.addOnClient, managedClusterAddon.Namespace,
 		addon.UpdateConditionFn(&condition))
+	metrics.RecordStatusUpdateConflict("ManagedClusterAddOn", err)
+
 	if err != nil {
 		return err
 	}
@@ -618,12 +763,23 @@ func newSubmarinerManifestWork(managedCluster *clusterv1.ManagedCluster, config
 	return newManifestWork(SubmarinerCRManifestWorkName, managedCluster.Name, config, submarinerCRFile)
 }
 
-func newOperatorManifestWork(managedCluster *clusterv1.ManagedCluster, config interface{}, skipOperatorGroup bool,
+func newOperatorManifestWork(managedCluster *clusterv1.ManagedCluster, config interface{}, installMode string, skipOperatorGroup bool,
 ) (*workv1.ManifestWork, error) {
+	if installMode == configv1alpha1.InstallModeManifests {
+		// The CRDs and cluster roles are applied with the default execution permissions of the work agent, no extra RBAC is
+		// granted to it.
+		files := []string{}
+		if constants.IsOpenShiftProduct(getClusterProduct(managedCluster)) {
+			files = append(files, sccFiles...)
+		}
+
+		files = append(files, operatorManifestsFiles...)
+
+		return newManifestWork(OperatorManifestWorkName, managedCluster.Name, config, files...)
+	}
+
 	files := []string{agentRBACFile}
-	clusterProduct := getClusterProduct(managedCluster)
-	if clusterProduct == constants.ProductOCP || clusterProduct == constants.ProductROSA ||
-		clusterProduct == constants.ProductARO || clusterProduct == constants.ProductROKS {
+	if constants.IsOpenShiftProduct(getClusterProduct(managedCluster)) {
 		files = append(files, sccFiles...)
 	}
 
@@ -646,13 +802,21 @@ func newManifestWork(name, namespace string, config interface{}, files ...string
 		}
 
 		yamlData := assets.MustCreateAssetFromTemplate(file, template, config).Data
-		jsonData, err := yaml.YAMLToJSON(yamlData)
-		if err != nil {
-			return nil, err
-		}
 
-		manifest := workv1.Manifest{RawExtension: runtime.RawExtension{Raw: jsonData}}
-		manifests = append(manifests, manifest)
+		// A manifest file may contain several resources separated by YAML document markers.
+		for _, doc := range strings.Split(string(yamlData), "\n---\n") {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+
+			jsonData, err := yaml.YAMLToJSON([]byte(doc))
+			if err != nil {
+				return nil, err
+			}
+
+			manifest := workv1.Manifest{RawExtension: runtime.RawExtension{Raw: jsonData}}
+			manifests = append(manifests, manifest)
+		}
 	}
 
 	return &workv1.ManifestWork{
@@ -679,6 +843,22 @@ func getClusterProduct(managedCluster *clusterv1.ManagedCluster) string {
 	return ""
 }
 
+// getInstallMode returns the install mode explicitly set in the SubmarinerConfig or, if not set, the mode derived from the
+// managed cluster product. Managed Kubernetes offerings without Operator Lifecycle Manager use the manifests mode; all other
+// products, including unknown ones, keep using OLM.
+func getInstallMode(managedCluster *clusterv1.ManagedCluster, submarinerConfig *configv1alpha1.SubmarinerConfig) string {
+	if submarinerConfig != nil && submarinerConfig.Spec.InstallMode != "" {
+		return submarinerConfig.Spec.InstallMode
+	}
+
+	switch getClusterProduct(managedCluster) {
+	case constants.ProductEKS, constants.ProductGKE, constants.ProductAKS, constants.ProductIKS:
+		return configv1alpha1.InstallModeManifests
+	default:
+		return configv1alpha1.InstallModeOLM
+	}
+}
+
 func getManagedClusterInfo(managedCluster *clusterv1.ManagedCluster) *configv1alpha1.ManagedClusterInfo {
 	clusterInfo := &configv1alpha1.ManagedClusterInfo{
 		ClusterName: managedCluster.Name,
@@ -865,11 +1045,13 @@ func (c *submarinerAgentController) deleteGlobalBrokerResourcesIfNecessary(ctx c
 		Name:      BrokerObjectName,
 		Namespace: brokerNamespace,
 	}})
-	if apierrors.IsNotFound(err) {
-		err = nil
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
 	}
 
-	return err
+	c.brokerInfoCache.Forget(brokerNamespace)
+
+	return nil
 }
 
 func (c *submarinerAgentController) getBrokerObject(ctx context.Context, brokerNamespace string) (*submarinerv1a1.Broker, error) {
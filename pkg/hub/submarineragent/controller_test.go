@@ -17,6 +17,8 @@ import (
 	cloudFake "github.com/stolostron/submariner-addon/pkg/cloud/fake"
 	"github.com/stolostron/submariner-addon/pkg/constants"
 	"github.com/stolostron/submariner-addon/pkg/hub/submarineragent"
+	brokerinfo "github.com/stolostron/submariner-addon/pkg/hub/submarinerbrokerinfo"
+	"github.com/stolostron/submariner-addon/pkg/manifestwork"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	fakereactor "github.com/submariner-io/admiral/pkg/fake"
 	"github.com/submariner-io/admiral/pkg/federate"
@@ -31,6 +33,7 @@ import (
 	discovery "k8s.io/api/discovery/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,7 +48,6 @@ import (
 	"k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
-	addonclient "open-cluster-management.io/api/client/addon/clientset/versioned"
 	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
 	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
@@ -110,7 +112,9 @@ var _ = Describe("Controller", func() {
 
 			Context("and the SubmarinerConfig is present", func() {
 				BeforeEach(func() {
-					t.createSubmarinerConfig(newSubmarinerConfig())
+					config := newSubmarinerConfig()
+					config.Generation = 2
+					t.createSubmarinerConfig(config)
 
 					t.managedCluster.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{
 						{
@@ -161,6 +165,65 @@ var _ = Describe("Controller", func() {
 						Region:        "east",
 						VendorVersion: "1.0",
 					}))
+
+					condition := meta.FindStatusCondition(config.Status.Conditions, configv1alpha1.SubmarinerConfigConditionApplied)
+					Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+					Expect(config.Status.Phase).To(Equal(configv1alpha1.SubmarinerConfigPhasePreparing))
+				})
+
+				It("should record the applied ManifestWork revisions in the SubmarinerConfig status", func() {
+					t.awaitManifestWorks()
+
+					Eventually(func() []string {
+						config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+							constants.SubmarinerConfigName, metav1.GetOptions{})
+						Expect(err).To(Succeed())
+
+						names := []string{}
+						for _, applied := range config.Status.AppliedManifestWorks {
+							work, err := t.manifestWorkClient.WorkV1().ManifestWorks(clusterName).Get(context.TODO(), applied.Name,
+								metav1.GetOptions{})
+							Expect(err).To(Succeed())
+							Expect(applied.ContentHash).To(Equal(work.Annotations[manifestwork.ContentHashAnnotation]))
+							Expect(applied.Generation).To(Equal(work.Generation))
+							Expect(applied.LastAppliedTime.IsZero()).To(BeFalse())
+
+							names = append(names, applied.Name)
+						}
+
+						return names
+					}).Should(Equal([]string{submarineragent.OperatorManifestWorkName, submarineragent.SubmarinerCRManifestWorkName}))
+				})
+
+				It("should report that the Submariner agent isn't available yet in the SubmarinerConfig status", func() {
+					t.awaitManifestWorks()
+
+					t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+						Type:   configv1alpha1.SubmarinerConfigConditionAgentDeployed,
+						Status: metav1.ConditionFalse,
+						Reason: configv1alpha1.SubmarinerAgentDeployedReasonUnavailable,
+					})
+				})
+
+				Context("and the Submariner agent is deployed", func() {
+					BeforeEach(func() {
+						t.addOn.Status.Conditions = []metav1.Condition{{
+							Type:    "SubmarinerAgentDegraded",
+							Status:  metav1.ConditionFalse,
+							Reason:  "SubmarinerAgentDeployed",
+							Message: "Submariner is deployed on managed cluster.",
+						}}
+					})
+
+					It("should report it in the SubmarinerConfig status", func() {
+						t.awaitManifestWorks()
+
+						t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+							Type:   configv1alpha1.SubmarinerConfigConditionAgentDeployed,
+							Status: metav1.ConditionTrue,
+							Reason: "SubmarinerAgentDeployed",
+						})
+					})
 				})
 			})
 
@@ -216,6 +279,45 @@ var _ = Describe("Controller", func() {
 				It("should deploy the operator ManifestWork with the Openshift security resources", func() {
 					t.assertSCCManifestObjs(t.awaitOperatorManifestWork())
 				})
+
+				Context("and the SubmarinerConfig specifies the Manifests install mode", func() {
+					BeforeEach(func() {
+						config := newSubmarinerConfig()
+						config.Spec.InstallMode = configv1alpha1.InstallModeManifests
+						t.createSubmarinerConfig(config)
+					})
+
+					It("should deploy the operator ManifestWork without OLM resources", func() {
+						t.assertSCCManifestObjs(t.awaitManifestsOperatorManifestWork())
+					})
+				})
+			})
+
+			Context("and the ManagedCluster product is EKS", func() {
+				BeforeEach(func() {
+					t.managedCluster.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{
+						{
+							Name:  "product.open-cluster-management.io",
+							Value: constants.ProductEKS,
+						},
+					}
+				})
+
+				It("should deploy the operator ManifestWork without OLM resources", func() {
+					assertNoManifestObj(t.awaitManifestsOperatorManifestWork(), "ClusterRole", "scc")
+				})
+
+				Context("and the SubmarinerConfig specifies the OLM install mode", func() {
+					BeforeEach(func() {
+						config := newSubmarinerConfig()
+						config.Spec.InstallMode = configv1alpha1.InstallModeOLM
+						t.createSubmarinerConfig(config)
+					})
+
+					It("should deploy the operator ManifestWork with the OLM resources", func() {
+						t.awaitOperatorManifestWork()
+					})
+				})
 			})
 		})
 
@@ -307,6 +409,81 @@ var _ = Describe("Controller", func() {
 		})
 	})
 
+	When("a ManagedClusterSet is updated", func() {
+		var clusterSet *clusterv1beta2.ManagedClusterSet
+
+		JustBeforeEach(func() {
+			t.initManifestWorks()
+
+			_, err := t.clusterClient.ClusterV1beta2().ManagedClusterSets().Create(context.TODO(), &clusterv1beta2.ManagedClusterSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "south-america",
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+
+			// Let the reconciliations triggered by the deployment settle
+			Eventually(func() []testing.Action {
+				defer t.addOnClient.Fake.ClearActions()
+				return t.addOnClient.Fake.Actions()
+			}).WithPolling(300 * time.Millisecond).Should(BeEmpty())
+
+			existing, err := t.clusterClient.ClusterV1beta2().ManagedClusterSets().Get(context.TODO(), clusterSet.Name,
+				metav1.GetOptions{})
+			Expect(err).To(Succeed())
+
+			existing.Labels = map[string]string{"updated": "true"}
+			existing.Spec = clusterSet.Spec
+
+			_, err = t.clusterClient.ClusterV1beta2().ManagedClusterSets().Update(context.TODO(), existing, metav1.UpdateOptions{})
+			Expect(err).To(Succeed())
+		})
+
+		Context("and it contains the ManagedCluster", func() {
+			BeforeEach(func() {
+				clusterSet = &clusterv1beta2.ManagedClusterSet{ObjectMeta: metav1.ObjectMeta{Name: clusterSetName}}
+			})
+
+			It("should reconcile the ManagedCluster", func() {
+				Eventually(func() []testing.Action {
+					return t.addOnClient.Fake.Actions()
+				}).ShouldNot(BeEmpty())
+			})
+		})
+
+		Context("and it selects all the ManagedClusters with a label selector", func() {
+			BeforeEach(func() {
+				clusterSet = &clusterv1beta2.ManagedClusterSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "south-america"},
+					Spec: clusterv1beta2.ManagedClusterSetSpec{
+						ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+							SelectorType:  clusterv1beta2.LabelSelector,
+							LabelSelector: &metav1.LabelSelector{},
+						},
+					},
+				}
+			})
+
+			It("should reconcile the ManagedCluster", func() {
+				Eventually(func() []testing.Action {
+					return t.addOnClient.Fake.Actions()
+				}).ShouldNot(BeEmpty())
+			})
+		})
+
+		Context("and it doesn't contain the ManagedCluster", func() {
+			BeforeEach(func() {
+				clusterSet = &clusterv1beta2.ManagedClusterSet{ObjectMeta: metav1.ObjectMeta{Name: "south-america"}}
+			})
+
+			It("should not reconcile the ManagedCluster", func() {
+				Consistently(func() []testing.Action {
+					return t.addOnClient.Fake.Actions()
+				}, 300*time.Millisecond).Should(BeEmpty())
+			})
+		})
+	})
+
 	When("a ManagedClusterAddon is being deleted", func() {
 		const otherClusterName = "west"
 
@@ -540,7 +717,7 @@ type testDriver struct {
 	clusterClient      clusterclient.Interface
 	manifestWorkClient *fakeworkclient.Clientset
 	configClient       configclient.Interface
-	addOnClient        addonclient.Interface
+	addOnClient        *addonfake.Clientset
 	stop               context.CancelFunc
 	mockCtrl           *gomock.Controller
 	cloudProvider      *cloudFake.MockProvider
@@ -668,9 +845,10 @@ func newTestDriver() *testDriver {
 		workInformerFactory := workinformers.NewSharedInformerFactory(t.manifestWorkClient, 0)
 		configInformerFactory := configinformers.NewSharedInformerFactory(t.configClient, 0)
 		addOnInformerFactory := addoninformers.NewSharedInformerFactory(t.addOnClient, 0)
+		brokerInfoCache := brokerinfo.NewCache(t.kubeClient, t.dynamicClient)
 
 		controller := submarineragent.NewSubmarinerAgentController(t.kubeClient, t.dynamicClient, t.controllerClient, t.clusterClient,
-			t.manifestWorkClient, t.configClient, t.addOnClient,
+			t.manifestWorkClient, t.configClient, t.addOnClient, brokerInfoCache,
 			clusterInformerFactory.Cluster().V1().ManagedClusters(),
 			clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
 			workInformerFactory.Work().V1().ManifestWorks(),
@@ -688,6 +866,7 @@ func newTestDriver() *testDriver {
 		workInformerFactory.Start(ctx.Done())
 		configInformerFactory.Start(ctx.Done())
 		addOnInformerFactory.Start(ctx.Done())
+		Expect(brokerInfoCache.Start(ctx)).To(Succeed())
 
 		cache.WaitForCacheSync(ctx.Done(),
 			clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().HasSynced,
@@ -750,6 +929,18 @@ func (t *testDriver) testAgentCleanup(expMCADeleted bool) {
 	})
 }
 
+func (t *testDriver) awaitSubmarinerConfigStatusCondition(expCond *metav1.Condition) {
+	test.AwaitStatusCondition(expCond, func() ([]metav1.Condition, error) {
+		config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+			constants.SubmarinerConfigName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		return config.Status.Conditions, nil
+	})
+}
+
 func (t *testDriver) awaitManifestWorks() {
 	t.awaitOperatorManifestWork()
 	t.awaitSubmarinerManifestWork()
@@ -788,6 +979,33 @@ func (t *testDriver) assertOperatorManifestWork(work *workv1.ManifestWork) []*un
 	return manifestObjs
 }
 
+func (t *testDriver) awaitManifestsOperatorManifestWork() []*unstructured.Unstructured {
+	work := test.AwaitResource[*workv1.ManifestWork](resource.ForManifestWork(
+		t.manifestWorkClient.WorkV1().ManifestWorks(clusterName)), submarineragent.OperatorManifestWorkName)
+
+	manifestObjs := unmarshallManifestObjs(work)
+
+	assertNoManifestObj(manifestObjs, "Subscription", "")
+	assertNoManifestObj(manifestObjs, "OperatorGroup", "")
+	assertNoManifestObj(manifestObjs, "ClusterRole", "operatorgroups")
+
+	assertManifestObj(manifestObjs, "CustomResourceDefinition", "submariners.submariner.io")
+	assertManifestObj(manifestObjs, "CustomResourceDefinition", "servicediscoveries.submariner.io")
+	assertManifestObj(manifestObjs, "CustomResourceDefinition", "brokers.submariner.io")
+	assertManifestObj(manifestObjs, "ServiceAccount", "submariner-operator")
+	assertManifestObj(manifestObjs, "ClusterRoleBinding", "submariner-operator")
+	assertManifestObj(manifestObjs, "ServiceAccount", "submariner-gateway")
+
+	assertNoManifestObj(manifestObjs, "ClusterRole", "submariner-addon-manifests")
+	assertNoManifestObj(manifestObjs, "ClusterRoleBinding", "submariner-addon-manifests")
+
+	deployment := assertManifestObj(manifestObjs, "Deployment", "submariner-operator")
+	assertNestedString(deployment, installNamespace, "metadata", "namespace")
+	assertNestedString(deployment, configv1alpha1.InstallModeManifests, "metadata", "labels", constants.InstallModeLabel)
+
+	return manifestObjs
+}
+
 func (t *testDriver) awaitSubmarinerManifestWork() {
 	t.assertSubmarinerManifestWork(test.AwaitResource[*workv1.ManifestWork](resource.ForManifestWork(
 		t.manifestWorkClient.WorkV1().ManifestWorks(clusterName)), submarineragent.SubmarinerCRManifestWorkName))
@@ -0,0 +1,227 @@
+package submarineragent_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	fakeconfigclient "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/clientset/versioned/fake"
+	configinformers "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/hub/submarineragent"
+	"github.com/stolostron/submariner-addon/pkg/hub/submarinerbrokerinfo"
+	fakereactor "github.com/submariner-io/admiral/pkg/fake"
+	submarinerv1alpha1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
+	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	fakeclusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const fleetSize = 200
+
+// BenchmarkFleetDeployment measures the throughput of the agent controller deploying Submariner on a fleet of managed clusters
+// joining a ManagedClusterSet, by number of workers. Run it with:
+//
+//	go test ./pkg/hub/submarineragent -run '^$' -bench BenchmarkFleetDeployment
+func BenchmarkFleetDeployment(b *testing.B) {
+	b.Setenv("BROKER_API_SERVER", "127.0.0.1")
+	utilruntime.Must(submarinerv1alpha1.AddToScheme(scheme.Scheme))
+
+	for _, workers := range []int{1, 5, 10} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+
+				fleet := newFleet(b, fleetSize)
+				stop := fleet.run(workers)
+
+				b.StartTimer()
+
+				fleet.join()
+				fleet.awaitManifestWorks()
+
+				b.StopTimer()
+
+				stop()
+			}
+
+			b.ReportMetric(float64(fleetSize*b.N)/b.Elapsed().Seconds(), "clusters/s")
+		})
+	}
+}
+
+type fleet struct {
+	b                *testing.B
+	size             int
+	kubeClient       *kubefake.Clientset
+	clusterClient    *fakeclusterclient.Clientset
+	addOnClient      *addonfake.Clientset
+	workClient       *fakeworkclient.Clientset
+	configClient     *fakeconfigclient.Clientset
+	dynamicClient    *dynamicfake.FakeDynamicClient
+	controllerClient client.Client
+}
+
+func newFleet(b *testing.B, size int) *fleet {
+	objs := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "submariner-ipsec-psk",
+				Namespace: brokerNamespace,
+			},
+			Data: map[string][]byte{
+				"psk": []byte(ipsecPSK),
+			},
+		},
+	}
+
+	for i := 0; i < size; i++ {
+		objs = append(objs, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fleetClusterName(i) + "-token",
+				Namespace:   brokerNamespace,
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: fleetClusterName(i)},
+			},
+			Data: map[string][]byte{
+				"ca.crt": []byte(brokerCA),
+				"token":  []byte(brokerToken),
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		})
+	}
+
+	globalnetConfigMap, err := globalnet.NewGlobalnetConfigMap(false, "", 0, brokerNamespace)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	f := &fleet{
+		b:                b,
+		size:             size,
+		kubeClient:       kubefake.NewSimpleClientset(objs...),
+		clusterClient:    fakeclusterclient.NewSimpleClientset(),
+		addOnClient:      addonfake.NewSimpleClientset(),
+		workClient:       fakeworkclient.NewSimpleClientset(),
+		configClient:     fakeconfigclient.NewSimpleClientset(),
+		dynamicClient:    dynamicfake.NewSimpleDynamicClient(scheme.Scheme),
+		controllerClient: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(globalnetConfigMap).Build(),
+	}
+
+	fakereactor.AddBasicReactors(&f.addOnClient.Fake)
+	fakereactor.AddBasicReactors(&f.workClient.Fake)
+	fakereactor.AddBasicReactors(&f.dynamicClient.Fake)
+
+	return f
+}
+
+func (f *fleet) run(workers int) context.CancelFunc {
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(f.clusterClient, 0)
+	workInformerFactory := workinformers.NewSharedInformerFactory(f.workClient, 0)
+	configInformerFactory := configinformers.NewSharedInformerFactory(f.configClient, 0)
+	addOnInformerFactory := addoninformers.NewSharedInformerFactory(f.addOnClient, 0)
+	brokerInfoCache := submarinerbrokerinfo.NewCache(f.kubeClient, f.dynamicClient)
+
+	controller := submarineragent.NewSubmarinerAgentController(f.kubeClient, f.dynamicClient, f.controllerClient, f.clusterClient,
+		f.workClient, f.configClient, f.addOnClient, brokerInfoCache,
+		clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+		workInformerFactory.Work().V1().ManifestWorks(),
+		configInformerFactory.Submarineraddon().V1alpha1().SubmarinerConfigs(),
+		addOnInformerFactory.Addon().V1alpha1().ClusterManagementAddOns(),
+		addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
+		addOnInformerFactory.Addon().V1alpha1().AddOnDeploymentConfigs(),
+		events.NewInMemoryRecorder("test"))
+
+	ctx, stop := context.WithCancel(context.Background())
+
+	clusterInformerFactory.Start(ctx.Done())
+	workInformerFactory.Start(ctx.Done())
+	configInformerFactory.Start(ctx.Done())
+	addOnInformerFactory.Start(ctx.Done())
+
+	if err := brokerInfoCache.Start(ctx); err != nil {
+		f.b.Fatal(err)
+	}
+
+	go controller.Run(ctx, workers)
+
+	return stop
+}
+
+// join creates the ManagedClusterSet, and the ManagedClusters with the submariner ManagedClusterAddOn.
+func (f *fleet) join() {
+	ctx := context.Background()
+
+	_, err := f.clusterClient.ClusterV1beta2().ManagedClusterSets().Create(ctx, &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterSetName,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		f.b.Fatal(err)
+	}
+
+	for i := 0; i < f.size; i++ {
+		_, err := f.clusterClient.ClusterV1().ManagedClusters().Create(ctx, &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fleetClusterName(i),
+				Labels: map[string]string{clusterv1beta2.ClusterSetLabel: clusterSetName},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			f.b.Fatal(err)
+		}
+
+		_, err = f.addOnClient.AddonV1alpha1().ManagedClusterAddOns(fleetClusterName(i)).Create(ctx, &addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.SubmarinerAddOnName,
+				Namespace: fleetClusterName(i),
+			},
+			Spec: addonv1alpha1.ManagedClusterAddOnSpec{
+				InstallNamespace: installNamespace,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			f.b.Fatal(err)
+		}
+	}
+}
+
+// awaitManifestWorks waits for the ManifestWorks of all the managed clusters.
+func (f *fleet) awaitManifestWorks() {
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Minute, true,
+		func(ctx context.Context) (bool, error) {
+			works, err := f.workClient.WorkV1().ManifestWorks(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			return len(works.Items) == 2*f.size, nil
+		})
+	if err != nil {
+		f.b.Fatalf("The ManifestWorks of the fleet weren't deployed: %v", err)
+	}
+}
+
+func fleetClusterName(i int) string {
+	return fmt.Sprintf("cluster-%04d", i)
+}
@@ -0,0 +1,105 @@
+package submarineragent
+
+import (
+	"github.com/submariner-io/admiral/pkg/log"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var (
+	// ResyncRateLimit and ResyncBurst limit the rate at which the managed clusters are queued on ManagedClusterSet and
+	// ClusterManagementAddOn changes, on the initial listing and on the periodic resyncs of the informers.
+	ResyncRateLimit = rate.Limit(10)
+	ResyncBurst     = 100
+)
+
+// queueKeyFunc returns the queue key of the given object, or an empty key to ignore it.
+type queueKeyFunc func(obj metav1.Object) string
+
+// clusterQueue adds the keys to reconcile to the controller queue by priority. The keys of the changed resources are added
+// right away, whereas the resyncs are rate limited so that re-queueing a large fleet doesn't delay the changes, such as new
+// clusters to deploy.
+type clusterQueue struct {
+	queue         workqueue.RateLimitingInterface
+	resyncLimiter workqueue.TypedRateLimiter[string]
+}
+
+func newClusterQueue(queue workqueue.RateLimitingInterface) *clusterQueue {
+	return &clusterQueue{
+		queue:         queue,
+		resyncLimiter: &workqueue.TypedBucketRateLimiter[string]{Limiter: rate.NewLimiter(ResyncRateLimit, ResyncBurst)},
+	}
+}
+
+// add queues the given key to be reconciled right away.
+func (q *clusterQueue) add(key string) {
+	q.queue.Add(key)
+}
+
+// addResync queues the given key once the resync rate limit allows it.
+func (q *clusterQueue) addResync(key string) {
+	q.queue.AddAfter(key, q.resyncLimiter.When(key))
+}
+
+// eventHandler returns an informer event handler queueing the keys returned by the given function. The objects in the initial
+// listing of the informer and the periodic resyncs, which are updates without any change, are queued as resyncs.
+func (q *clusterQueue) eventHandler(keyFunc queueKeyFunc) cache.ResourceEventHandler {
+	enqueue := func(obj interface{}, add func(string)) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			logger.Errorf(err, "Unable to queue object %T", obj)
+			return
+		}
+
+		if key := keyFunc(accessor); key != "" {
+			add(key)
+		}
+	}
+
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if isInInitialList {
+				enqueue(obj, q.addResync)
+			} else {
+				enqueue(obj, q.add)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if isResync(oldObj, newObj) {
+				enqueue(newObj, q.addResync)
+			} else {
+				enqueue(newObj, q.add)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			enqueue(obj, q.add)
+		},
+	}
+}
+
+func isResync(oldObj, newObj interface{}) bool {
+	oldAccessor, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+
+	newAccessor, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+
+	if oldAccessor.GetResourceVersion() == newAccessor.GetResourceVersion() {
+		logger.V(log.TRACE).Infof("Resync of %T %q", newObj, newAccessor.GetName())
+
+		return true
+	}
+
+	return false
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	"github.com/submariner-io/submariner-operator/pkg/embeddedyamls"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -51,7 +52,7 @@ func NewCRDsController(
 
 			return accessor.GetName()
 		}, crdInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(metrics.InstrumentSync("SubmarinerBrokerCRDsController", c.sync)).
 		ToController("SubmarinerBrokerCRDsController", recorder)
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stolostron/submariner-addon/pkg/constants"
 	brokerinfo "github.com/stolostron/submariner-addon/pkg/hub/submarinerbrokerinfo"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/finalizer"
 	"github.com/submariner-io/admiral/pkg/log"
@@ -110,7 +111,7 @@ func NewController(kubeClient kubernetes.Interface,
 
 			return factory.DefaultQueueKey
 		}, addOnInformer.ManagedClusterAddOns().Informer()).
-		WithSync(c.sync).
+		WithSync(metrics.InstrumentSync("SubmarinerBrokerController", c.sync)).
 		ToController("SubmarinerBrokerController", recorder)
 }
 
@@ -122,6 +123,8 @@ func (c *submarinerBrokerController) sync(ctx context.Context, syncCtx factory.S
 	logger.V(log.TRACE).Infof("Entering sync for %q", syncCtx.QueueKey())
 	defer logger.V(log.TRACE).Infof("Exiting sync for %q", syncCtx.QueueKey())
 
+	defer c.recordBrokerNamespaces()
+
 	clusterAddOn, err := c.clusterAddOnLister.Get(constants.SubmarinerAddOnName)
 	if apierrors.IsNotFound(err) {
 		return nil
@@ -300,6 +303,24 @@ func (c *submarinerBrokerController) doAllClusterSetCleanup(ctx context.Context,
 		constants.SubmarinerAddOnFinalizer)
 }
 
+// recordBrokerNamespaces records the number of broker namespaces, one per ManagedClusterSet annotated with its broker namespace.
+func (c *submarinerBrokerController) recordBrokerNamespaces() {
+	clusterSets, err := c.clusterSetLister.List(labels.Everything())
+	if err != nil {
+		return
+	}
+
+	count := 0
+
+	for _, clusterSet := range clusterSets {
+		if clusterSet.GetAnnotations()[SubmBrokerNamespaceKey] != "" && clusterSet.DeletionTimestamp.IsZero() {
+			count++
+		}
+	}
+
+	metrics.SetBrokerNamespaces(count)
+}
+
 func assetFunc(brokerNS string) resourceapply.AssetFunc {
 	config := &brokerConfig{
 		SubmarinerNamespace: brokerNS,
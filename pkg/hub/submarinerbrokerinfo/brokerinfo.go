@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
 	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/reporter"
 	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
@@ -19,21 +20,24 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/kubernetes"
 	controllerclient "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// submarinerRelease is the Submariner release deployed by default, it must match the release of the submariner-operator
+// module the addon depends on. The catalog channel and the default operator images follow it.
+const submarinerRelease = "0.19"
+
 const (
 	catalogName                   = "submariner"
 	defaultCatalogSource          = "redhat-operators"
 	defaultCatalogSourceNamespace = "openshift-marketplace"
-	defaultCatalogChannel         = "stable-0.19"
+	defaultCatalogChannel         = "stable-" + submarinerRelease
 	defaultCableDriver            = "libreswan"
+	defaultOperatorImage          = "registry.redhat.io/rhacm2/submariner-rhel9-operator:v" + submarinerRelease
+	defaultUpstreamOperatorImage  = "quay.io/submariner/submariner-operator:release-" + submarinerRelease
 	defaultInstallationNamespace  = "open-cluster-management-agent-addon"
 	brokerAPIServer               = "BROKER_API_SERVER"
 	ocpInfrastructureName         = "cluster"
@@ -43,6 +47,14 @@ const (
 	namespaceMaxLength            = 63
 )
 
+// The reasons of the failures to build the broker information, as recorded in the metrics.
+const (
+	failureGlobalnet       = "Globalnet"
+	failureBrokerAPIServer = "BrokerAPIServer"
+	failureIPSecPSK        = "IPSecPSK"
+	failureBrokerToken     = "BrokerToken"
+)
+
 var (
 	logger = log.Logger{Logger: logf.Log.WithName("BrokerInfo")}
 
@@ -95,20 +107,23 @@ type SubmarinerBrokerInfo struct {
 	LighthouseCoreDNSImage    string
 	MetricsProxyImage         string
 	NettestImage              string
+	OperatorImage             string
 	NodeSelector              map[string]string
 	Tolerations               []corev1.Toleration
 }
 
-// Get retrieves submariner broker information consolidated with hub information.
+// Get retrieves submariner broker information consolidated with hub information, read from the given cache. The default
+// submariner-operator image depends on the product of the managed cluster: clusters which aren't OpenShift clusters can't pull
+// the Red Hat image without a Red Hat pull secret, they use the upstream image.
 func Get(
 	ctx context.Context,
-	kubeClient kubernetes.Interface,
-	dynamicClient dynamic.Interface,
+	hubCache *Cache,
 	controllerClient controllerclient.Client,
 	clusterName string,
 	brokerNamespace string,
 	submarinerConfig *configv1alpha1.SubmarinerConfig,
 	installationNamespace string,
+	clusterProduct string,
 ) (*SubmarinerBrokerInfo, error) {
 	brokerInfo := &SubmarinerBrokerInfo{
 		CableDriver:            defaultCableDriver,
@@ -124,6 +139,11 @@ func Get(
 		NodeSelector:           make(map[string]string),
 		Tolerations:            make([]corev1.Toleration, 0),
 		HaltOnCertificateError: true,
+		OperatorImage:          defaultOperatorImage,
+	}
+
+	if !constants.IsOpenShiftProduct(clusterProduct) {
+		brokerInfo.OperatorImage = defaultUpstreamOperatorImage
 	}
 
 	if installationNamespace != "" {
@@ -132,25 +152,29 @@ func Get(
 
 	err := applyGlobalnetConfig(ctx, controllerClient, brokerNamespace, clusterName, brokerInfo, submarinerConfig)
 	if err != nil {
+		metrics.RecordBrokerInfoFailure(failureGlobalnet)
 		return nil, err
 	}
 
-	apiServer, err := getBrokerAPIServer(ctx, dynamicClient)
+	apiServer, err := getBrokerAPIServer(ctx, hubCache)
 	if err != nil {
+		metrics.RecordBrokerInfoFailure(failureBrokerAPIServer)
 		return nil, err
 	}
 
 	brokerInfo.BrokerAPIServer = apiServer
 
-	ipSecPSK, err := getIPSecPSK(ctx, kubeClient, brokerNamespace)
+	ipSecPSK, err := getIPSecPSK(ctx, hubCache, brokerNamespace)
 	if err != nil {
+		metrics.RecordBrokerInfoFailure(failureIPSecPSK)
 		return nil, err
 	}
 
 	brokerInfo.IPSecPSK = ipSecPSK
 
-	token, ca, err := getBrokerTokenAndCA(ctx, kubeClient, dynamicClient, brokerNamespace, clusterName, apiServer)
+	token, ca, err := getBrokerTokenAndCA(ctx, hubCache, brokerNamespace, clusterName, apiServer)
 	if err != nil {
+		metrics.RecordBrokerInfoFailure(failureBrokerToken)
 		return nil, err
 	}
 
@@ -187,6 +211,8 @@ func applyGlobalnetConfig(ctx context.Context, controllerClient controllerclient
 
 		status := reporter.Silent()
 		err = globalnet.AllocateAndUpdateGlobalCIDRConfigMap(ctx, controllerClient, brokerNamespace, &netconfig, status)
+		metrics.RecordGlobalnetAllocation(err)
+
 		if err != nil {
 			logger.Errorf(err, "Unable to allocate globalCIDR to cluster %q", clusterName)
 			return err
@@ -237,6 +263,7 @@ func applySubmarinerImageConfig(brokerInfo *SubmarinerBrokerInfo, submarinerConf
 	setIfValueNotDefault(&brokerInfo.SubmarinerGlobalnetImage, submarinerConfig.Spec.ImagePullSpecs.SubmarinerGlobalnetImagePullSpec)
 	setIfValueNotDefault(&brokerInfo.MetricsProxyImage, submarinerConfig.Spec.ImagePullSpecs.MetricsProxyImagePullSpec)
 	setIfValueNotDefault(&brokerInfo.NettestImage, submarinerConfig.Spec.ImagePullSpecs.NettestImagePullSpec)
+	setIfValueNotDefault(&brokerInfo.OperatorImage, submarinerConfig.Spec.ImagePullSpecs.SubmarinerOperatorImagePullSpec)
 }
 
 func setIfValueNotDefault[T comparable](target *T, value T) {
@@ -246,8 +273,8 @@ func setIfValueNotDefault[T comparable](target *T, value T) {
 	}
 }
 
-func getIPSecPSK(ctx context.Context, client kubernetes.Interface, brokerNamespace string) (string, error) {
-	secret, err := client.CoreV1().Secrets(brokerNamespace).Get(ctx, constants.IPSecPSKSecretName, metav1.GetOptions{})
+func getIPSecPSK(ctx context.Context, hubCache *Cache, brokerNamespace string) (string, error) {
+	secret, err := hubCache.getSecret(ctx, brokerNamespace, constants.IPSecPSKSecretName)
 	if err != nil {
 		return "", fmt.Errorf("failed to get broker IPSEC PSK secret %v/%v: %w",
 			brokerNamespace, constants.IPSecPSKSecretName, err)
@@ -256,9 +283,8 @@ func getIPSecPSK(ctx context.Context, client kubernetes.Interface, brokerNamespa
 	return base64.StdEncoding.EncodeToString(secret.Data["psk"]), nil
 }
 
-func getBrokerAPIServer(ctx context.Context, dynamicClient dynamic.Interface) (string, error) {
-	infrastructureConfig, err := dynamicClient.Resource(infrastructureGVR).
-		Get(ctx, ocpInfrastructureName, metav1.GetOptions{})
+func getBrokerAPIServer(ctx context.Context, hubCache *Cache) (string, error) {
+	infrastructureConfig, err := hubCache.getOpenShiftConfig(ctx, infrastructureGVR, ocpInfrastructureName)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			apiServer := os.Getenv(brokerAPIServer)
@@ -284,15 +310,13 @@ func getBrokerAPIServer(ctx context.Context, dynamicClient dynamic.Interface) (s
 	return strings.Trim(strings.Trim(apiServer, "hpst"), "/:"), nil
 }
 
-func getKubeAPIServerCA(ctx context.Context, kubeAPIServer string, kubeClient kubernetes.Interface,
-	dynamicClient dynamic.Interface,
-) ([]byte, error) {
+func getKubeAPIServerCA(ctx context.Context, kubeAPIServer string, hubCache *Cache) ([]byte, error) {
 	kubeAPIServerURL, err := url.Parse(fmt.Sprintf("https://%s", kubeAPIServer))
 	if err != nil {
 		return nil, err
 	}
 
-	unstructuredAPIServer, err := dynamicClient.Resource(apiServerGVR).Get(ctx, ocpAPIServerName, metav1.GetOptions{})
+	unstructuredAPIServer, err := hubCache.getOpenShiftConfig(ctx, apiServerGVR, ocpAPIServerName)
 	if apierrors.IsNotFound(err) {
 		return nil, nil
 	}
@@ -314,7 +338,7 @@ func getKubeAPIServerCA(ctx context.Context, kubeAPIServer string, kubeClient ku
 			}
 
 			secretName := namedCert.ServingCertificate.Name
-			secret, err := kubeClient.CoreV1().Secrets(ocpConfigNamespace).Get(ctx, secretName, metav1.GetOptions{})
+			secret, err := hubCache.getSecret(ctx, ocpConfigNamespace, secretName)
 			if err != nil {
 				return nil, err
 			}
@@ -335,67 +359,71 @@ func getKubeAPIServerCA(ctx context.Context, kubeAPIServer string, kubeClient ku
 	return nil, nil
 }
 
-func getBrokerTokenAndCA(ctx context.Context, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
-	brokerNS, clusterName, kubeAPIServer string,
+func getBrokerTokenAndCA(ctx context.Context, hubCache *Cache, brokerNS, clusterName, kubeAPIServer string,
 ) (token, ca string, err error) {
-	sa, err := kubeClient.CoreV1().ServiceAccounts(brokerNS).Get(ctx, clusterName, metav1.GetOptions{})
+	sa, err := hubCache.getServiceAccount(ctx, brokerNS, clusterName)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get agent ServiceAccount %v/%v: %w", brokerNS, clusterName, err)
 	}
 
-	tokenSecret, err := getTokenSecretForSA(ctx, kubeClient, sa)
+	tokenSecret, err := getTokenSecretForSA(ctx, hubCache, sa)
 	if err != nil {
 		return "", "", err
 	}
 
-	return getTokenAndCAFromSecret(ctx, tokenSecret, kubeAPIServer, kubeClient, dynamicClient)
+	return getTokenAndCAFromSecret(ctx, tokenSecret, kubeAPIServer, hubCache)
 }
 
-func getTokenSecretForSA(ctx context.Context, client kubernetes.Interface, sa *corev1.ServiceAccount,
+func getTokenSecretForSA(ctx context.Context, hubCache *Cache, sa *corev1.ServiceAccount,
 ) (*corev1.Secret, error) {
-	var secret *corev1.Secret
-	saSecrets, err := client.CoreV1().Secrets(sa.Namespace).List(ctx, metav1.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector("type", string(corev1.SecretTypeServiceAccountToken)).String(),
-	})
+	saSecrets, err := hubCache.listSecrets(ctx, sa.Namespace, corev1.SecretTypeServiceAccountToken)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get secrets of type service-account-token in %v", sa.Namespace)
 	}
 
-	for i := 0; i < len(saSecrets.Items); i++ {
-		if saSecrets.Items[i].Annotations[corev1.ServiceAccountNameKey] == sa.Name {
-			secret = &saSecrets.Items[i]
+	var secret *corev1.Secret
+
+	for _, saSecret := range saSecrets {
+		if saSecret.Annotations[corev1.ServiceAccountNameKey] == sa.Name {
+			secret = saSecret
 		}
 	}
 
+	if secret != nil {
+		return secret, nil
+	}
+
 	// Secret not found, so create one and return.
-	if secret == nil {
-		secret = &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        GenerateBrokerName(sa.Name),
-				Annotations: map[string]string{corev1.ServiceAccountNameKey: sa.Name},
-			},
-			Type: corev1.SecretTypeServiceAccountToken,
-		}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        GenerateBrokerName(sa.Name),
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: sa.Name},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
 
-		secret, err = client.CoreV1().Secrets(sa.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create secret %s/%s of type service-account",
-				sa.Name, sa.Namespace)
-		}
+	created, err := hubCache.kubeClient.CoreV1().Secrets(sa.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// The Secret was created but isn't cached yet.
+		return hubCache.kubeClient.CoreV1().Secrets(sa.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create secret %s/%s of type service-account",
+			sa.Name, sa.Namespace)
 	}
 
-	return secret, nil
+	return created, nil
 }
 
-func getTokenAndCAFromSecret(ctx context.Context, tokenSecret *corev1.Secret, kubeAPIServer string,
-	kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
+func getTokenAndCAFromSecret(ctx context.Context, tokenSecret *corev1.Secret, kubeAPIServer string, hubCache *Cache,
 ) (token, ca string, err error) {
 	if len(tokenSecret.Data) == 0 || tokenSecret.Data["token"] == nil {
 		return "", "", fmt.Errorf("token data not yet generated for secret %s/%s", tokenSecret.Namespace, tokenSecret.Name)
 	}
 
 	// try to get ca from apiserver secret firstly, if the ca cannot be found, get it from sa
-	kubeAPIServerCA, err := getKubeAPIServerCA(ctx, kubeAPIServer, kubeClient, dynamicClient)
+	kubeAPIServerCA, err := getKubeAPIServerCA(ctx, kubeAPIServer, hubCache)
 	if err != nil {
 		return "", "", err
 	}
@@ -3,21 +3,25 @@ package submarinerbrokerinfo_test
 import (
 	"context"
 	"encoding/base64"
+	"os"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	apiconfigv1 "github.com/openshift/api/config/v1"
 	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/constants"
 	"github.com/stolostron/submariner-addon/pkg/hub/submarinerbrokerinfo"
 	"github.com/submariner-io/submariner-operator/pkg/discovery/globalnet"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	clientTesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -48,12 +52,35 @@ var _ = Describe("Function Get", func() {
 		gnConfigMap           *corev1.ConfigMap
 		kubeObjs              []runtime.Object
 		dynamicObjs           []runtime.Object
+		openShift             bool
+		clusterProduct        string
+		uncachedSA            bool
+		kubeClient            *kubefake.Clientset
+		dynamicClient         *dynamicfake.FakeDynamicClient
+		brokerClient          client.Client
+		brokerInfoCache       *submarinerbrokerinfo.Cache
 		brokerInfo            *submarinerbrokerinfo.SubmarinerBrokerInfo
 		err                   error
 	)
 
+	getBrokerInfo := func() {
+		brokerInfo, err = submarinerbrokerinfo.Get(
+			context.TODO(),
+			brokerInfoCache,
+			brokerClient,
+			clusterName,
+			brokerNamespace,
+			submarinerConfig,
+			installationNamespace,
+			clusterProduct,
+		)
+	}
+
 	BeforeEach(func() {
 		installationNamespace = ""
+		openShift = true
+		clusterProduct = constants.ProductOCP
+		uncachedSA = false
 
 		infrastructure = &unstructured.Unstructured{
 			Object: map[string]interface{}{
@@ -110,16 +137,35 @@ var _ = Describe("Function Get", func() {
 			brokerObjs = append(brokerObjs, gnConfigMap)
 		}
 
-		brokerInfo, err = submarinerbrokerinfo.Get(
-			context.TODO(),
-			kubefake.NewSimpleClientset(kubeObjs...),
-			dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), dynamicObjs...),
-			fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(brokerObjs...).Build(),
-			clusterName,
-			brokerNamespace,
-			submarinerConfig,
-			installationNamespace,
-		)
+		brokerClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(brokerObjs...).Build()
+
+		kubeClient = kubefake.NewSimpleClientset(kubeObjs...)
+		if openShift {
+			kubeClient.Resources = []*metav1.APIResourceList{{
+				GroupVersion: "config.openshift.io/v1",
+				APIResources: []metav1.APIResource{{Name: "infrastructures"}, {Name: "apiservers"}},
+			}}
+		}
+
+		if uncachedSA {
+			kubeClient.PrependReactor("list", "serviceaccounts", func(_ clientTesting.Action) (bool, runtime.Object, error) {
+				return true, &corev1.ServiceAccountList{}, nil
+			})
+		}
+
+		dynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				{Group: "config.openshift.io", Version: "v1", Resource: "infrastructures"}: "InfrastructureList",
+				{Group: "config.openshift.io", Version: "v1", Resource: "apiservers"}:      "APIServerList",
+			}, dynamicObjs...)
+
+		ctx, stop := context.WithCancel(context.Background())
+		DeferCleanup(stop)
+
+		brokerInfoCache = submarinerbrokerinfo.NewCache(kubeClient, dynamicClient)
+		Expect(brokerInfoCache.Start(ctx)).To(Succeed())
+
+		getBrokerInfo()
 	})
 
 	Context("on success", func() {
@@ -142,6 +188,80 @@ var _ = Describe("Function Get", func() {
 			Expect(brokerInfo.ClusterName).To(Equal(clusterName))
 		})
 
+		It("should only read the hub resources from the API server to fill the caches", func() {
+			for _, action := range append(kubeClient.Actions(), dynamicClient.Actions()...) {
+				Expect(action.GetVerb()).To(BeElementOf("list", "watch"), "Unexpected action %#v", action)
+			}
+		})
+
+		When("the broker information of the cluster is retrieved again", func() {
+			JustBeforeEach(func() {
+				kubeClient.ClearActions()
+				dynamicClient.ClearActions()
+
+				getBrokerInfo()
+				Expect(err).To(Succeed())
+			})
+
+			It("should not read the hub resources from the API server", func() {
+				Expect(kubeClient.Actions()).To(BeEmpty())
+				Expect(dynamicClient.Actions()).To(BeEmpty())
+				Expect(brokerInfo.BrokerToken).To(Equal(brokerToken))
+			})
+		})
+
+		When("the cluster ServiceAccount isn't cached", func() {
+			BeforeEach(func() {
+				uncachedSA = true
+			})
+
+			It("should get it from the API server", func() {
+				gets := 0
+
+				for _, action := range kubeClient.Actions() {
+					if action.GetVerb() == "get" {
+						Expect(action.GetResource().Resource).To(Equal("serviceaccounts"))
+						gets++
+					}
+				}
+
+				Expect(gets).To(Equal(1))
+				Expect(brokerInfo.BrokerToken).To(Equal(brokerToken))
+			})
+		})
+
+		When("the hub isn't an OpenShift cluster", func() {
+			const envAPIServer = "10.0.0.1:6443"
+
+			BeforeEach(func() {
+				openShift = false
+
+				Expect(os.Setenv("BROKER_API_SERVER", envAPIServer)).To(Succeed())
+				DeferCleanup(os.Unsetenv, "BROKER_API_SERVER")
+			})
+
+			It("should return the broker API server from the environment without reading the OpenShift configuration", func() {
+				Expect(brokerInfo.BrokerAPIServer).To(Equal(envAPIServer))
+				Expect(dynamicClient.Actions()).To(BeEmpty())
+			})
+		})
+
+		When("the managed cluster is an OpenShift cluster", func() {
+			It("should return the default Red Hat operator image", func() {
+				Expect(brokerInfo.OperatorImage).To(Equal("registry.redhat.io/rhacm2/submariner-rhel9-operator:v0.19"))
+			})
+		})
+
+		When("the managed cluster isn't an OpenShift cluster", func() {
+			BeforeEach(func() {
+				clusterProduct = constants.ProductEKS
+			})
+
+			It("should return the default upstream operator image", func() {
+				Expect(brokerInfo.OperatorImage).To(Equal("quay.io/submariner/submariner-operator:release-0.19"))
+			})
+		})
+
 		When("no installation namespace is provided", func() {
 			It("should return the default", func() {
 				Expect(brokerInfo.InstallationNamespace).To(Equal("open-cluster-management-agent-addon"))
@@ -200,6 +320,7 @@ var _ = Describe("Function Get", func() {
 							LighthouseAgentImagePullSpec:      "quay.io/submariner/lighthouse-agent:10.0.1",
 							LighthouseCoreDNSImagePullSpec:    "quay.io/submariner/lighthouse-coredns:10.0.1",
 							SubmarinerRouteAgentImagePullSpec: "quay.io/submariner/submariner-route-agent:10.0.1",
+							SubmarinerOperatorImagePullSpec:   "quay.io/submariner/submariner-operator:10.0.1",
 						},
 						CableDriver:              "wireguard",
 						IPSecNATTPort:            5678,
@@ -228,6 +349,7 @@ var _ = Describe("Function Get", func() {
 				Expect(brokerInfo.AirGappedDeployment).To(Equal(submarinerConfig.Spec.AirGappedDeployment))
 				Expect(brokerInfo.SubmarinerGatewayImage).To(Equal(submarinerConfig.Spec.ImagePullSpecs.SubmarinerImagePullSpec))
 				Expect(brokerInfo.SubmarinerRouteAgentImage).To(Equal(submarinerConfig.Spec.ImagePullSpecs.SubmarinerRouteAgentImagePullSpec))
+				Expect(brokerInfo.OperatorImage).To(Equal(submarinerConfig.Spec.ImagePullSpecs.SubmarinerOperatorImagePullSpec))
 				Expect(brokerInfo.InsecureBrokerConnection).To(Equal(submarinerConfig.Spec.InsecureBrokerConnection))
 				Expect(brokerInfo.HaltOnCertificateError).To(Equal(submarinerConfig.Spec.HaltOnCertificateError))
 			})
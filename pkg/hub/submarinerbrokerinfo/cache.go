@@ -0,0 +1,279 @@
+package submarinerbrokerinfo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/submariner-io/admiral/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Cache serves the hub resources read to build the broker information from informers, so that building the broker
+// information of a cluster only reads from the API server the resources missing from the informers. The Secrets and
+// ServiceAccounts are cached by broker namespace, from the first time the broker information of a cluster in the namespace is
+// built until Forget is called, and the OpenShift configuration is only cached if the hub is an OpenShift cluster.
+type Cache struct {
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	started       bool
+	// stop is closed when the informers must be stopped, it's nil if they're never stopped.
+	stop            <-chan struct{}
+	openShift       bool
+	infrastructures cache.GenericLister
+	apiServers      cache.GenericLister
+	configSecrets   corev1listers.SecretLister
+	mutex           sync.Mutex
+	namespaces      map[string]*namespaceCache
+}
+
+type namespaceCache struct {
+	secrets         corev1listers.SecretLister
+	serviceAccounts corev1listers.ServiceAccountLister
+	synced          []cache.InformerSynced
+	stop            context.CancelFunc
+}
+
+func NewCache(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) *Cache {
+	return &Cache{
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		namespaces:    map[string]*namespaceCache{},
+	}
+}
+
+// Start starts the informers of the OpenShift configuration and waits for their caches to sync. The informers of the broker
+// namespaces are started on demand and all the informers are stopped when the given context is done.
+func (c *Cache) Start(ctx context.Context) error {
+	c.mutex.Lock()
+	c.started = true
+	c.stop = ctx.Done()
+	c.mutex.Unlock()
+
+	openShift, err := c.isOpenShift()
+	if err != nil {
+		return err
+	}
+
+	if !openShift {
+		logger.Infof("The OpenShift configuration resources aren't available on the hub")
+		return nil
+	}
+
+	// The Infrastructure and APIServer configurations are both named "cluster".
+	byName := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamicClient, 0, metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", ocpInfrastructureName).String()
+		})
+	configInformers := kubeinformers.NewSharedInformerFactoryWithOptions(c.kubeClient, 0,
+		kubeinformers.WithNamespace(ocpConfigNamespace))
+
+	infrastructures := byName.ForResource(infrastructureGVR)
+	apiServers := byName.ForResource(apiServerGVR)
+	configSecrets := configInformers.Core().V1().Secrets()
+
+	synced := []cache.InformerSynced{
+		infrastructures.Informer().HasSynced, apiServers.Informer().HasSynced, configSecrets.Informer().HasSynced,
+	}
+
+	byName.Start(ctx.Done())
+	configInformers.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return fmt.Errorf("failed to wait for the OpenShift configuration caches to sync")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.openShift = true
+	c.infrastructures = infrastructures.Lister()
+	c.apiServers = apiServers.Lister()
+	c.configSecrets = configSecrets.Lister()
+
+	return nil
+}
+
+// Forget stops the informers of the given broker namespace.
+func (c *Cache) Forget(brokerNamespace string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if nsCache, ok := c.namespaces[brokerNamespace]; ok {
+		logger.V(log.DEBUG).Infof("Stopping the broker information informers of namespace %q", brokerNamespace)
+
+		nsCache.stop()
+		delete(c.namespaces, brokerNamespace)
+	}
+}
+
+func (c *Cache) isOpenShift() (bool, error) {
+	resources, err := c.kubeClient.Discovery().ServerResourcesForGroupVersion(infrastructureGVR.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to discover the resources of %q: %w", infrastructureGVR.GroupVersion(), err)
+	}
+
+	found := 0
+
+	for i := range resources.APIResources {
+		if resources.APIResources[i].Name == infrastructureGVR.Resource || resources.APIResources[i].Name == apiServerGVR.Resource {
+			found++
+		}
+	}
+
+	return found == 2, nil
+}
+
+func (c *Cache) namespace(ctx context.Context, brokerNamespace string) (*namespaceCache, error) {
+	c.mutex.Lock()
+
+	nsCache, ok := c.namespaces[brokerNamespace]
+	if !ok {
+		if !c.started {
+			c.mutex.Unlock()
+			return nil, fmt.Errorf("the broker information cache isn't started")
+		}
+
+		logger.V(log.DEBUG).Infof("Starting the broker information informers of namespace %q", brokerNamespace)
+
+		informerCtx, stop := context.WithCancel(wait.ContextForChannel(c.stop))
+		informers := kubeinformers.NewSharedInformerFactoryWithOptions(c.kubeClient, 0, kubeinformers.WithNamespace(brokerNamespace))
+		secrets := informers.Core().V1().Secrets()
+		serviceAccounts := informers.Core().V1().ServiceAccounts()
+
+		nsCache = &namespaceCache{
+			secrets:         secrets.Lister(),
+			serviceAccounts: serviceAccounts.Lister(),
+			synced:          []cache.InformerSynced{secrets.Informer().HasSynced, serviceAccounts.Informer().HasSynced},
+			stop:            stop,
+		}
+
+		informers.Start(informerCtx.Done())
+		c.namespaces[brokerNamespace] = nsCache
+	}
+
+	c.mutex.Unlock()
+
+	if !cache.WaitForCacheSync(ctx.Done(), nsCache.synced...) {
+		return nil, fmt.Errorf("failed to wait for the caches of broker namespace %q to sync", brokerNamespace)
+	}
+
+	return nsCache, nil
+}
+
+func (c *Cache) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	var lister corev1listers.SecretNamespaceLister
+
+	if namespace == ocpConfigNamespace {
+		c.mutex.Lock()
+		if c.configSecrets != nil {
+			lister = c.configSecrets.Secrets(namespace)
+		}
+		c.mutex.Unlock()
+	} else {
+		nsCache, err := c.namespace(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		lister = nsCache.secrets.Secrets(namespace)
+	}
+
+	if lister != nil {
+		secret, err := lister.Get(name)
+		if !apierrors.IsNotFound(err) {
+			return secret, err
+		}
+	}
+
+	logger.V(log.DEBUG).Infof("Secret %s/%s not cached, getting it from the API server", namespace, name)
+
+	return c.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// listSecrets returns the cached Secrets of the given broker namespace of the given type.
+func (c *Cache) listSecrets(ctx context.Context, namespace string, secretType corev1.SecretType) ([]*corev1.Secret, error) {
+	nsCache, err := c.namespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := nsCache.secrets.Secrets(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	ofType := []*corev1.Secret{}
+
+	for _, secret := range secrets {
+		if secret.Type == secretType {
+			ofType = append(ofType, secret)
+		}
+	}
+
+	return ofType, nil
+}
+
+func (c *Cache) getServiceAccount(ctx context.Context, namespace, name string) (*corev1.ServiceAccount, error) {
+	nsCache, err := c.namespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	sa, err := nsCache.serviceAccounts.ServiceAccounts(namespace).Get(name)
+	if !apierrors.IsNotFound(err) {
+		return sa, err
+	}
+
+	logger.V(log.DEBUG).Infof("ServiceAccount %s/%s not cached, getting it from the API server", namespace, name)
+
+	return c.kubeClient.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// getOpenShiftConfig returns the given cluster-scoped OpenShift configuration resource, or a NotFound error if the hub isn't
+// an OpenShift cluster.
+func (c *Cache) getOpenShiftConfig(ctx context.Context, gvr schema.GroupVersionResource, name string,
+) (*unstructured.Unstructured, error) {
+	c.mutex.Lock()
+	openShift := c.openShift
+	lister := c.infrastructures
+
+	if gvr == apiServerGVR {
+		lister = c.apiServers
+	}
+	c.mutex.Unlock()
+
+	if !openShift {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+
+	obj, err := lister.Get(name)
+	if err == nil {
+		return obj.(*unstructured.Unstructured), nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	logger.V(log.DEBUG).Infof("%s %q not cached, getting it from the API server", gvr.Resource, name)
+
+	return c.dynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+}
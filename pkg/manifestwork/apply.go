@@ -3,40 +3,143 @@ package manifestwork
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/pkg/errors"
 	"github.com/stolostron/submariner-addon/pkg/redact"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/log"
+	coreresource "github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/util"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	workclient "open-cluster-management.io/api/client/work/clientset/versioned"
+	workclientv1 "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// ContentHashAnnotation is set on the applied ManifestWorks to the hash of their content, so that re-applying an unchanged
+// ManifestWork is skipped.
+const ContentHashAnnotation = "submarineraddon.open-cluster-management.io/content-hash"
+
 var logger = log.Logger{Logger: logf.Log.WithName("ManifestWork")}
 
-func Apply(ctx context.Context, client workclient.Interface, toApply *workv1.ManifestWork, recorder events.Recorder) error {
-	resourceInterface := resource.ForManifestWork(client.WorkV1().ManifestWorks(toApply.Namespace))
+// Apply creates or updates the given ManifestWork with its content hash annotation, and returns the applied ManifestWork. Nothing
+// is applied if the ManifestWork in the given lister has the same content hash and its spec wasn't edited since.
+func Apply(ctx context.Context, client workclient.Interface, lister worklister.ManifestWorkLister, toApply *workv1.ManifestWork,
+	recorder events.Recorder,
+) (*workv1.ManifestWork, error) {
+	hash, err := ContentHash(toApply)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := lister.ManifestWorks(toApply.Namespace).Get(toApply.Name)
+	if err == nil && existing.Annotations[ContentHashAnnotation] == hash && existing.DeletionTimestamp.IsZero() &&
+		sameSpec(&existing.Spec, &toApply.Spec) {
+		logger.V(log.TRACE).Infof("ManifestWork \"%s/%s\" is up to date", toApply.Namespace, toApply.Name)
+		return existing, nil
+	}
+
+	toApply = toApply.DeepCopy()
+	if toApply.Annotations == nil {
+		toApply.Annotations = map[string]string{}
+	}
+
+	toApply.Annotations[ContentHashAnnotation] = hash
+
+	var applied *workv1.ManifestWork
 
-	result, err := util.CreateOrUpdate[*workv1.ManifestWork](ctx, resourceInterface, toApply,
-		func(existing *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	result, err := util.CreateOrUpdate[*workv1.ManifestWork](ctx, appliedRecorder(client.WorkV1().ManifestWorks(toApply.Namespace), &applied),
+		toApply, func(existing *workv1.ManifestWork) (*workv1.ManifestWork, error) {
 			existing.Spec = toApply.Spec
 
+			if existing.Annotations == nil {
+				existing.Annotations = map[string]string{}
+			}
+
+			existing.Annotations[ContentHashAnnotation] = hash
+
 			return existing, nil
 		})
 
 	if result == util.OperationResultCreated {
-		recorder.Event("ManifestWorkApplied", fmt.Sprintf("manifestwork %s/%s was created", toApply.Namespace, toApply.Name))
+		recorder.Eventf("ManifestWorkApplied", "manifestwork %s/%s was created with content hash %s", toApply.Namespace, toApply.Name, hash)
 		logger.Infof("Created ManifestWork \"%s/%s\": %s", toApply.Namespace, toApply.Name, manifestsToString(toApply.Spec.Workload.Manifests))
 	} else if result == util.OperationResultUpdated {
-		recorder.Event("ManifestWorkApplied", fmt.Sprintf("manifestwork %s/%s was updated", toApply.Namespace, toApply.Name))
+		recorder.Eventf("ManifestWorkApplied", "manifestwork %s/%s was updated with content hash %s", toApply.Namespace, toApply.Name, hash)
 		logger.Infof("Updated ManifestWork \"%s/%s\"", toApply.Namespace, toApply.Name)
 	}
 
-	return err
+	return applied, err
+}
+
+// ContentHash returns the hash of the content of the given ManifestWork, that is its spec.
+func ContentHash(work *workv1.ManifestWork) (string, error) {
+	content, err := json.Marshal(&work.Spec)
+	if err != nil {
+		return "", errors.Wrapf(err, "error marshalling the spec of ManifestWork \"%s/%s\"", work.Namespace, work.Name)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+// sameSpec returns whether the given ManifestWork specs are semantically equal. The manifests are compared once decoded, since
+// the API server doesn't preserve the formatting of their JSON.
+func sameSpec(existing, expected *workv1.ManifestWorkSpec) bool {
+	if len(existing.Workload.Manifests) != len(expected.Workload.Manifests) {
+		return false
+	}
+
+	for i := range expected.Workload.Manifests {
+		var existingManifest, expectedManifest interface{}
+
+		if json.Unmarshal(existing.Workload.Manifests[i].Raw, &existingManifest) != nil ||
+			json.Unmarshal(expected.Workload.Manifests[i].Raw, &expectedManifest) != nil ||
+			!equality.Semantic.DeepEqual(existingManifest, expectedManifest) {
+			return false
+		}
+	}
+
+	existingRest := *existing
+	existingRest.Workload = workv1.ManifestsTemplate{}
+	expectedRest := *expected
+	expectedRest.Workload = workv1.ManifestsTemplate{}
+
+	return equality.Semantic.DeepEqual(existingRest, expectedRest)
+}
+
+// appliedRecorder returns the resource interface of the given ManifestWork client, storing the ManifestWork last read or
+// written by the API server in the given pointer.
+func appliedRecorder(client workclientv1.ManifestWorkInterface, applied **workv1.ManifestWork,
+) coreresource.Interface[*workv1.ManifestWork] {
+	delegate := resource.ForManifestWork(client)
+
+	record := func(work *workv1.ManifestWork, err error) (*workv1.ManifestWork, error) {
+		if err == nil {
+			*applied = work
+		}
+
+		return work, err
+	}
+
+	return &coreresource.InterfaceFuncs[*workv1.ManifestWork]{
+		GetFunc: func(ctx context.Context, name string, options metav1.GetOptions) (*workv1.ManifestWork, error) {
+			return record(delegate.Get(ctx, name, options))
+		},
+		CreateFunc: func(ctx context.Context, obj *workv1.ManifestWork, options metav1.CreateOptions) (*workv1.ManifestWork, error) {
+			return record(delegate.Create(ctx, obj, options))
+		},
+		UpdateFunc: func(ctx context.Context, obj *workv1.ManifestWork, options metav1.UpdateOptions) (*workv1.ManifestWork, error) {
+			return record(delegate.Update(ctx, obj, options))
+		},
+		DeleteFunc: delegate.Delete,
+	}
 }
 
 func manifestsToString(manifests []workv1.Manifest) string {
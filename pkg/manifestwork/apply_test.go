@@ -11,7 +11,9 @@ import (
 	"github.com/submariner-io/admiral/pkg/test"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 	"open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 )
 
@@ -19,11 +21,14 @@ var _ = Describe("Apply", func() {
 	var (
 		work          *workv1.ManifestWork
 		existingWorks []runtime.Object
+		cachedWorks   []*workv1.ManifestWork
 		workClient    *fake.Clientset
+		applied       *workv1.ManifestWork
 	)
 
 	BeforeEach(func() {
 		existingWorks = []runtime.Object{}
+		cachedWorks = []*workv1.ManifestWork{}
 
 		work = &workv1.ManifestWork{
 			TypeMeta: metav1.TypeMeta{},
@@ -50,13 +55,39 @@ var _ = Describe("Apply", func() {
 	})
 
 	doApply := func() error {
-		return manifestwork.Apply(context.TODO(), workClient, work, events.NewLoggingEventRecorder("test"))
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, cached := range cachedWorks {
+			Expect(indexer.Add(cached)).To(Succeed())
+		}
+
+		var err error
+
+		applied, err = manifestwork.Apply(context.TODO(), workClient, worklister.NewManifestWorkLister(indexer), work,
+			events.NewLoggingEventRecorder("test"))
+
+		return err
+	}
+
+	contentHash := func() string {
+		hash, err := manifestwork.ContentHash(work)
+		Expect(err).To(Succeed())
+
+		return hash
+	}
+
+	withContentHash := func(work *workv1.ManifestWork) *workv1.ManifestWork {
+		work = work.DeepCopy()
+		work.Annotations = map[string]string{manifestwork.ContentHashAnnotation: contentHash()}
+
+		return work
 	}
 
 	ensureWork := func() {
 		actual, err := workClient.WorkV1().ManifestWorks(work.Namespace).Get(context.TODO(), work.Name, metav1.GetOptions{})
 		Expect(err).To(Succeed())
 		Expect(actual.Spec).To(Equal(work.Spec))
+		Expect(actual.Annotations).To(HaveKeyWithValue(manifestwork.ContentHashAnnotation, contentHash()))
+		Expect(applied).To(Equal(actual))
 	}
 
 	When("the Work doesn't exist", func() {
@@ -83,6 +114,7 @@ var _ = Describe("Apply", func() {
 
 		Context("and the workload manifest has changed", func() {
 			BeforeEach(func() {
+				cachedWorks = []*workv1.ManifestWork{withContentHash(work)}
 				work.Spec.Workload.Manifests[0].RawExtension.Raw = []byte("{\"foo\": \"updated\"}")
 			})
 
@@ -150,9 +182,59 @@ var _ = Describe("Apply", func() {
 		})
 
 		Context("and the Work Spec has not changed", func() {
+			BeforeEach(func() {
+				existingWorks = []runtime.Object{withContentHash(work)}
+			})
+
 			It("should not update it", func() {
 				Expect(doApply()).To(Succeed())
 				test.EnsureNoActionsForResource(&workClient.Fake, "manifestworks", "update")
+				ensureWork()
+			})
+
+			Context("and the cached Work has the same content hash", func() {
+				BeforeEach(func() {
+					cachedWorks = []*workv1.ManifestWork{withContentHash(work)}
+				})
+
+				It("should not read nor update it", func() {
+					Expect(doApply()).To(Succeed())
+					Expect(workClient.Actions()).To(BeEmpty())
+					Expect(applied).To(Equal(cachedWorks[0]))
+				})
+			})
+
+			Context("and the cached Work has the same content hash but differently formatted manifests", func() {
+				BeforeEach(func() {
+					cachedWorks = []*workv1.ManifestWork{withContentHash(work)}
+					cachedWorks[0].Spec.Workload.Manifests[0].Raw = []byte("{\"foo\":\"bar\"}")
+				})
+
+				It("should not read nor update it", func() {
+					Expect(doApply()).To(Succeed())
+					Expect(workClient.Actions()).To(BeEmpty())
+				})
+			})
+
+			Context("and the cached Work has the same content hash but its spec was edited", func() {
+				BeforeEach(func() {
+					edited := withContentHash(work)
+					edited.Spec.Workload.Manifests[0].Raw = []byte("{\"foo\": \"edited\"}")
+					existingWorks = []runtime.Object{edited}
+					cachedWorks = []*workv1.ManifestWork{edited.DeepCopy()}
+				})
+
+				It("should revert it", func() {
+					Expect(doApply()).To(Succeed())
+					ensureWork()
+				})
+			})
+		})
+
+		Context("and the Work has no content hash annotation", func() {
+			It("should only add the annotation", func() {
+				Expect(doApply()).To(Succeed())
+				ensureWork()
 			})
 		})
 	})
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const agentSubsystem = "agent"
+
+// The gateway label operations.
+const (
+	GatewayLabel   = "label"
+	GatewayUnlabel = "unlabel"
+)
+
+var (
+	gatewayLabelOperations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace: namespace,
+			Subsystem: agentSubsystem,
+			Name:      "gateway_label_operations_total",
+			Help:      "The number of gateway node label operations, by operation and result.",
+		},
+		[]string{"operation", "result"},
+	)
+
+	cloudPreparations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace: namespace,
+			Subsystem: agentSubsystem,
+			Name:      "cloud_preparations_total",
+			Help:      "The number of cloud environment preparation attempts, by provider and result.",
+		},
+		[]string{"provider", "result"},
+	)
+
+	cloudPreparationDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: agentSubsystem,
+			Name:      "cloud_preparation_duration_seconds",
+			Help:      "The duration of the cloud environment preparations, by provider.",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600},
+		},
+		[]string{"provider"},
+	)
+
+	connections = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: agentSubsystem,
+			Name:      "connections",
+			Help:      "The number of connections to other clusters reported in the Submariner resource, by status.",
+		},
+		[]string{"status"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(gatewayLabelOperations, cloudPreparations, cloudPreparationDuration, connections)
+}
+
+// RecordGatewayLabelOperation records the result of labeling or unlabeling a gateway node.
+func RecordGatewayLabelOperation(operation string, err error) {
+	gatewayLabelOperations.WithLabelValues(operation, resultOf(err)).Inc()
+}
+
+// RecordCloudPreparation records the result and duration of preparing the cloud environment with the given provider.
+func RecordCloudPreparation(provider string, duration time.Duration, err error) {
+	cloudPreparations.WithLabelValues(provider, resultOf(err)).Inc()
+	cloudPreparationDuration.WithLabelValues(provider).Observe(duration.Seconds())
+}
+
+// SetConnections records the number of connections by status, resetting the statuses which aren't reported anymore.
+func SetConnections(counts map[string]int) {
+	connections.Reset()
+
+	for status, count := range counts {
+		connections.WithLabelValues(status).Set(float64(count))
+	}
+}
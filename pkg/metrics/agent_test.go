@@ -0,0 +1,65 @@
+package metrics_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+const agentPrefix = "open_cluster_management_submariner_addon_agent_"
+
+var _ = Describe("Agent metrics", func() {
+	When("gateway label operations are recorded", func() {
+		It("should count them by operation and result", func() {
+			metrics.RecordGatewayLabelOperation(metrics.GatewayLabel, nil)
+			metrics.RecordGatewayLabelOperation(metrics.GatewayUnlabel, errors.New("fake error"))
+
+			Expect(sampleValue(agentPrefix+"gateway_label_operations_total",
+				map[string]string{"operation": metrics.GatewayLabel, "result": "success"})).To(Equal(float64(1)))
+			Expect(sampleValue(agentPrefix+"gateway_label_operations_total",
+				map[string]string{"operation": metrics.GatewayUnlabel, "result": "failure"})).To(Equal(float64(1)))
+		})
+	})
+
+	When("cloud preparations are recorded", func() {
+		It("should count them and record their duration by provider", func() {
+			metrics.RecordCloudPreparation("AWS", 10*time.Second, nil)
+			metrics.RecordCloudPreparation("AWS", 20*time.Second, errors.New("fake error"))
+
+			Expect(sampleValue(agentPrefix+"cloud_preparations_total",
+				map[string]string{"provider": "AWS", "result": "success"})).To(Equal(float64(1)))
+			Expect(sampleValue(agentPrefix+"cloud_preparations_total",
+				map[string]string{"provider": "AWS", "result": "failure"})).To(Equal(float64(1)))
+
+			hist, err := testutil.GetHistogramVecFromGatherer(legacyregistry.DefaultGatherer,
+				agentPrefix+"cloud_preparation_duration_seconds", map[string]string{"provider": "AWS"})
+			Expect(err).To(Succeed())
+			Expect(hist.GetAggregatedSampleCount()).To(Equal(uint64(2)))
+			Expect(hist.GetAggregatedSampleSum()).To(Equal(float64(30)))
+		})
+	})
+
+	When("the connections are recorded", func() {
+		It("should set the gauge by status", func() {
+			metrics.SetConnections(map[string]int{"connected": 2, "error": 1})
+
+			Expect(sampleValue(agentPrefix+"connections", map[string]string{"status": "connected"})).To(Equal(float64(2)))
+			Expect(sampleValue(agentPrefix+"connections", map[string]string{"status": "error"})).To(Equal(float64(1)))
+		})
+
+		Context("and a status isn't reported anymore", func() {
+			It("should remove the gauge for the status", func() {
+				metrics.SetConnections(map[string]int{"connected": 2, "error": 1})
+				metrics.SetConnections(map[string]int{"connected": 3})
+
+				Expect(sampleValue(agentPrefix+"connections", map[string]string{"status": "connected"})).To(Equal(float64(3)))
+				Expect(hasSample(agentPrefix+"connections", map[string]string{"status": "error"})).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	hubSubsystem = "hub"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	clusters = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "clusters",
+			Help:      "The number of managed clusters with the submariner addon, by ManagedClusterSet.",
+		},
+		[]string{"cluster_set"},
+	)
+
+	brokerNamespaces = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "broker_namespaces",
+			Help:      "The number of broker namespaces managed for the ManagedClusterSets.",
+		},
+	)
+
+	manifestWorkApplies = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "manifestwork_applies_total",
+			Help:      "The number of ManifestWork applies, by ManifestWork name and result.",
+		},
+		[]string{"manifestwork", "result"},
+	)
+
+	manifestWorkDeletionTimeouts = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "manifestwork_deletion_timeouts_total",
+			Help:      "The number of clean ups which didn't wait any longer for a ManifestWork to be deleted.",
+		},
+	)
+
+	brokerInfoFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "brokerinfo_failures_total",
+			Help:      "The number of failures to build the broker information of a managed cluster, by reason.",
+		},
+		[]string{"reason"},
+	)
+
+	globalnetAllocations = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "globalnet_allocations_total",
+			Help:      "The number of global CIDR allocations for managed clusters, by result.",
+		},
+		[]string{"result"},
+	)
+
+	reconcileDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: hubSubsystem,
+			Name:      "reconcile_duration_seconds",
+			Help:      "The duration of the reconciliations, by controller and result.",
+			Buckets:   metrics.DefBuckets,
+		},
+		[]string{"controller", "result"},
+	)
+)
+
+func init() {
+	legacyregistry.MustRegister(clusters, brokerNamespaces, manifestWorkApplies, manifestWorkDeletionTimeouts, brokerInfoFailures,
+		globalnetAllocations, reconcileDuration)
+}
+
+// SetClusters records the number of managed clusters with the submariner addon in the given ManagedClusterSet.
+func SetClusters(clusterSet string, count int) {
+	clusters.WithLabelValues(clusterSet).Set(float64(count))
+}
+
+// DeleteClusters forgets the number of managed clusters of the given ManagedClusterSet, once it's deleted.
+func DeleteClusters(clusterSet string) {
+	clusters.Delete(map[string]string{"cluster_set": clusterSet})
+}
+
+// SetBrokerNamespaces records the number of broker namespaces.
+func SetBrokerNamespaces(count int) {
+	brokerNamespaces.Set(float64(count))
+}
+
+// RecordManifestWorkApply records the result of applying the given ManifestWork.
+func RecordManifestWorkApply(name string, err error) {
+	manifestWorkApplies.WithLabelValues(name, resultOf(err)).Inc()
+}
+
+// RecordManifestWorkDeletionTimeout records a clean up which didn't wait any longer for a ManifestWork to be deleted.
+func RecordManifestWorkDeletionTimeout() {
+	manifestWorkDeletionTimeouts.Inc()
+}
+
+// RecordBrokerInfoFailure records a failure to build the broker information of a managed cluster.
+func RecordBrokerInfoFailure(reason string) {
+	brokerInfoFailures.WithLabelValues(reason).Inc()
+}
+
+// RecordGlobalnetAllocation records the result of allocating a global CIDR to a managed cluster.
+func RecordGlobalnetAllocation(err error) {
+	globalnetAllocations.WithLabelValues(resultOf(err)).Inc()
+}
+
+// InstrumentSync returns a sync function recording the duration of each reconciliation of the given controller.
+func InstrumentSync(controller string, sync factory.SyncFunc) factory.SyncFunc {
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		start := time.Now()
+		err := sync(ctx, syncCtx)
+
+		reconcileDuration.WithLabelValues(controller, resultOf(err)).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+func resultOf(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+
+	return resultSuccess
+}
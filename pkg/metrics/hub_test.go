@@ -0,0 +1,112 @@
+package metrics_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+const hubPrefix = "open_cluster_management_submariner_addon_hub_"
+
+var _ = Describe("Hub metrics", func() {
+	When("the clusters of a ManagedClusterSet are recorded", func() {
+		It("should set the gauge for the ManagedClusterSet", func() {
+			metrics.SetClusters("east", 3)
+			Expect(sampleValue(hubPrefix+"clusters", map[string]string{"cluster_set": "east"})).To(Equal(float64(3)))
+
+			metrics.SetClusters("east", 2)
+			Expect(sampleValue(hubPrefix+"clusters", map[string]string{"cluster_set": "east"})).To(Equal(float64(2)))
+		})
+
+		Context("and the ManagedClusterSet is subsequently deleted", func() {
+			It("should remove the gauge for the ManagedClusterSet", func() {
+				metrics.SetClusters("west", 1)
+				metrics.DeleteClusters("west")
+				Expect(hasSample(hubPrefix+"clusters", map[string]string{"cluster_set": "west"})).To(BeFalse())
+			})
+		})
+	})
+
+	When("ManifestWork applies are recorded", func() {
+		It("should count them by result", func() {
+			succeeded := map[string]string{"manifestwork": "test-work", "result": "success"}
+			failed := map[string]string{"manifestwork": "test-work", "result": "failure"}
+
+			metrics.RecordManifestWorkApply("test-work", nil)
+			metrics.RecordManifestWorkApply("test-work", nil)
+			metrics.RecordManifestWorkApply("test-work", errors.New("fake error"))
+
+			Expect(sampleValue(hubPrefix+"manifestwork_applies_total", succeeded)).To(Equal(float64(2)))
+			Expect(sampleValue(hubPrefix+"manifestwork_applies_total", failed)).To(Equal(float64(1)))
+		})
+	})
+
+	When("broker information failures are recorded", func() {
+		It("should count them by reason", func() {
+			metrics.RecordBrokerInfoFailure("TestReason")
+			Expect(sampleValue(hubPrefix+"brokerinfo_failures_total", map[string]string{"reason": "TestReason"})).To(Equal(float64(1)))
+		})
+	})
+
+	When("a sync function is instrumented", func() {
+		It("should record the duration of each reconciliation and return its error", func() {
+			syncErr := errors.New("fake error")
+			sync := metrics.InstrumentSync("TestController", func(_ context.Context, _ factory.SyncContext) error {
+				return syncErr
+			})
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test"))
+			Expect(sync(context.TODO(), syncCtx)).To(Equal(syncErr))
+
+			hist, err := testutil.GetHistogramVecFromGatherer(legacyregistry.DefaultGatherer, hubPrefix+"reconcile_duration_seconds",
+				map[string]string{"controller": "TestController", "result": "failure"})
+			Expect(err).To(Succeed())
+			Expect(hist.GetAggregatedSampleCount()).To(Equal(uint64(1)))
+		})
+	})
+})
+
+func findSample(name string, labels map[string]string) (float64, bool) {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	Expect(err).To(Succeed())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if !testutil.LabelsMatch(metric, labels) {
+				continue
+			}
+
+			if metric.GetGauge() != nil {
+				return metric.GetGauge().GetValue(), true
+			}
+
+			return metric.GetCounter().GetValue(), true
+		}
+	}
+
+	return 0, false
+}
+
+func sampleValue(name string, labels map[string]string) float64 {
+	value, found := findSample(name, labels)
+	Expect(found).To(BeTrue(), "metric %q with labels %v not found", name, labels)
+
+	return value
+}
+
+func hasSample(name string, labels map[string]string) bool {
+	_, found := findSample(name, labels)
+
+	return found
+}
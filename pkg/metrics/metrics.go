@@ -0,0 +1,37 @@
+// Package metrics defines the Prometheus metrics exported by the submariner-addon. They are registered in the legacy registry,
+// served on the /metrics endpoint of the controller command.
+package metrics
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	namespace = "open_cluster_management_submariner_addon"
+
+	statusSubsystem = "status"
+)
+
+// statusUpdateConflicts is recorded by both the hub controllers and the agent.
+var statusUpdateConflicts = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Namespace: namespace,
+		Subsystem: statusSubsystem,
+		Name:      "update_conflicts_total",
+		Help:      "The number of status updates which still failed with a conflict once retried, by resource.",
+	},
+	[]string{"resource"},
+)
+
+func init() {
+	legacyregistry.MustRegister(statusUpdateConflicts)
+}
+
+// RecordStatusUpdateConflict records the failure of a status update of the given resource, if it failed with a conflict.
+func RecordStatusUpdateConflict(resource string, err error) {
+	if apierrors.IsConflict(err) {
+		statusUpdateConflicts.WithLabelValues(resource).Inc()
+	}
+}
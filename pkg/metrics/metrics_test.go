@@ -0,0 +1,23 @@
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Status metrics", func() {
+	When("status updates which failed are recorded", func() {
+		It("should only count the conflicts by resource", func() {
+			metrics.RecordStatusUpdateConflict("TestResource", apierrors.NewConflict(schema.GroupResource{}, "test", errors.New("fake")))
+			metrics.RecordStatusUpdateConflict("TestResource", errors.New("fake error"))
+			metrics.RecordStatusUpdateConflict("TestResource", nil)
+
+			Expect(sampleValue("open_cluster_management_submariner_addon_status_update_conflicts_total",
+				map[string]string{"resource": "TestResource"})).To(Equal(float64(1)))
+		})
+	})
+})
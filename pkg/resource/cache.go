@@ -0,0 +1,32 @@
+package resource
+
+import (
+	"sync"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// syncResourceCache guards a resource cache, which isn't safe for concurrent use, for the controllers running several workers.
+type syncResourceCache struct {
+	sync.Mutex
+	cache resourceapply.ResourceCache
+}
+
+func NewSyncResourceCache() resourceapply.ResourceCache {
+	return &syncResourceCache{cache: resourceapply.NewResourceCache()}
+}
+
+func (c *syncResourceCache) UpdateCachedResourceMetadata(required, actual runtime.Object) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.cache.UpdateCachedResourceMetadata(required, actual)
+}
+
+func (c *syncResourceCache) SafeToSkipApply(required, existing runtime.Object) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.cache.SafeToSkipApply(required, existing)
+}
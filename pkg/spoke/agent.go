@@ -15,11 +15,15 @@ import (
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	"github.com/stolostron/submariner-addon/pkg/spoke/submarineragent"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
@@ -29,7 +33,13 @@ import (
 	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 )
 
-const defaultInstallationNamespace = "submariner-operator"
+const (
+	defaultInstallationNamespace = "submariner-operator"
+	defaultCloudPrepareTimeout   = 15 * time.Minute
+	defaultCloudCleanUpTimeout   = 15 * time.Minute
+	defaultCloudCheckTimeout     = 2 * time.Minute
+	defaultReadinessAddress      = ":8000"
+)
 
 var (
 	submarinerGVR = schema.GroupVersionResource{
@@ -49,16 +59,35 @@ type AgentOptions struct {
 	HubKubeconfigFile     string
 	HubRestConfig         *rest.Config
 	ClusterName           string
+	CloudTimeouts         submarineragent.CloudTimeouts
+	ReadinessAddress      string
+	health                *agentHealth
 }
 
 func NewAgentOptions() *AgentOptions {
-	return &AgentOptions{}
+	return &AgentOptions{
+		CloudTimeouts: submarineragent.CloudTimeouts{
+			Prepare: defaultCloudPrepareTimeout,
+			CleanUp: defaultCloudCleanUpTimeout,
+			Check:   defaultCloudCheckTimeout,
+		},
+		ReadinessAddress: defaultReadinessAddress,
+		health:           newAgentHealth(),
+	}
 }
 
 func (o *AgentOptions) AddFlags(cmd *cobra.Command) {
 	flags := cmd.Flags()
 	flags.StringVar(&o.HubKubeconfigFile, "hub-kubeconfig", o.HubKubeconfigFile, "Location of kubeconfig file to connect to hub cluster.")
 	flags.StringVar(&o.ClusterName, "cluster-name", o.ClusterName, "Name of managed cluster.")
+	flags.DurationVar(&o.CloudTimeouts.Prepare, "cloud-prepare-timeout", o.CloudTimeouts.Prepare,
+		"Timeout of the preparation of the cloud environment, 0 to disable it.")
+	flags.DurationVar(&o.CloudTimeouts.CleanUp, "cloud-cleanup-timeout", o.CloudTimeouts.CleanUp,
+		"Timeout of the clean up of the cloud environment, 0 to disable it.")
+	flags.DurationVar(&o.CloudTimeouts.Check, "cloud-check-timeout", o.CloudTimeouts.Check,
+		"Timeout of the cloud permissions check, planning and drift detection, 0 to disable it.")
+	flags.StringVar(&o.ReadinessAddress, "readiness-bind-address", o.ReadinessAddress,
+		"Address serving the readiness checks on /readyz, empty to disable it.")
 }
 
 func (o *AgentOptions) Complete() {
@@ -74,12 +103,40 @@ func (o *AgentOptions) Validate() error {
 		return errors.New("cluster name is empty")
 	}
 
+	if o.CloudTimeouts.Prepare < 0 || o.CloudTimeouts.CleanUp < 0 || o.CloudTimeouts.Check < 0 {
+		return errors.New("the cloud timeouts can't be negative")
+	}
+
 	return nil
 }
 
+// HealthChecks returns the checks served by the health endpoint of the agent, used by its liveness probe: the informer caches
+// have synced.
+func (o *AgentOptions) HealthChecks() []healthz.HealthChecker {
+	if o.health == nil {
+		o.health = newAgentHealth()
+	}
+
+	return o.health.livenessChecks()
+}
+
+// ReadinessChecks returns the checks served by the readiness endpoint of the agent: the informer caches have synced and the
+// hub cluster has been reached recently.
+func (o *AgentOptions) ReadinessChecks() []healthz.HealthChecker {
+	if o.health == nil {
+		o.health = newAgentHealth()
+	}
+
+	return o.health.readinessChecks()
+}
+
 func (o *AgentOptions) RunAgent(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	o.Complete()
 
+	if o.health == nil {
+		o.health = newAgentHealth()
+	}
+
 	if err := o.Validate(); err != nil {
 		return err
 	}
@@ -146,6 +203,21 @@ func (o *AgentOptions) RunAgent(ctx context.Context, controllerContext *controll
 		nil)
 	submarinerInformer := dynamicInformers.ForResource(submarinerGVR)
 
+	// Only the credentials secret referenced by the SubmarinerConfig is cached, not all the secrets of the cluster namespace.
+	secretInformer := func(name string, stopCh <-chan struct{}) corev1informers.SecretInformer {
+		hubKubeInformers := informers.NewSharedInformerFactoryWithOptions(hubClient, 10*time.Minute,
+			informers.WithNamespace(o.ClusterName), informers.WithTransform(trim),
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			}))
+
+		informer := hubKubeInformers.Core().V1().Secrets()
+		informer.Informer()
+		hubKubeInformers.Start(stopCh)
+
+		return informer
+	}
+
 	submarinerConfigController := submarineragent.NewSubmarinerConfigController(&submarineragent.SubmarinerConfigControllerInput{
 		ClusterName:          o.ClusterName,
 		Namespace:            o.InstallationNamespace,
@@ -156,8 +228,10 @@ func (o *AgentOptions) RunAgent(ctx context.Context, controllerContext *controll
 		NodeInformer:         spokeKubeInformers.Core().V1().Nodes(),
 		AddOnInformer:        addOnInformers.Addon().V1alpha1().ManagedClusterAddOns(),
 		ConfigInformer:       configInformers.Submarineraddon().V1alpha1().SubmarinerConfigs(),
+		SecretInformer:       secretInformer,
 		SubmarinerInformer:   submarinerInformer,
 		CloudProviderFactory: cloud.NewProviderFactory(restMapper, spokeKubeClient, spokeDynamicClient, hubClient),
+		CloudTimeouts:        o.CloudTimeouts,
 		Recorder:             controllerContext.EventRecorder,
 	})
 
@@ -168,17 +242,48 @@ func (o *AgentOptions) RunAgent(ctx context.Context, controllerContext *controll
 		controllerContext.EventRecorder,
 	)
 
+	// Subscriptions are only available if Operator Lifecycle Manager is installed on the managed cluster; otherwise the
+	// submariner-operator is installed from manifests. OLM may be installed later, it's discovered again until then.
+	subscriptionInformer := func() informers.GenericInformer {
+		if !isServed(spokeKubeClient.Discovery(), subscriptionGVR) {
+			return nil
+		}
+
+		informer := dynamicInformers.ForResource(subscriptionGVR)
+		dynamicInformers.Start(ctx.Done())
+
+		return informer
+	}
+
 	deploymentStatusController := submarineragent.NewDeploymentStatusController(o.ClusterName, o.InstallationNamespace,
 		addOnHubKubeClient, spokeKubeInformers.Apps().V1().DaemonSets(), spokeKubeInformers.Apps().V1().Deployments(),
-		dynamicInformers.ForResource(subscriptionGVR), submarinerInformer, controllerContext.EventRecorder)
+		subscriptionInformer, submarinerInformer, controllerContext.EventRecorder)
 
 	connectionsStatusController := submarineragent.NewConnectionsStatusController(o.ClusterName, addOnHubKubeClient,
-		dynamicInformers.ForResource(submarinerGVR), controllerContext.EventRecorder)
+		configHubKubeClient, configInformers.Submarineraddon().V1alpha1().SubmarinerConfigs(), dynamicInformers.ForResource(submarinerGVR),
+		controllerContext.EventRecorder)
+
+	addOnInformers.Start(ctx.Done())
+	configInformers.Start(ctx.Done())
+	spokeKubeInformers.Start(ctx.Done())
+	dynamicInformers.Start(ctx.Done())
+
+	go func() {
+		addOnInformers.WaitForCacheSync(ctx.Done())
+		configInformers.WaitForCacheSync(ctx.Done())
+		spokeKubeInformers.WaitForCacheSync(ctx.Done())
+		dynamicInformers.WaitForCacheSync(ctx.Done())
+
+		if ctx.Err() == nil {
+			o.health.setInformersSynced()
+		}
+	}()
 
-	go addOnInformers.Start(ctx.Done())
-	go configInformers.Start(ctx.Done())
-	go spokeKubeInformers.Start(ctx.Done())
-	go dynamicInformers.Start(ctx.Done())
+	go o.health.watchHubConnection(ctx, hubClient.Discovery())
+
+	if o.ReadinessAddress != "" {
+		go o.health.serveReadiness(ctx, o.ReadinessAddress)
+	}
 
 	go submarinerConfigController.Run(ctx, 1)
 	go gatewaysStatusController.Run(ctx, 1)
@@ -198,6 +303,22 @@ func (o *AgentOptions) RunAgent(ctx context.Context, controllerContext *controll
 	return nil
 }
 
+// isServed returns whether the given resource is served by the API server.
+func isServed(client discovery.DiscoveryInterface, gvr schema.GroupVersionResource) bool {
+	resources, err := client.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		return false
+	}
+
+	for i := range resources.APIResources {
+		if resources.APIResources[i].Name == gvr.Resource {
+			return true
+		}
+	}
+
+	return false
+}
+
 func buildRestMapper(restConfig *rest.Config) (meta.RESTMapper, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
@@ -0,0 +1,135 @@
+package spoke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/submariner-io/admiral/pkg/log"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/discovery"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("AgentHealth")}
+
+const (
+	hubContactInterval   = 30 * time.Second
+	hubContactTimeout    = 3 * time.Minute
+	readinessReadTimeout = 10 * time.Second
+)
+
+// agentHealth tracks the state checked by the liveness and readiness probes of the agent: whether its informers have synced,
+// and when it last reached the hub cluster.
+type agentHealth struct {
+	sync.Mutex
+	informersSynced bool
+	started         time.Time
+	lastHubContact  time.Time
+	now             func() time.Time
+}
+
+func newAgentHealth() *agentHealth {
+	return &agentHealth{
+		started: time.Now(),
+		now:     time.Now,
+	}
+}
+
+func (h *agentHealth) setInformersSynced() {
+	h.Lock()
+	defer h.Unlock()
+
+	h.informersSynced = true
+}
+
+func (h *agentHealth) setHubContacted() {
+	h.Lock()
+	defer h.Unlock()
+
+	h.lastHubContact = h.now()
+}
+
+func (h *agentHealth) checkInformersSynced(_ *http.Request) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if !h.informersSynced {
+		return errors.New("the informer caches have not synced yet")
+	}
+
+	return nil
+}
+
+func (h *agentHealth) checkHubConnection(_ *http.Request) error {
+	h.Lock()
+	defer h.Unlock()
+
+	// Give the agent some time to reach the hub cluster after it started
+	since := h.started
+	if h.lastHubContact.After(since) {
+		since = h.lastHubContact
+	}
+
+	if elapsed := h.now().Sub(since); elapsed > hubContactTimeout {
+		if h.lastHubContact.IsZero() {
+			return fmt.Errorf("the hub cluster has not been reached since the agent started %v ago", elapsed.Round(time.Second))
+		}
+
+		return fmt.Errorf("the hub cluster was last reached %v ago", elapsed.Round(time.Second))
+	}
+
+	return nil
+}
+
+// livenessChecks returns the checks of the liveness probe. The hub connection isn't checked, restarting the agent doesn't help
+// reaching the hub cluster.
+func (h *agentHealth) livenessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{
+		healthz.NamedCheck("informer-sync", h.checkInformersSynced),
+	}
+}
+
+func (h *agentHealth) readinessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{
+		healthz.NamedCheck("informer-sync", h.checkInformersSynced),
+		healthz.NamedCheck("hub-connection", h.checkHubConnection),
+	}
+}
+
+// serveReadiness serves the readiness checks on the /readyz endpoint of the given address, until the given context is done.
+func (h *agentHealth) serveReadiness(ctx context.Context, address string) {
+	mux := http.NewServeMux()
+	healthz.InstallReadyzHandler(mux, h.readinessChecks()...)
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: readinessReadTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf(err, "Unable to serve the readiness checks on %q", address)
+	}
+}
+
+// watchHubConnection periodically checks that the hub cluster can be reached, until the given context is done.
+func (h *agentHealth) watchHubConnection(ctx context.Context, hubDiscoveryClient discovery.ServerVersionInterface) {
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		if _, err := hubDiscoveryClient.ServerVersion(); err != nil {
+			logger.Warningf("Unable to reach the hub cluster: %v", err)
+			return
+		}
+
+		h.setHubContacted()
+	}, hubContactInterval)
+}
@@ -0,0 +1,97 @@
+package submarineragent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const reasonTimeout = "Timeout"
+
+// CloudTimeouts bounds the duration of the cloud provider operations, so that a hung cloud API call doesn't block the controller
+// worker. A zero timeout disables the bound.
+type CloudTimeouts struct {
+	// Prepare bounds the preparation of the submariner cluster environment.
+	Prepare time.Duration
+	// CleanUp bounds the clean up of the submariner cluster environment.
+	CleanUp time.Duration
+	// Check bounds the operations which don't mutate the cloud: the permissions check, the planning and the drift detection.
+	Check time.Duration
+}
+
+// runCloudOperation runs the given cloud provider operation with a context bounded by the given timeout, and returns once the
+// operation completes or times out.
+func (c *submarinerConfigController) runCloudOperation(ctx context.Context, namespace, operation string, timeout time.Duration,
+	run func(ctx context.Context) error,
+) error {
+	_, err := callCloudOperation(ctx, c, namespace, operation, timeout, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, run(ctx)
+	})
+
+	return err
+}
+
+// callCloudOperation runs the given cloud provider operation with a context bounded by the given timeout, and returns its result
+// once it completes or times out. The cloud-prepare calls don't accept a context, so an operation which timed out may keep
+// running in the background: no other operation is started for the same cluster until it completes, the operations are
+// reported as timed out meanwhile.
+func callCloudOperation[T any](ctx context.Context, c *submarinerConfigController, namespace, operation string, timeout time.Duration,
+	call func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+
+	c.cloudOperationsMutex.Lock()
+
+	if c.runningCloudOperations.Has(namespace) {
+		c.cloudOperationsMutex.Unlock()
+		return zero, fmt.Errorf("unable to start the %s, a previous cloud operation which timed out is still running: %w", operation,
+			context.DeadlineExceeded)
+	}
+
+	c.runningCloudOperations.Insert(namespace)
+	c.cloudOperationsMutex.Unlock()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		value, err := call(ctx)
+
+		c.cloudOperationsMutex.Lock()
+		c.runningCloudOperations.Delete(namespace)
+		c.cloudOperationsMutex.Unlock()
+
+		done <- result{value: value, err: err}
+	}()
+
+	var r result
+
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		r.err = ctx.Err()
+	}
+
+	if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return zero, fmt.Errorf("the %s timed out after %v: %w", operation, timeout, context.DeadlineExceeded)
+	}
+
+	return r.value, r.err
+}
+
+// isTimeout returns whether the given error was returned by a cloud operation which timed out.
+func isTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -2,11 +2,16 @@ package submarineragent
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/openshift/library-go/pkg/controller/factory"
@@ -20,7 +25,9 @@ import (
 	configinformer "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions/submarinerconfig/v1alpha1"
 	configlister "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/listers/submarinerconfig/v1alpha1"
 	"github.com/stolostron/submariner-addon/pkg/cloud"
+	"github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/util"
@@ -46,22 +53,26 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-var (
-	// TODO expose this as a flag to allow user to specify their zone label.
-	defaultZoneLabel = ""
-	networksGVR      = schema.GroupVersionResource{
-		Group:    "config.openshift.io",
-		Version:  "v1",
-		Resource: "networks",
-	}
-)
+var networksGVR = schema.GroupVersionResource{
+	Group:    "config.openshift.io",
+	Version:  "v1",
+	Resource: "networks",
+}
 
-const submarinerGatewayCondition = "SubmarinerGatewaysLabeled"
+const submarinerGatewayCondition = configv1alpha1.SubmarinerConfigConditionGatewaysLabeled
+
+// MinDriftDetectionInterval is the shortest interval at which the prepared cluster environments are verified, so that short
+// intervals don't flood the cloud APIs.
+var MinDriftDetectionInterval = time.Minute
 
 const (
 	submarinerUDPPortLabel = "gateway.submariner.io/udp-port"
 	workerNodeLabel        = "node-role.kubernetes.io/worker"
+	controlPlaneNodeLabel  = "node-role.kubernetes.io/control-plane"
+	masterNodeLabel        = "node-role.kubernetes.io/master"
 	networksConfigName     = "cluster"
+	// secretCacheSyncRetry is the interval after which a config is synced again while its credentials secret isn't cached.
+	secretCacheSyncRetry = time.Second
 )
 
 type nodeLabelSelector struct {
@@ -79,13 +90,38 @@ type submarinerConfigController struct {
 	nodeLister           corev1lister.NodeLister
 	addOnLister          addonlisterv1alpha1.ManagedClusterAddOnLister
 	configLister         configlister.SubmarinerConfigLister
+	secretInformer       SecretInformerFunc
+	secretLister         corev1lister.SecretLister
 	submarinerLister     cache.GenericLister
 	clusterName          string
 	namespace            string
 	cloudProviderFactory cloud.ProviderFactory
 	onSyncDefer          func()
-	knownConfigs         map[string]*configv1alpha1.SubmarinerConfig
-	logger               log.Logger
+	knownConfigs         map[string]knownConfig
+	preparedGateways     map[string][]string
+	labelingConfigs      sets.Set[string]
+	unavailableNodes     map[string]time.Time
+	lastVerified         map[string]time.Time
+	cloudTimeouts        CloudTimeouts
+	// cloudOperationsMutex guards runningCloudOperations, which is updated once the cloud operations which timed out complete.
+	cloudOperationsMutex   sync.Mutex
+	runningCloudOperations sets.Set[string]
+	// credentialsSecretName is the name of the secret watched by the informer stopped by stopSecretInformer.
+	credentialsSecretName string
+	stopSecretInformer    context.CancelFunc
+	secretSynced          cache.InformerSynced
+	logger                log.Logger
+}
+
+// SecretInformerFunc returns an informer for the Secret with the given name in the cluster namespace on the hub, started with
+// the given stop channel.
+type SecretInformerFunc func(name string, stopCh <-chan struct{}) corev1informers.SecretInformer
+
+// knownConfig is the last submariner config which was successfully synced, with the hash of the content of its credentials
+// secret at the time.
+type knownConfig struct {
+	config          *configv1alpha1.SubmarinerConfig
+	credentialsHash string
 }
 
 type SubmarinerConfigControllerInput struct {
@@ -98,8 +134,10 @@ type SubmarinerConfigControllerInput struct {
 	NodeInformer         corev1informers.NodeInformer
 	AddOnInformer        addoninformerv1alpha1.ManagedClusterAddOnInformer
 	ConfigInformer       configinformer.SubmarinerConfigInformer
+	SecretInformer       SecretInformerFunc
 	SubmarinerInformer   informers.GenericInformer
 	CloudProviderFactory cloud.ProviderFactory
+	CloudTimeouts        CloudTimeouts
 	Recorder             events.Recorder
 	// This is a hook for unit tests to invoke a defer (specifically GinkgoRecover) when the sync function is called.
 	OnSyncDefer func()
@@ -109,20 +147,27 @@ type SubmarinerConfigControllerInput struct {
 func NewSubmarinerConfigController(input *SubmarinerConfigControllerInput) factory.Controller {
 	name := "SubmarinerConfigController"
 	c := &submarinerConfigController{
-		kubeClient:           input.KubeClient,
-		configClient:         input.ConfigClient,
-		addOnClient:          input.AddOnClient,
-		dynamicClient:        input.DynamicClient,
-		nodeLister:           input.NodeInformer.Lister(),
-		addOnLister:          input.AddOnInformer.Lister(),
-		configLister:         input.ConfigInformer.Lister(),
-		submarinerLister:     input.SubmarinerInformer.Lister(),
-		clusterName:          input.ClusterName,
-		namespace:            input.Namespace,
-		cloudProviderFactory: input.CloudProviderFactory,
-		onSyncDefer:          input.OnSyncDefer,
-		knownConfigs:         make(map[string]*configv1alpha1.SubmarinerConfig),
-		logger:               log.Logger{Logger: logf.Log.WithName(name)},
+		kubeClient:             input.KubeClient,
+		configClient:           input.ConfigClient,
+		addOnClient:            input.AddOnClient,
+		dynamicClient:          input.DynamicClient,
+		nodeLister:             input.NodeInformer.Lister(),
+		addOnLister:            input.AddOnInformer.Lister(),
+		configLister:           input.ConfigInformer.Lister(),
+		secretInformer:         input.SecretInformer,
+		submarinerLister:       input.SubmarinerInformer.Lister(),
+		clusterName:            input.ClusterName,
+		namespace:              input.Namespace,
+		cloudProviderFactory:   input.CloudProviderFactory,
+		onSyncDefer:            input.OnSyncDefer,
+		knownConfigs:           make(map[string]knownConfig),
+		preparedGateways:       make(map[string][]string),
+		labelingConfigs:        sets.New[string](),
+		unavailableNodes:       make(map[string]time.Time),
+		lastVerified:           make(map[string]time.Time),
+		cloudTimeouts:          input.CloudTimeouts,
+		runningCloudOperations: sets.New[string](),
+		logger:                 log.Logger{Logger: logf.Log.WithName(name)},
 	}
 
 	return factory.New().
@@ -138,12 +183,17 @@ func NewSubmarinerConfigController(input *SubmarinerConfigControllerInput) facto
 		}, input.ConfigInformer.Informer()).
 		WithFilteredEventsInformers(func(obj interface{}) bool {
 			metaObj := obj.(metav1.Object)
-			// only handle the changes of worker nodes
-			if _, has := metaObj.GetLabels()[workerNodeLabel]; has {
+			// only handle the changes of worker nodes, managed Kubernetes services don't label their nodes with the worker role
+			// but don't expose their control plane nodes either
+			nodeLabels := metaObj.GetLabels()
+			if _, has := nodeLabels[workerNodeLabel]; has {
 				return true
 			}
 
-			return false
+			_, isControlPlane := nodeLabels[controlPlaneNodeLabel]
+			_, isMaster := nodeLabels[masterNodeLabel]
+
+			return !isControlPlane && !isMaster
 		}, input.NodeInformer.Informer()).
 		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
 			key, _ := cache.MetaNamespaceKeyFunc(obj)
@@ -180,6 +230,8 @@ func (c *submarinerConfigController) sync(ctx context.Context, syncCtx factory.S
 		return err
 	}
 
+	c.watchCredentialsSecret(ctx, syncCtx, config)
+
 	if config.Status.ManagedClusterInfo.Platform == "" {
 		// no managed cluster info, do nothing
 		return nil
@@ -213,6 +265,10 @@ func (c *submarinerConfigController) sync(ctx context.Context, syncCtx factory.S
 		err = c.cleanupClusterEnvironment(ctx, config, recorder)
 		if err != nil {
 			condition = failedCondition(err.Error())
+
+			if isTimeout(err) {
+				condition.Reason = reasonTimeout
+			}
 		}
 
 		updateErr := c.updateSubmarinerConfigStatus(ctx, recorder, config, &condition)
@@ -224,14 +280,35 @@ func (c *submarinerConfigController) sync(ctx context.Context, syncCtx factory.S
 		return updateErr
 	}
 
-	return c.syncConfig(ctx, recorder, config)
+	if c.secretSynced != nil && !c.secretSynced() {
+		// The credentials secret is hashed once it's cached, its informer enqueues the config when it's added.
+		c.logger.V(log.DEBUG).Infof("Waiting for the credentials secret of submariner config %q to be cached",
+			config.Namespace+"/"+config.Name)
+		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), secretCacheSyncRetry)
+
+		return nil
+	}
+
+	return c.syncConfig(ctx, syncCtx, config)
 }
 
-func (c *submarinerConfigController) syncConfig(ctx context.Context, recorder events.Recorder,
+func (c *submarinerConfigController) syncConfig(ctx context.Context, syncCtx factory.SyncContext,
 	config *configv1alpha1.SubmarinerConfig,
 ) error {
-	if c.skipSyncingUnchangedConfig(config) {
+	recorder := syncCtx.Recorder()
+
+	if c.skipSyncingUnchangedConfig(ctx, config) {
+		if c.isDriftDetectionDue(config) {
+			return c.detectDrift(ctx, syncCtx, config)
+		}
+
+		if c.hasUnavailableGateways(config) {
+			// The cluster environment is still prepared, only the gateways may need to be failed over.
+			return c.syncGateways(ctx, syncCtx, config)
+		}
+
 		c.logger.V(log.DEBUG).Infof("Skip syncing submariner config %q as it didn't change", config.Namespace+"/"+config.Name)
+
 		return nil
 	}
 
@@ -241,30 +318,219 @@ func (c *submarinerConfigController) syncConfig(ctx context.Context, recorder ev
 		return err
 	}
 
-	return c.prepareForSubmariner(ctx, config, recorder)
+	return c.prepareForSubmariner(ctx, config, syncCtx)
+}
+
+// skipSyncingUnchangedConfig if last submariner config is known and is equal to the given config, the content of its
+// credentials secret didn't change and, if the cluster environment was prepared for existing gateway nodes, the labeled
+// gateways didn't change, as the cluster environment must be prepared for replacement gateways.
+func (c *submarinerConfigController) skipSyncingUnchangedConfig(ctx context.Context, config *configv1alpha1.SubmarinerConfig) bool {
+	last, known := c.knownConfigs[config.Namespace]
+	if !known || !reflect.DeepEqual(last.config.Spec, config.Spec) || last.credentialsHash != c.credentialsHash(config) {
+		return false
+	}
+
+	preparedGateways, prepared := c.preparedGateways[config.Namespace]
+
+	return !prepared || slices.Equal(preparedGateways, c.labeledGatewayNames(ctx, false))
+}
+
+// labeledGatewayNames returns the sorted names of the nodes labeled as gateways. The nodes are listed from the cache unless live
+// is set, the cache may not reflect the labels just updated by the controller yet.
+func (c *submarinerConfigController) labeledGatewayNames(ctx context.Context, live bool) []string {
+	names := []string{}
+
+	if live {
+		nodes, err := c.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: submarinerGatewayLabel})
+		if err != nil {
+			return nil
+		}
+
+		for i := range nodes.Items {
+			names = append(names, nodes.Items[i].Name)
+		}
+	} else {
+		nodes, err := c.getLabeledNodes(nodeLabelSelector{submarinerGatewayLabel, selection.Exists})
+		if err != nil {
+			return nil
+		}
+
+		for _, node := range nodes {
+			names = append(names, node.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// watchCredentialsSecret watches the credentials secret referenced by the given config, rather than all the secrets of the
+// cluster namespace. The informer is replaced when the config references another secret.
+func (c *submarinerConfigController) watchCredentialsSecret(ctx context.Context, syncCtx factory.SyncContext,
+	config *configv1alpha1.SubmarinerConfig,
+) {
+	name := ""
+	if config.Spec.CredentialsSecret != nil {
+		name = config.Spec.CredentialsSecret.Name
+	}
+
+	if name == c.credentialsSecretName {
+		return
+	}
+
+	if c.stopSecretInformer != nil {
+		c.stopSecretInformer()
+	}
+
+	c.credentialsSecretName = name
+	c.secretLister = nil
+	c.secretSynced = nil
+	c.stopSecretInformer = nil
+
+	if name == "" {
+		return
+	}
+
+	informerCtx, stop := context.WithCancel(ctx)
+	informer := c.secretInformer(name, informerCtx.Done())
+
+	enqueue := func(interface{}) {
+		syncCtx.Queue().Add(factory.DefaultQueueKey)
+	}
+
+	_, _ = informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+		DeleteFunc: enqueue,
+	})
+
+	c.stopSecretInformer = stop
+	c.secretLister = informer.Lister()
+	c.secretSynced = informer.Informer().HasSynced
 }
 
-// skipSyncingUnchangedConfig if last submariner config is known and is equal to the given config.
-func (c *submarinerConfigController) skipSyncingUnchangedConfig(config *configv1alpha1.SubmarinerConfig) bool {
-	lastConfig, known := c.knownConfigs[config.Namespace]
-	return known && reflect.DeepEqual(lastConfig.Spec, config.Spec)
+// credentialsHash returns the hash of the content of the credentials secret referenced by the given config, or an empty hash if
+// the config doesn't reference a credentials secret or it's not found.
+func (c *submarinerConfigController) credentialsHash(config *configv1alpha1.SubmarinerConfig) string {
+	if config.Spec.CredentialsSecret == nil || c.secretLister == nil {
+		return ""
+	}
+
+	secret, err := c.secretLister.Secrets(config.Namespace).Get(config.Spec.CredentialsSecret.Name)
+	if err != nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	hash := sha256.New()
+
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write(secret.Data[key])
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hasUnavailableGateways returns whether the controller labels the gateways of the given config and one of them is
+// unavailable, in which case the gateways must be synced again to fail the gateway over even if the config didn't change.
+func (c *submarinerConfigController) hasUnavailableGateways(config *configv1alpha1.SubmarinerConfig) bool {
+	if !c.labelingConfigs.Has(config.Namespace) {
+		return false
+	}
+
+	gateways, err := c.getLabeledNodes(nodeLabelSelector{submarinerGatewayLabel, selection.Exists})
+	if err != nil {
+		return true
+	}
+
+	for _, gateway := range gateways {
+		if !isNodeAvailable(gateway) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (c *submarinerConfigController) prepareForSubmariner(ctx context.Context, config *configv1alpha1.SubmarinerConfig,
-	recorder events.Recorder,
+	syncCtx factory.SyncContext,
 ) error {
-	cloudProvider, providerFound, preparedErr := c.cloudProviderFactory.Get(config, recorder)
+	recorder := syncCtx.Recorder()
+	stepLog := provider.NewStepLog()
+	cloudProvider, providerFound, preparedErr := c.cloudProviderFactory.Get(ctx, config, recorder, stepLog)
 	errs := []error{}
 
+	// Some providers don't deploy dedicated gateway nodes, the existing nodes must then be labeled as gateways first so the
+	// provider can prepare them.
+	usesExistingNodes := providerFound && cloud.UsesExistingGatewayNodes(config.Status.ManagedClusterInfo.Vendor)
+
+	// In dry run mode, the changes are only planned and published in the status, they are applied once dry run is disabled.
+	// No node is labeled either, the changes are planned for the nodes which would be labeled as gateways.
+	dryRun := providerFound && config.Spec.DryRun
+
+	var (
+		gatewayCondition metav1.Condition
+		gatewayErr       error
+		requeueAfter     time.Duration
+	)
+
+	if (!providerFound || usesExistingNodes) && !dryRun {
+		c.labelingConfigs.Insert(config.Namespace)
+	} else {
+		c.labelingConfigs.Delete(config.Namespace)
+	}
+
+	// Check the permissions of the cloud credentials before anything is mutated.
 	if providerFound && preparedErr == nil {
-		preparedErr = cloudProvider.PrepareSubmarinerClusterEnv()
+		preparedErr = c.checkCloudCredentials(ctx, config, cloudProvider)
+	}
+
+	if usesExistingNodes && !dryRun && preparedErr == nil {
+		gatewayCondition, requeueAfter, gatewayErr = c.ensureGateways(ctx, config, recorder)
+	}
+
+	var (
+		plannedChanges []configv1alpha1.PlannedChange
+		updateFns      []submarinerconfig.UpdateStatusFunc
+	)
+
+	switch {
+	case dryRun && preparedErr == nil:
+		plannedChanges, preparedErr = c.planChanges(ctx, config, cloudProvider)
+	case providerFound && preparedErr == nil:
+		start := time.Now()
+		preparedErr = c.runCloudOperation(ctx, config.Namespace, "preparation", c.cloudTimeouts.Prepare,
+			cloudProvider.PrepareSubmarinerClusterEnv)
+		metrics.RecordCloudPreparation(config.Status.ManagedClusterInfo.Platform, time.Since(start), preparedErr)
+
+		// A preparation which timed out keeps running in the background, its later steps mustn't be published.
+		stepLog.Close()
+
+		updateFns = append(updateFns, submarinerconfig.UpdatePreparationStepsFn(stepLog.Steps()))
+	}
+
+	if usesExistingNodes && !dryRun && preparedErr == nil {
+		c.preparedGateways[config.Namespace] = c.labeledGatewayNames(ctx, true)
+	} else {
+		delete(c.preparedGateways, config.Namespace)
 	}
 
 	condition := metav1.Condition{
-		Type:    configv1alpha1.SubmarinerConfigConditionEnvPrepared,
-		Status:  metav1.ConditionTrue,
-		Reason:  "SubmarinerClusterEnvPrepared",
-		Message: "Submariner cluster environment was prepared",
+		Type:               configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SubmarinerClusterEnvPrepared",
+		Message:            "Submariner cluster environment was prepared",
+		ObservedGeneration: config.Generation,
 	}
 
 	if preparedErr != nil {
@@ -272,17 +538,42 @@ func (c *submarinerConfigController) prepareForSubmariner(ctx context.Context, c
 		condition.Reason = "SubmarinerClusterEnvPreparationFailed"
 		condition.Message = fmt.Sprintf("Failed to prepare submariner cluster environment: %v", preparedErr)
 		errs = append(errs, preparedErr)
+
+		switch {
+		case provider.IsInvalidCredentials(preparedErr):
+			condition.Reason = "InvalidCloudCredentials"
+			condition.Message = fmt.Sprintf("The cloud credentials are invalid or expired: %v", preparedErr)
+		case isTimeout(preparedErr):
+			condition.Reason = reasonTimeout
+		case errors.Is(preparedErr, provider.ErrUnsupported):
+			condition.Reason = "SubmarinerClusterEnvPreparationUnsupported"
+			condition.Message = fmt.Sprintf("The submariner cluster environment can't be prepared: %v", preparedErr)
+		}
+	} else if dryRun {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "SubmarinerClusterEnvPlanned"
+		condition.Message = fmt.Sprintf("%d change(s) to prepare the submariner cluster environment were planned, "+
+			"they will be applied once dryRun is disabled", len(plannedChanges))
 	}
 
+	updateFns = append(updateFns, submarinerconfig.UpdateConditionFn(&condition), submarinerconfig.UpdatePlannedChangesFn(plannedChanges),
+		submarinerconfig.UpdateObservedGenerationFn(config.Generation))
+
 	_, updated, updatedErr := submarinerconfig.UpdateStatus(ctx,
-		c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace), config.Name,
-		submarinerconfig.UpdateConditionFn(&condition))
+		c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace), config.Name, updateFns...)
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", updatedErr)
 
 	if updatedErr != nil {
 		errs = append(errs, updatedErr)
 	}
 
-	if updated {
+	switch {
+	case updated && dryRun:
+		for _, change := range plannedChanges {
+			recorder.Eventf("SubmarinerClusterEnvPlanned", "%s %s %q: %s", change.Action, change.Kind, change.Name,
+				change.Description)
+		}
+	case updated:
 		recorder.Eventf("SubmarinerClusterEnvPrepared",
 			"submariner cluster environment was prepared for managed cluster %s", config.Namespace)
 	}
@@ -291,18 +582,251 @@ func (c *submarinerConfigController) prepareForSubmariner(ctx context.Context, c
 		return operatorhelpers.NewMultiLineAggregate(errs)
 	}
 
-	if providerFound {
-		if updated {
-			c.logger.Infof("Submariner environment was prepared for cluster %q: %#v", config.Namespace, config.Status.ManagedClusterInfo)
-		}
+	if providerFound && updated {
+		c.logger.Infof("Submariner environment was prepared for cluster %q: %#v", config.Namespace, config.Status.ManagedClusterInfo)
+	}
 
+	if providerFound && !dryRun {
+		c.scheduleDriftDetection(syncCtx, config)
+	}
+
+	if dryRun {
+		// No gateway nodes are deployed nor labeled until the planned changes are applied, the changes only need to be
+		// planned again once the config changes.
+		delete(c.lastVerified, config.Namespace)
+		c.knownConfigs[config.Namespace] = knownConfig{config: config, credentialsHash: c.credentialsHash(config)}
+
+		return nil
+	}
+
+	if providerFound && !usesExistingNodes {
 		return c.updateGatewayStatus(ctx, recorder, config)
 	}
 
-	// No provider - ensure the expected count of gateways
-	condition, err := c.ensureGateways(ctx, config)
+	if !providerFound {
+		// No provider - ensure the expected count of gateways
+		gatewayCondition, requeueAfter, gatewayErr = c.ensureGateways(ctx, config, recorder)
+	}
+
+	return c.recordGateways(ctx, syncCtx, config, &gatewayCondition, requeueAfter, gatewayErr)
+}
+
+// planChanges returns the changes the given provider would make to prepare the cluster environment. The providers which
+// prepare existing nodes plan them for the nodes which would be labeled as gateways, as no node is labeled in dry run.
+func (c *submarinerConfigController) planChanges(ctx context.Context, config *configv1alpha1.SubmarinerConfig,
+	cloudProvider cloud.Provider,
+) ([]configv1alpha1.PlannedChange, error) {
+	plan := cloudProvider.Plan
+
+	if planner, ok := cloudProvider.(cloud.GatewayPlanner); ok {
+		gateways, err := c.planGateways(config)
+		if err != nil {
+			return nil, err
+		}
+
+		plan = func(ctx context.Context) ([]configv1alpha1.PlannedChange, error) {
+			return planner.PlanForGateways(ctx, gateways)
+		}
+	}
+
+	return callCloudOperation(ctx, c, config.Namespace, "planning", c.cloudTimeouts.Check, plan)
+}
+
+// syncGateways ensures the desired number of available gateways are labeled, without preparing the cluster environment again.
+func (c *submarinerConfigController) syncGateways(ctx context.Context, syncCtx factory.SyncContext,
+	config *configv1alpha1.SubmarinerConfig,
+) error {
+	condition, requeueAfter, err := c.ensureGateways(ctx, config, syncCtx.Recorder())
+
+	return c.recordGateways(ctx, syncCtx, config, &condition, requeueAfter, err)
+}
+
+// recordGateways records the given gateway condition in the status, and requeues the config to check the unavailable gateways
+// again once their grace period expires.
+func (c *submarinerConfigController) recordGateways(ctx context.Context, syncCtx factory.SyncContext,
+	config *configv1alpha1.SubmarinerConfig, condition *metav1.Condition, requeueAfter time.Duration, gatewayErr error,
+) error {
+	if requeueAfter > 0 {
+		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), requeueAfter)
+	}
+
+	updateErr := c.updateSubmarinerConfigStatus(ctx, syncCtx.Recorder(), config, condition)
 
-	updateErr := c.updateSubmarinerConfigStatus(ctx, recorder, config, &condition)
+	if gatewayErr != nil {
+		return gatewayErr
+	}
+
+	return updateErr
+}
+
+// checkCloudCredentials records whether the cloud credentials have the permissions required to prepare the cluster environment,
+// for the providers which can check them, and returns an error if they don't.
+func (c *submarinerConfigController) checkCloudCredentials(ctx context.Context, config *configv1alpha1.SubmarinerConfig,
+	cloudProvider cloud.Provider,
+) error {
+	checker, ok := cloudProvider.(cloud.PermissionsChecker)
+	if !ok {
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               configv1alpha1.SubmarinerConfigConditionCloudCredentialsValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CloudCredentialsValid",
+		Message:            "The cloud credentials have the required permissions",
+		ObservedGeneration: config.Generation,
+	}
+
+	missing, checkErr := callCloudOperation(ctx, c, config.Namespace, "permissions check", c.cloudTimeouts.Check,
+		checker.CheckPermissions)
+
+	switch {
+	case provider.IsInvalidCredentials(checkErr):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidCloudCredentials"
+		condition.Message = fmt.Sprintf("The cloud credentials are invalid or expired: %v", checkErr)
+	case isTimeout(checkErr):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonTimeout
+		condition.Message = fmt.Sprintf("Failed to check the permissions of the cloud credentials: %v", checkErr)
+	case checkErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PermissionsCheckFailed"
+		condition.Message = fmt.Sprintf("Failed to check the permissions of the cloud credentials: %v", checkErr)
+	case len(missing) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MissingPermissions"
+		condition.Message = fmt.Sprintf("The cloud credentials are missing the permissions: %s", strings.Join(missing, ", "))
+		checkErr = fmt.Errorf("the cloud credentials are missing the permissions: %s", strings.Join(missing, ", "))
+	}
+
+	_, _, err := submarinerconfig.UpdateStatus(ctx, c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace),
+		config.Name, submarinerconfig.UpdateConditionFn(&condition), submarinerconfig.UpdateObservedGenerationFn(config.Generation))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", err)
+
+	if err != nil {
+		return err
+	}
+
+	return checkErr
+}
+
+// driftDetectionInterval returns the interval at which the prepared cluster environment of the given config must be verified, at
+// least MinDriftDetectionInterval, or 0 if drift detection is disabled.
+func driftDetectionInterval(config *configv1alpha1.SubmarinerConfig) time.Duration {
+	if config.Spec.DriftDetection == nil || config.Spec.DriftDetection.Interval == nil {
+		return 0
+	}
+
+	interval := config.Spec.DriftDetection.Interval.Duration
+	if interval <= 0 {
+		return 0
+	}
+
+	return max(interval, MinDriftDetectionInterval)
+}
+
+// scheduleDriftDetection records that the cluster environment of the given config was just prepared or verified, and requeues
+// the config to verify it again once the drift detection interval expires.
+func (c *submarinerConfigController) scheduleDriftDetection(syncCtx factory.SyncContext, config *configv1alpha1.SubmarinerConfig) {
+	interval := driftDetectionInterval(config)
+	if interval <= 0 {
+		delete(c.lastVerified, config.Namespace)
+		return
+	}
+
+	c.lastVerified[config.Namespace] = time.Now()
+	syncCtx.Queue().AddAfter(syncCtx.QueueKey(), interval)
+}
+
+// isDriftDetectionDue returns whether the cluster environment of the given config wasn't verified for longer than the drift
+// detection interval.
+func (c *submarinerConfigController) isDriftDetectionDue(config *configv1alpha1.SubmarinerConfig) bool {
+	interval := driftDetectionInterval(config)
+	lastVerified, found := c.lastVerified[config.Namespace]
+
+	return interval > 0 && found && time.Since(lastVerified) >= interval
+}
+
+// detectDrift verifies that the cloud resources prepared for the given config still exist as prepared, for the providers which
+// can detect drift, records the result in the SubmarinerClusterEnvironmentInSync condition and, if requested, prepares the
+// cluster environment again to remediate the drift, as it's prepared when the config changes.
+func (c *submarinerConfigController) detectDrift(ctx context.Context, syncCtx factory.SyncContext,
+	config *configv1alpha1.SubmarinerConfig,
+) error {
+	recorder := syncCtx.Recorder()
+
+	cloudProvider, providerFound, err := c.cloudProviderFactory.Get(ctx, config, recorder, nil)
+	if !providerFound {
+		return nil
+	}
+
+	detector, canDetectDrift := cloudProvider.(cloud.DriftDetector)
+	if err == nil && !canDetectDrift {
+		delete(c.lastVerified, config.Namespace)
+		return nil
+	}
+
+	defer c.scheduleDriftDetection(syncCtx, config)
+
+	condition := metav1.Condition{
+		Type:               configv1alpha1.SubmarinerConfigConditionEnvInSync,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NoDriftDetected",
+		Message:            "The submariner cluster environment is as prepared",
+		ObservedGeneration: config.Generation,
+	}
+
+	var drifts []string
+
+	if err == nil {
+		drifts, err = callCloudOperation(ctx, c, config.Namespace, "drift detection", c.cloudTimeouts.Check, detector.DetectDrift)
+	}
+
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "DriftDetectionFailed"
+		condition.Message = fmt.Sprintf("Failed to detect the drift of the submariner cluster environment: %v", err)
+
+		if isTimeout(err) {
+			condition.Reason = reasonTimeout
+		}
+	case len(drifts) > 0:
+		for _, drift := range drifts {
+			recorder.Warningf("SubmarinerClusterEnvDrifted", "The submariner cluster environment drifted: %s", drift)
+		}
+
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DriftDetected"
+		condition.Message = fmt.Sprintf("The submariner cluster environment drifted: %s", strings.Join(drifts, "; "))
+
+		if config.Spec.DriftDetection.Remediate {
+			// The cloud credentials are checked and, for the providers which use existing nodes, the gateways are labeled
+			// before the cluster environment is prepared again.
+			err = c.prepareForSubmariner(ctx, config, syncCtx)
+			if err != nil {
+				condition.Reason = "DriftRemediationFailed"
+				condition.Message = fmt.Sprintf("Failed to remediate the drift of the submariner cluster environment (%s): %v",
+					strings.Join(drifts, "; "), err)
+
+				if isTimeout(err) {
+					condition.Reason = reasonTimeout
+				}
+			} else {
+				condition.Status = metav1.ConditionTrue
+				condition.Reason = "DriftRemediated"
+				condition.Message = fmt.Sprintf("The drift of the submariner cluster environment was remediated: %s",
+					strings.Join(drifts, "; "))
+
+				recorder.Eventf("SubmarinerClusterEnvRemediated", "The drift of the submariner cluster environment was remediated")
+			}
+		}
+	}
+
+	_, _, updateErr := submarinerconfig.UpdateStatus(ctx, c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace),
+		config.Name, submarinerconfig.UpdateConditionFn(&condition), submarinerconfig.UpdateObservedGenerationFn(config.Generation))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", updateErr)
 
 	if err != nil {
 		return err
@@ -314,7 +838,7 @@ func (c *submarinerConfigController) prepareForSubmariner(ctx context.Context, c
 func (c *submarinerConfigController) cleanupClusterEnvironment(ctx context.Context, config *configv1alpha1.SubmarinerConfig,
 	recorder events.Recorder,
 ) error {
-	cloudProvider, found, err := c.cloudProviderFactory.Get(config, recorder)
+	cloudProvider, found, err := c.cloudProviderFactory.Get(ctx, config, recorder, nil)
 	if !found {
 		return errors.WithMessagef(c.removeAllGateways(ctx), "failed to unlabel the gateway nodes")
 	}
@@ -322,18 +846,29 @@ func (c *submarinerConfigController) cleanupClusterEnvironment(ctx context.Conte
 	if err == nil {
 		c.logger.Infof("Cleaning up the submariner cluster environment")
 
-		err = cloudProvider.CleanUpSubmarinerClusterEnv()
+		err = c.runCloudOperation(ctx, config.Namespace, "clean up", c.cloudTimeouts.CleanUp, cloudProvider.CleanUpSubmarinerClusterEnv)
+	}
+
+	if err != nil {
+		return errors.WithMessagef(err, "failed to clean up the submariner cluster environment")
+	}
+
+	if cloud.UsesExistingGatewayNodes(config.Status.ManagedClusterInfo.Vendor) {
+		return errors.WithMessagef(c.removeAllGateways(ctx), "failed to unlabel the gateway nodes")
 	}
 
-	return errors.WithMessagef(err, "failed to clean up the submariner cluster environment")
+	return nil
 }
 
 func (c *submarinerConfigController) updateSubmarinerConfigStatus(ctx context.Context, recorder events.Recorder,
 	config *configv1alpha1.SubmarinerConfig, condition *metav1.Condition,
 ) error {
+	condition.ObservedGeneration = config.Generation
+
 	updatedStatus, updated, err := submarinerconfig.UpdateStatus(ctx,
 		c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace), config.Name,
-		submarinerconfig.UpdateConditionFn(condition))
+		submarinerconfig.UpdateConditionFn(condition), submarinerconfig.UpdateObservedGenerationFn(config.Generation))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", err)
 
 	if updated {
 		c.logger.Infof("Updated SubmarinerConfig status condition: %s", resource.ToJSON(condition))
@@ -342,38 +877,56 @@ func (c *submarinerConfigController) updateSubmarinerConfigStatus(ctx context.Co
 
 		// When all is well, the status is eventually updated with a "true" condition, allowing us to cache latest good known config
 		if condition.Status == metav1.ConditionTrue {
-			c.knownConfigs[config.Namespace] = config
+			c.knownConfigs[config.Namespace] = knownConfig{config: config, credentialsHash: c.credentialsHash(config)}
 		}
 	}
 
 	return err
 }
 
+// ensureGateways labels the desired number of available gateways. Gateways which are unavailable for longer than the
+// failover grace period are replaced, and unlabeled if they were labeled by the controller. It returns the time after
+// which the unavailable gateways still within their grace period must be checked again.
 func (c *submarinerConfigController) ensureGateways(ctx context.Context,
-	config *configv1alpha1.SubmarinerConfig,
-) (metav1.Condition, error) {
+	config *configv1alpha1.SubmarinerConfig, recorder events.Recorder,
+) (metav1.Condition, time.Duration, error) {
 	if config.Spec.Gateways < 1 {
 		return metav1.Condition{
 			Type:    submarinerGatewayCondition,
 			Status:  metav1.ConditionFalse,
 			Reason:  "InvalidInput",
 			Message: "The desired number of gateways must be at least 1",
-		}, nil
+		}, 0, nil
 	}
 
-	currentGateways, err := c.getLabeledNodes(
+	selector, err := newGatewaySelector(config)
+	if err != nil {
+		return metav1.Condition{
+			Type:    submarinerGatewayCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidInput",
+			Message: fmt.Sprintf("The gateway selection policy is invalid: %v", err),
+		}, 0, nil
+	}
+
+	labeledGateways, err := c.getLabeledNodes(
 		nodeLabelSelector{submarinerGatewayLabel, selection.Exists},
 	)
 	if err != nil {
-		return failedCondition("Error retrieving nodes: %v", err), err
+		return failedCondition("Error retrieving nodes: %v", err), 0, err
 	}
 
+	availability := c.checkGatewayAvailability(config, labeledGateways)
+	currentGateways := availability.usable
+
 	currentGatewayNames := []string{}
 	for _, gateway := range currentGateways {
 		currentGatewayNames = append(currentGatewayNames, gateway.Name)
 	}
 
 	updatedGatewayNames := []string{}
+	reasons := []string{}
+	insufficientReason := ""
 
 	requiredGateways := config.Spec.Gateways - len(currentGateways)
 
@@ -389,12 +942,21 @@ func (c *submarinerConfigController) ensureGateways(ctx context.Context,
 		err = operatorhelpers.NewMultiLineAggregate(errs)
 	case requiredGateways > 0:
 		// gateways increased, need to label new ones
-		updatedGatewayNames, err = c.addGateways(ctx, config, requiredGateways)
+		updatedGatewayNames, reasons, insufficientReason, err = c.addGateways(ctx, config, selector, currentGateways,
+			requiredGateways)
+		if len(updatedGatewayNames) > 0 && len(availability.failedOver) > 0 {
+			recorder.Eventf("SubmarinerGatewayReplaced", "Labeled node(s) %q as gateways to replace the unavailable gateway(s) %s",
+				strings.Join(updatedGatewayNames, ","), strings.Join(describeUnavailable(availability.failedOver), ", "))
+		}
+
+		if len(updatedGatewayNames) > 0 {
+			updatedGatewayNames = append(updatedGatewayNames, currentGatewayNames...)
+		}
 	default:
 		// gateways decreased, need to unlabel some
 		var removed []string
 
-		removed, err = c.removeGateways(ctx, currentGateways, -requiredGateways)
+		removed, err = c.removeGateways(ctx, selector.selectForRemoval(currentGateways, -requiredGateways))
 
 		removedNames := sets.NewString(removed...)
 
@@ -406,21 +968,97 @@ func (c *submarinerConfigController) ensureGateways(ctx context.Context,
 	}
 
 	if err != nil {
-		return failedCondition("Unable to label the gateway nodes: %v", err), err
+		return failedCondition("Unable to label the gateway nodes: %v", err), 0, err
+	}
+
+	replaced := []string{}
+
+	if len(updatedGatewayNames) > 0 && len(availability.failedOver) > 0 {
+		replaced, err = c.failOverGateways(ctx, recorder, availability.failedOver, updatedGatewayNames)
+		if err != nil {
+			return failedCondition("Unable to unlabel the unavailable gateway nodes: %v", err), 0, err
+		}
 	}
 
 	if len(updatedGatewayNames) == 0 {
+		message := "Insufficient number of worker nodes to satisfy the desired number of gateways"
+		if insufficientReason != "" {
+			message += ": " + insufficientReason
+		}
+
 		return metav1.Condition{
 			Type:    submarinerGatewayCondition,
 			Status:  metav1.ConditionFalse,
-			Reason:  "InsufficientNodes",
-			Message: "Insufficient number of worker nodes to satisfy the desired number of gateways",
-		}, nil
+			Reason:  configv1alpha1.SubmarinerGatewaysLabeledReasonInsufficientNodes,
+			Message: message,
+		}, availability.requeueAfter, nil
 	}
 
 	sort.Strings(updatedGatewayNames)
 
-	return successCondition(updatedGatewayNames), nil
+	condition := successCondition(updatedGatewayNames)
+	if len(reasons) > 0 {
+		condition.Message += fmt.Sprintf(", newly selected %s", strings.Join(reasons, ", "))
+	}
+
+	if len(replaced) > 0 {
+		condition.Message += fmt.Sprintf(", replaced unavailable gateway(s) %s", strings.Join(replaced, ", "))
+	}
+
+	if len(availability.pending) > 0 {
+		condition.Message += fmt.Sprintf(", unavailable gateway(s) %s", strings.Join(availability.pending, ", "))
+	}
+
+	return condition, availability.requeueAfter, nil
+}
+
+// planGateways returns the sorted names of the nodes ensureGateways would label as gateways, without labeling nor
+// unlabeling any node.
+func (c *submarinerConfigController) planGateways(config *configv1alpha1.SubmarinerConfig) ([]string, error) {
+	if config.Spec.Gateways < 1 {
+		return nil, fmt.Errorf("the desired number of gateways must be at least 1")
+	}
+
+	selector, err := newGatewaySelector(config)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "the gateway selection policy is invalid")
+	}
+
+	labeledGateways, err := c.getLabeledNodes(nodeLabelSelector{submarinerGatewayLabel, selection.Exists})
+	if err != nil {
+		return nil, err
+	}
+
+	gateways := c.checkGatewayAvailability(config, labeledGateways).usable
+	requiredGateways := config.Spec.Gateways - len(gateways)
+
+	switch {
+	case requiredGateways > 0:
+		added, _, _, err := c.selectNewGateways(config, selector, gateways, requiredGateways)
+		if err != nil {
+			return nil, err
+		}
+
+		gateways = append(gateways, added...)
+	case requiredGateways < 0:
+		removedNames := sets.NewString()
+		for _, gateway := range selector.selectForRemoval(gateways, -requiredGateways) {
+			removedNames.Insert(gateway.Name)
+		}
+
+		gateways = slices.DeleteFunc(gateways, func(gateway *corev1.Node) bool {
+			return removedNames.Has(gateway.Name)
+		})
+	}
+
+	names := []string{}
+	for _, gateway := range gateways {
+		names = append(names, gateway.Name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
 }
 
 func (c *submarinerConfigController) getLabeledNodes(nodeLabelSelectors ...nodeLabelSelector) ([]*corev1.Node, error) {
@@ -447,13 +1085,16 @@ func (c *submarinerConfigController) labelNode(ctx context.Context, config *conf
 		return nil
 	}
 
-	return c.updateNode(ctx, node, func(node *corev1.Node) {
+	err := c.updateNode(ctx, node, func(node *corev1.Node) {
 		if !hasGatewayLabel {
 			node.Annotations[gatewayLabeledBySubmariner] = "true"
 			node.Labels[submarinerGatewayLabel] = "true"
 		}
 		node.Labels[submarinerUDPPortLabel] = nattPort
 	})
+	metrics.RecordGatewayLabelOperation(metrics.GatewayLabel, err)
+
+	return err
 }
 
 func (c *submarinerConfigController) updateNode(ctx context.Context, node *corev1.Node, mutate func(node *corev1.Node)) error {
@@ -490,7 +1131,7 @@ func (c *submarinerConfigController) unlabelNode(ctx context.Context, node *core
 
 	c.logger.Infof("Unlabeling gateway node %q", node.Name)
 
-	return c.updateNode(ctx, node, func(node *corev1.Node) {
+	err := c.updateNode(ctx, node, func(node *corev1.Node) {
 		_, hasGatewayLabelFromSubmariner := node.Annotations[gatewayLabeledBySubmariner]
 		if hasGatewayLabelFromSubmariner {
 			delete(node.Labels, submarinerGatewayLabel)
@@ -501,17 +1142,19 @@ func (c *submarinerConfigController) unlabelNode(ctx context.Context, node *core
 
 		delete(node.Labels, submarinerUDPPortLabel)
 	})
+	metrics.RecordGatewayLabelOperation(metrics.GatewayUnlabel, err)
+
+	return err
 }
 
+// addGateways labels the expected number of additional gateways selected according to the gateway selection policy. It
+// returns the names of the labeled nodes and the reasoning of their selection, or the reason why no node was selected.
 func (c *submarinerConfigController) addGateways(ctx context.Context, config *configv1alpha1.SubmarinerConfig,
-	expectedGateways int,
-) ([]string, error) {
-	// for other non-public cloud platform (vsphere) or native k8s
-	zoneLabel := defaultZoneLabel
-
-	gateways, err := c.findGatewaysWithZone(expectedGateways, zoneLabel)
+	selector *gatewaySelector, currentGateways []*corev1.Node, expectedGateways int,
+) ([]string, []string, string, error) {
+	gateways, reasons, insufficientReason, err := c.selectNewGateways(config, selector, currentGateways, expectedGateways)
 	if err != nil {
-		return []string{}, err
+		return []string{}, nil, "", err
 	}
 
 	names := []string{}
@@ -521,20 +1164,32 @@ func (c *submarinerConfigController) addGateways(ctx context.Context, config *co
 		names = append(names, gateway.Name)
 	}
 
-	return names, operatorhelpers.NewMultiLineAggregate(errs)
+	return names, reasons, insufficientReason, operatorhelpers.NewMultiLineAggregate(errs)
 }
 
-func (c *submarinerConfigController) removeGateways(ctx context.Context, gateways []*corev1.Node, removedGateways int) ([]string, error) {
-	if len(gateways) < removedGateways {
-		removedGateways = len(gateways)
+// selectNewGateways selects the expected number of additional gateways among the unlabeled candidate nodes, according to the
+// gateway selection policy.
+func (c *submarinerConfigController) selectNewGateways(config *configv1alpha1.SubmarinerConfig, selector *gatewaySelector,
+	currentGateways []*corev1.Node, expectedGateways int,
+) ([]*corev1.Node, []string, string, error) {
+	candidates, err := c.getLabeledNodes(append(gatewayCandidateSelectors(config),
+		nodeLabelSelector{submarinerGatewayLabel, selection.DoesNotExist})...)
+	if err != nil {
+		return nil, nil, "", err
 	}
 
+	gateways, reasons, insufficientReason := selector.selectGateways(candidates, currentGateways, expectedGateways)
+
+	return gateways, reasons, insufficientReason, nil
+}
+
+func (c *submarinerConfigController) removeGateways(ctx context.Context, gateways []*corev1.Node) ([]string, error) {
 	errs := []error{}
 	removed := []string{}
 
-	for i := 0; i < removedGateways; i++ {
-		removed = append(removed, gateways[i].Name)
-		errs = append(errs, c.unlabelNode(ctx, gateways[i]))
+	for _, gateway := range gateways {
+		removed = append(removed, gateway.Name)
+		errs = append(errs, c.unlabelNode(ctx, gateway))
 	}
 
 	return removed, operatorhelpers.NewMultiLineAggregate(errs)
@@ -546,66 +1201,23 @@ func (c *submarinerConfigController) removeAllGateways(ctx context.Context) erro
 		return err
 	}
 
-	_, err = c.removeGateways(ctx, gateways, len(gateways))
+	_, err = c.removeGateways(ctx, gateways)
 
 	return err
 }
 
-func (c *submarinerConfigController) findGatewaysWithZone(expected int, zoneLabel string) ([]*corev1.Node, error) {
-	workers, err := c.getLabeledNodes(
-		nodeLabelSelector{workerNodeLabel, selection.Exists},
-		nodeLabelSelector{submarinerGatewayLabel, selection.DoesNotExist},
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(workers) < expected {
-		return []*corev1.Node{}, nil
-	}
-
-	// group the nodes with zone
-	zoneNodes := map[string][]*corev1.Node{}
-
-	for _, worker := range workers {
-		zone, has := worker.Labels[zoneLabel]
-		if !has {
-			zone = "unknown"
-		}
-
-		nodes, has := zoneNodes[zone]
-		if !has {
-			zoneNodes[zone] = []*corev1.Node{worker}
-
-			continue
-		}
-
-		nodes = append(nodes, worker)
-		zoneNodes[zone] = nodes
-	}
-
-	count := 0
-	nodeIndex := 0
-	gateways := []*corev1.Node{}
-	// find candidate gateways from different zones
-	for count < expected {
-		for _, nodes := range zoneNodes {
-			if nodeIndex >= len(nodes) {
-				continue
-			}
-
-			if count == expected {
-				break
-			}
-
-			gateways = append(gateways, nodes[nodeIndex])
-			count++
+// gatewayCandidateSelectors returns the selectors matching the nodes eligible as gateways. The nodes of managed Kubernetes
+// services aren't labeled with the worker role, any node that isn't part of the control plane is eligible there.
+func gatewayCandidateSelectors(config *configv1alpha1.SubmarinerConfig) []nodeLabelSelector {
+	switch config.Status.ManagedClusterInfo.Vendor {
+	case constants.ProductEKS, constants.ProductGKE, constants.ProductAKS, constants.ProductIKS:
+		return []nodeLabelSelector{
+			{controlPlaneNodeLabel, selection.DoesNotExist},
+			{masterNodeLabel, selection.DoesNotExist},
 		}
-
-		nodeIndex++
 	}
 
-	return gateways, nil
+	return []nodeLabelSelector{{workerNodeLabel, selection.Exists}}
 }
 
 func (c *submarinerConfigController) updateGatewayStatus(ctx context.Context, recorder events.Recorder,
@@ -630,7 +1242,7 @@ func (c *submarinerConfigController) updateGatewayStatus(ctx context.Context, re
 		condition = metav1.Condition{
 			Type:   submarinerGatewayCondition,
 			Status: metav1.ConditionFalse,
-			Reason: "InsufficientNodes",
+			Reason: configv1alpha1.SubmarinerGatewaysLabeledReasonInsufficientNodes,
 			Message: fmt.Sprintf("The %d worker nodes labeled as gateways (%q) does not match the desired number %d",
 				len(gatewayNames), strings.Join(gatewayNames, ","), config.Spec.Gateways),
 		}
@@ -662,6 +1274,7 @@ func (c *submarinerConfigController) setNetworkTypeIfAbsent(ctx context.Context,
 
 	_, updated, updatedErr := submarinerconfig.UpdateStatus(ctx, c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(config.Namespace),
 		config.Name, submarinerconfig.UpdateStatusFn(nil, &config.Status.ManagedClusterInfo))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", updatedErr)
 
 	if updated {
 		msg := fmt.Sprintf("SubmarinerConfig network type was set to %q for managed cluster %q", networkType, config.Namespace)
@@ -711,6 +1324,8 @@ func (c *submarinerConfigController) validateOCPVersion(ctx context.Context, con
 			Message: fmt.Sprintf("OCP version is %s, submariner OVN requires %s+", ocpVersion, constants.OCPVersionForOVNK),
 		}
 		updatedStatus, updated, err := addon.UpdateStatus(ctx, c.addOnClient, c.clusterName, addon.UpdateConditionFn(&ovnCondition))
+		metrics.RecordStatusUpdateConflict("ManagedClusterAddOn", err)
+
 		if updated {
 			recorder.Eventf("ManagedClusterAddOnStatusUpdated", "Updated status conditions:  %#v",
 				updatedStatus.Conditions)
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -15,7 +18,9 @@ import (
 	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
 	configFake "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/clientset/versioned/fake"
 	configInformers "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions"
+	"github.com/stolostron/submariner-addon/pkg/cloud"
 	cloudFake "github.com/stolostron/submariner-addon/pkg/cloud/fake"
+	cloudProvider "github.com/stolostron/submariner-addon/pkg/cloud/provider"
 	"github.com/stolostron/submariner-addon/pkg/constants"
 	"github.com/stolostron/submariner-addon/pkg/resource"
 	"github.com/stolostron/submariner-addon/pkg/spoke/submarineragent"
@@ -33,6 +38,7 @@ import (
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubeInformers "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	kubeFake "k8s.io/client-go/kubernetes/fake"
 	k8sScheme "k8s.io/client-go/kubernetes/scheme"
 	clientTesting "k8s.io/client-go/testing"
@@ -51,6 +57,10 @@ var _ = Describe("Config Controller", func() {
 
 	testWorkerNodeLabeling(t)
 
+	testGatewaySelectionPolicy(t)
+
+	testGatewayFailover(t)
+
 	testSubmarinerConfig(t)
 
 	testManagedClusterAddOn(t)
@@ -71,6 +81,29 @@ func testWorkerNodeLabeling(t *configControllerTestDriver) {
 		It("should update the SubmarinerConfig cluster environment prepared condition", func() {
 			t.awaitClusterEnvPreparedSuccessCondition()
 		})
+
+		Context("and the SubmarinerConfig was updated", func() {
+			BeforeEach(func() {
+				t.config.Generation = 2
+			})
+
+			It("should set the SubmarinerConfig generation as the observed generation of the conditions", func() {
+				t.awaitClusterEnvPreparedSuccessCondition()
+				t.awaitGatewaysLabeledSuccessCondition()
+
+				config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+					constants.SubmarinerConfigName, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+
+				for _, conditionType := range []string{configv1alpha1.SubmarinerConfigConditionEnvPrepared, gatewayConditionType} {
+					condition := meta.FindStatusCondition(config.Status.Conditions, conditionType)
+					Expect(condition).ToNot(BeNil())
+					Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+				}
+
+				Expect(config.Status.ObservedGeneration).To(Equal(int64(2)))
+			})
+		})
 	})
 
 	When("the desired number of gateway nodes are already", func() {
@@ -240,6 +273,124 @@ func testWorkerNodeLabeling(t *configControllerTestDriver) {
 	})
 }
 
+func testGatewaySelectionPolicy(t *configControllerTestDriver) {
+	BeforeEach(func() {
+		t.config.Spec.SelectionPolicy = &configv1alpha1.GatewaySelectionPolicy{}
+
+		t.nodes = []*corev1.Node{
+			newWorkerNodeInZone("worker-1", "zone-a"),
+			newWorkerNodeInZone("worker-2", "zone-a"),
+			newWorkerNodeInZone("worker-3", "zone-b"),
+			newWorkerNodeInZone("worker-4", "zone-b"),
+		}
+	})
+
+	When("a node selector is specified", func() {
+		BeforeEach(func() {
+			t.nodes[3].Labels["public-nic"] = "true"
+			t.config.Spec.SelectionPolicy.NodeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"public-nic": "true"}}
+		})
+
+		It("should only label the matching nodes", func() {
+			t.awaitGatewaysLabeledSuccessCondition()
+			t.awaitGatewayNodeNames("worker-4")
+		})
+	})
+
+	When("an exclude selector is specified", func() {
+		BeforeEach(func() {
+			t.config.Spec.Gateways = 2
+			t.nodes[0].Labels["no-gateway"] = ""
+			t.nodes[2].Labels["no-gateway"] = ""
+			t.config.Spec.SelectionPolicy.ExcludeSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "no-gateway", Operator: metav1.LabelSelectorOpExists}},
+			}
+		})
+
+		It("should not label the excluded nodes", func() {
+			t.awaitGatewaysLabeledSuccessCondition()
+			t.awaitGatewayNodeNames("worker-2", "worker-4")
+		})
+	})
+
+	When("multiple gateways are desired", func() {
+		BeforeEach(func() {
+			t.config.Spec.Gateways = 2
+		})
+
+		It("should spread them across zones", func() {
+			t.awaitGatewaysLabeledSuccessCondition()
+			t.awaitGatewayNodeNames("worker-1", "worker-3")
+		})
+	})
+
+	When("a custom zone topology key is specified", func() {
+		BeforeEach(func() {
+			t.config.Spec.Gateways = 2
+			t.config.Spec.SelectionPolicy.ZoneTopologyKey = "rack"
+			t.nodes[0].Labels["rack"] = "rack-1"
+			t.nodes[1].Labels["rack"] = "rack-1"
+			t.nodes[2].Labels["rack"] = "rack-1"
+			t.nodes[3].Labels["rack"] = "rack-2"
+		})
+
+		It("should spread the gateways across its values", func() {
+			t.awaitGatewaysLabeledSuccessCondition()
+			t.awaitGatewayNodeNames("worker-1", "worker-4")
+		})
+	})
+
+	When("preferred nodes are specified", func() {
+		BeforeEach(func() {
+			t.config.Spec.SelectionPolicy.PreferredNodes = []string{"worker-2"}
+		})
+
+		It("should label the preferred nodes first", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:    gatewayConditionType,
+				Status:  metav1.ConditionTrue,
+				Reason:  "Success",
+				Message: `1 node(s) ("worker-2") are labeled as gateways, newly selected "worker-2" (preferred node in zone "zone-a")`,
+			})
+			t.awaitGatewayNodeNames("worker-2")
+		})
+
+		Context("and the desired number of gateway nodes is decreased", func() {
+			BeforeEach(func() {
+				t.config.Spec.Gateways = 2
+			})
+
+			It("should unlabel the non-preferred nodes", func() {
+				t.awaitGatewayNodeNames("worker-2", "worker-3")
+
+				t.config.Spec.Gateways = 1
+				_, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(t.config.Namespace).Update(context.TODO(),
+					t.config, metav1.UpdateOptions{})
+				Expect(err).To(Succeed())
+
+				t.awaitGatewayNodeNames("worker-2")
+			})
+		})
+	})
+
+	When("spreading across zones is required and there are fewer zones than desired gateways", func() {
+		BeforeEach(func() {
+			t.config.Spec.Gateways = 3
+			t.config.Spec.SelectionPolicy.SpreadAcrossZones = true
+		})
+
+		It("should not label any node", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   gatewayConditionType,
+				Status: metav1.ConditionFalse,
+				Reason: "InsufficientNodes",
+			})
+
+			t.ensureNoLabeledNodes()
+		})
+	})
+}
+
 func testSubmarinerConfig(t *configControllerTestDriver) {
 	When("the SubmarinerConfig doesn't initially exist", func() {
 		BeforeEach(func() {
@@ -282,7 +433,7 @@ func testSubmarinerConfig(t *configControllerTestDriver) {
 	When("the SubmarinerConfig's Platform field is set to AWS", func() {
 		BeforeEach(func() {
 			t.config.Status.ManagedClusterInfo.Platform = aws
-			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv().Return(nil).MinTimes(1)
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).MinTimes(1)
 		})
 
 		It("should invoke the cloud provider and update the SubmarinerConfig status condition", func() {
@@ -290,6 +441,442 @@ func testSubmarinerConfig(t *configControllerTestDriver) {
 		})
 	})
 
+	When("the SubmarinerConfig's credentials secret changes", func() {
+		var prepareCount atomic.Int32
+
+		BeforeEach(func() {
+			prepareCount.Store(0)
+
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "aws-credentials"}
+			labelGateway(t.nodes[0], true)
+
+			_, err := t.hubKubeClient.CoreV1().Secrets(clusterName).Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      t.config.Spec.CredentialsSecret.Name,
+					Namespace: clusterName,
+				},
+				Data: map[string][]byte{"aws_access_key_id": []byte("expired")},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				prepareCount.Add(1)
+				return nil
+			}).MinTimes(1)
+		})
+
+		It("should prepare the cluster environment again", func() {
+			t.awaitClusterEnvPreparedSuccessCondition()
+			t.awaitGatewaysLabeledSuccessCondition()
+
+			count := prepareCount.Load()
+			Consistently(prepareCount.Load, 300*time.Millisecond).Should(Equal(count))
+
+			_, err := t.hubKubeClient.CoreV1().Secrets(clusterName).Update(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      t.config.Spec.CredentialsSecret.Name,
+					Namespace: clusterName,
+				},
+				Data: map[string][]byte{"aws_access_key_id": []byte("renewed")},
+			}, metav1.UpdateOptions{})
+			Expect(err).To(Succeed())
+
+			Eventually(prepareCount.Load).Should(BeNumerically(">", count))
+		})
+
+		It("should only watch the credentials secret", func() {
+			t.awaitClusterEnvPreparedSuccessCondition()
+			Expect(t.watchedSecretNames()).To(Equal([]string{"aws-credentials"}))
+		})
+
+		Context("and the SubmarinerConfig references another secret", func() {
+			It("should watch the other secret and prepare the cluster environment again", func() {
+				t.awaitClusterEnvPreparedSuccessCondition()
+
+				_, err := t.hubKubeClient.CoreV1().Secrets(clusterName).Create(context.TODO(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "other-aws-credentials",
+						Namespace: clusterName,
+					},
+					Data: map[string][]byte{"aws_access_key_id": []byte("other")},
+				}, metav1.CreateOptions{})
+				Expect(err).To(Succeed())
+
+				count := prepareCount.Load()
+
+				t.config.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "other-aws-credentials"}
+				_, err = t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(t.config.Namespace).Update(context.TODO(),
+					t.config, metav1.UpdateOptions{})
+				Expect(err).To(Succeed())
+
+				Eventually(prepareCount.Load).Should(BeNumerically(">", count))
+				Expect(t.watchedSecretNames()).To(Equal([]string{"aws-credentials", "other-aws-credentials"}))
+			})
+		})
+	})
+
+	When("the SubmarinerConfig's credentials secret doesn't exist", func() {
+		var prepareCount atomic.Int32
+
+		BeforeEach(func() {
+			prepareCount.Store(0)
+
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.CredentialsSecret = &corev1.LocalObjectReference{Name: "aws-credentials"}
+
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				prepareCount.Add(1)
+				return nil
+			}).MinTimes(1)
+		})
+
+		It("should prepare the cluster environment once the secret informer is synced", func() {
+			Eventually(prepareCount.Load, 3*time.Second).Should(BeNumerically(">", 0))
+		})
+	})
+
+	When("the cloud provider fails because of invalid credentials", func() {
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(
+				fmt.Errorf("the credentials have expired: %w", cloudProvider.ErrInvalidCredentials)).MinTimes(1)
+		})
+
+		It("should set a failure status condition with the invalid credentials reason", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionFalse,
+				Reason: "InvalidCloudCredentials",
+			})
+		})
+	})
+
+	When("the cloud provider doesn't complete the preparation in time", func() {
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.cloudTimeouts.Prepare = 100 * time.Millisecond
+
+			// The preparation ignores the context, like the cloud-prepare calls.
+			release := make(chan struct{})
+			DeferCleanup(func() {
+				close(release)
+			})
+
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				stepLog := t.stepLog
+				stepLog.Start("Opening the Submariner ports")
+
+				select {
+				case <-release:
+				case <-time.After(300 * time.Millisecond):
+					stepLog.Start("Deploying the gateway MachineSet")
+					<-release
+				}
+
+				return nil
+			}).Times(1)
+		})
+
+		It("should set a failure status condition with the timeout reason", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionFalse,
+				Reason: "Timeout",
+			})
+		})
+
+		It("should not publish the steps reported after the timeout", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionFalse,
+				Reason: "Timeout",
+			})
+
+			Consistently(func() []configv1alpha1.PreparationStep {
+				config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+					constants.SubmarinerConfigName, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+
+				return config.Status.PreparationSteps
+			}, 500*time.Millisecond).ShouldNot(ContainElement(HaveField("Name", "Deploying the gateway MachineSet")))
+		})
+	})
+
+	When("the cloud provider reports the steps of the preparation", func() {
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Platform = aws
+
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				t.stepLog.Start("Opening the Submariner ports")
+				t.stepLog.Succeed("Opened the Submariner ports")
+				t.stepLog.Start("Deploying the gateway MachineSet")
+				t.stepLog.Fail("Failed to deploy the gateway MachineSet")
+
+				return errors.New("fake error")
+			}).MinTimes(1)
+		})
+
+		It("should record the steps in the status", func() {
+			Eventually(func() []configv1alpha1.PreparationStep {
+				config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+					constants.SubmarinerConfigName, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+
+				return config.Status.PreparationSteps
+			}).Should(HaveExactElements(
+				And(HaveField("Name", "Opening the Submariner ports"),
+					HaveField("Outcome", configv1alpha1.PreparationStepOutcomeSucceeded),
+					HaveField("Message", "Opened the Submariner ports"),
+					HaveField("EndTime", Not(BeNil()))),
+				And(HaveField("Name", "Deploying the gateway MachineSet"),
+					HaveField("Outcome", configv1alpha1.PreparationStepOutcomeFailed),
+					HaveField("Message", "Failed to deploy the gateway MachineSet"))))
+		})
+	})
+
+	When("the cloud credentials are missing permissions", func() {
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.checker = cloudFake.NewMockPermissionsChecker(t.mockCtrl)
+			t.checker.EXPECT().CheckPermissions(gomock.Any()).Return([]string{"ec2:CreateSecurityGroup", "ec2:CreateTags"}, nil).MinTimes(1)
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Times(0)
+		})
+
+		It("should set a failure status condition listing the missing permissions", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionCloudCredentialsValid,
+				Status: metav1.ConditionFalse,
+				Reason: "MissingPermissions",
+			})
+
+			config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+				constants.SubmarinerConfigName, metav1.GetOptions{})
+			Expect(err).To(Succeed())
+
+			condition := meta.FindStatusCondition(config.Status.Conditions, configv1alpha1.SubmarinerConfigConditionCloudCredentialsValid)
+			Expect(condition.Message).To(ContainSubstring("ec2:CreateSecurityGroup, ec2:CreateTags"))
+
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionFalse,
+				Reason: "SubmarinerClusterEnvPreparationFailed",
+			})
+		})
+	})
+
+	When("the cloud credentials have the required permissions", func() {
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.checker = cloudFake.NewMockPermissionsChecker(t.mockCtrl)
+			t.checker.EXPECT().CheckPermissions(gomock.Any()).Return([]string{}, nil).MinTimes(1)
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).MinTimes(1)
+		})
+
+		It("should set a success status condition and prepare the cluster environment", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionCloudCredentialsValid,
+				Status: metav1.ConditionTrue,
+				Reason: "CloudCredentialsValid",
+			})
+
+			t.awaitClusterEnvPreparedSuccessCondition()
+		})
+	})
+
+	When("the SubmarinerConfig is a dry run", func() {
+		plannedChanges := []configv1alpha1.PlannedChange{{
+			Action:      configv1alpha1.PlannedChangeActionCreate,
+			Kind:        "SecurityGroup",
+			Name:        "test-infra-submariner-gw-sg",
+			Description: "Open 4500/udp, 4900/udp",
+		}}
+
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.DryRun = true
+			t.cloudProvider.EXPECT().Plan(gomock.Any()).Return(plannedChanges, nil).Times(1)
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Times(0)
+		})
+
+		It("should publish the planned changes in the status without preparing the cluster environment", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionUnknown,
+				Reason: "SubmarinerClusterEnvPlanned",
+			})
+
+			config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+				constants.SubmarinerConfigName, metav1.GetOptions{})
+			Expect(err).To(Succeed())
+			Expect(config.Status.PlannedChanges).To(Equal(plannedChanges))
+		})
+
+		It("should not plan the changes again until the SubmarinerConfig changes", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionUnknown,
+				Reason: "SubmarinerClusterEnvPlanned",
+			})
+
+			// A node change resyncs the config, the planned changes must not be planned again.
+			t.setNodeReady("worker-1", corev1.ConditionTrue)
+			t.ensureNoLabeledNodes()
+		})
+	})
+
+	When("the SubmarinerConfig of a managed Kubernetes service is a dry run", func() {
+		plannedChanges := []configv1alpha1.PlannedChange{{
+			Action:      configv1alpha1.PlannedChangeActionCreate,
+			Kind:        "FirewallRule",
+			Name:        "worker-1",
+			Description: "Open 4500/udp, 4900/udp",
+		}}
+
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Vendor = constants.ProductEKS
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.DryRun = true
+
+			t.gatewayPlanner = cloudFake.NewMockGatewayPlanner(t.mockCtrl)
+			t.gatewayPlanner.EXPECT().PlanForGateways(gomock.Any(), []string{"worker-1"}).Return(plannedChanges, nil).Times(1)
+			t.cloudProvider.EXPECT().Plan(gomock.Any()).Times(0)
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Times(0)
+		})
+
+		It("should plan the changes for the gateway candidates without labeling them", func() {
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   configv1alpha1.SubmarinerConfigConditionEnvPrepared,
+				Status: metav1.ConditionUnknown,
+				Reason: "SubmarinerClusterEnvPlanned",
+			})
+
+			config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+				constants.SubmarinerConfigName, metav1.GetOptions{})
+			Expect(err).To(Succeed())
+			Expect(config.Status.PlannedChanges).To(Equal(plannedChanges))
+
+			t.ensureNoLabeledNodes()
+		})
+	})
+
+	When("the drift detection interval is shorter than the minimum", func() {
+		var detectCount atomic.Int32
+
+		BeforeEach(func() {
+			interval := submarineragent.MinDriftDetectionInterval
+			submarineragent.MinDriftDetectionInterval = time.Second
+
+			DeferCleanup(func() {
+				submarineragent.MinDriftDetectionInterval = interval
+			})
+
+			detectCount.Store(0)
+
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.DriftDetection = &configv1alpha1.DriftDetection{Interval: &metav1.Duration{Duration: time.Millisecond}}
+			labelGateway(t.nodes[0], true)
+
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).Times(1)
+
+			t.driftDetector = cloudFake.NewMockDriftDetector(t.mockCtrl)
+			t.driftDetector.EXPECT().DetectDrift(gomock.Any()).DoAndReturn(func(_ context.Context) ([]string, error) {
+				detectCount.Add(1)
+				return nil, nil
+			}).AnyTimes()
+		})
+
+		It("should verify the prepared cloud environment at the minimum interval", func() {
+			t.awaitClusterEnvPreparedSuccessCondition()
+			Consistently(detectCount.Load, 500*time.Millisecond).Should(BeZero())
+			Eventually(detectCount.Load, 2*time.Second).Should(BeNumerically(">", 0))
+		})
+	})
+
+	When("the prepared cloud environment drifts", func() {
+		drifts := []string{`the SecurityGroup "test-infra-submariner-gw-sg" was deleted`}
+
+		BeforeEach(func() {
+			interval := submarineragent.MinDriftDetectionInterval
+			submarineragent.MinDriftDetectionInterval = 50 * time.Millisecond
+
+			DeferCleanup(func() {
+				submarineragent.MinDriftDetectionInterval = interval
+			})
+
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.DriftDetection = &configv1alpha1.DriftDetection{Interval: &metav1.Duration{Duration: 100 * time.Millisecond}}
+			labelGateway(t.nodes[0], true)
+
+			t.driftDetector = cloudFake.NewMockDriftDetector(t.mockCtrl)
+			t.driftDetector.EXPECT().DetectDrift(gomock.Any()).Return(drifts, nil).MinTimes(1)
+		})
+
+		Context("", func() {
+			BeforeEach(func() {
+				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).Times(1)
+			})
+
+			It("should report the drift in the status without preparing the cluster environment again", func() {
+				t.awaitClusterEnvPreparedSuccessCondition()
+				t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+					Type:   configv1alpha1.SubmarinerConfigConditionEnvInSync,
+					Status: metav1.ConditionFalse,
+					Reason: "DriftDetected",
+				})
+
+				config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+					constants.SubmarinerConfigName, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+
+				condition := meta.FindStatusCondition(config.Status.Conditions, configv1alpha1.SubmarinerConfigConditionEnvInSync)
+				Expect(condition.Message).To(ContainSubstring(drifts[0]))
+			})
+		})
+
+		Context("and remediation is enabled", func() {
+			BeforeEach(func() {
+				t.config.Spec.DriftDetection.Remediate = true
+				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).MinTimes(2)
+			})
+
+			It("should prepare the cluster environment again", func() {
+				t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+					Type:   configv1alpha1.SubmarinerConfigConditionEnvInSync,
+					Status: metav1.ConditionTrue,
+					Reason: "DriftRemediated",
+				})
+			})
+		})
+
+		Context("and remediation is enabled but the cloud credentials lost permissions", func() {
+			BeforeEach(func() {
+				t.config.Spec.DriftDetection.Remediate = true
+				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).Times(1)
+
+				t.checker = cloudFake.NewMockPermissionsChecker(t.mockCtrl)
+				gomock.InOrder(
+					t.checker.EXPECT().CheckPermissions(gomock.Any()).Return([]string{}, nil).Times(1),
+					t.checker.EXPECT().CheckPermissions(gomock.Any()).Return([]string{"ec2:CreateTags"}, nil).MinTimes(1),
+				)
+			})
+
+			It("should check the cloud credentials before preparing the cluster environment again", func() {
+				t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+					Type:   configv1alpha1.SubmarinerConfigConditionEnvInSync,
+					Status: metav1.ConditionFalse,
+					Reason: "DriftRemediationFailed",
+				})
+
+				t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+					Type:   configv1alpha1.SubmarinerConfigConditionCloudCredentialsValid,
+					Status: metav1.ConditionFalse,
+					Reason: "MissingPermissions",
+				})
+			})
+		})
+	})
+
 	When("the SubmarinerConfig's Platform field is set to GCP", func() {
 		BeforeEach(func() {
 			t.config.Status.ManagedClusterInfo.Platform = gcp
@@ -297,7 +884,7 @@ func testSubmarinerConfig(t *configControllerTestDriver) {
 
 		Context("", func() {
 			BeforeEach(func() {
-				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv().Return(nil).MinTimes(1)
+				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).MinTimes(1)
 			})
 
 			It("should invoke the cloud provider and update the SubmarinerConfig status condition", func() {
@@ -331,8 +918,8 @@ func testSubmarinerConfig(t *configControllerTestDriver) {
 			BeforeEach(func() {
 				waitCh = make(chan struct{})
 				gomock.InOrder(
-					t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv().Return(errors.New("fake error")).Times(1),
-					t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv().DoAndReturn(func() error {
+					t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(errors.New("fake error")).Times(1),
+					t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
 						<-waitCh
 
 						return nil
@@ -358,6 +945,80 @@ func testSubmarinerConfig(t *configControllerTestDriver) {
 		})
 	})
 
+	When("the SubmarinerConfig's Vendor is a managed Kubernetes service", func() {
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Vendor = constants.ProductEKS
+			t.config.Status.ManagedClusterInfo.Platform = aws
+
+			t.nodes = []*corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "node-1",
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+					},
+				},
+			}
+
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				node, err := t.kubeClient.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+				Expect(err).To(Succeed())
+				Expect(node.Labels).To(HaveKeyWithValue("submariner.io/gateway", "true"))
+
+				return nil
+			}).MinTimes(1)
+		})
+
+		It("should label the gateway nodes before invoking the cloud provider", func() {
+			t.awaitClusterEnvPreparedSuccessCondition()
+			t.awaitGatewaysLabeledSuccessCondition()
+		})
+	})
+
+	When("a gateway node of a managed Kubernetes service becomes unavailable", func() {
+		var prepareCount atomic.Int32
+
+		BeforeEach(func() {
+			t.config.Status.ManagedClusterInfo.Vendor = constants.ProductEKS
+			t.config.Status.ManagedClusterInfo.Platform = aws
+			t.config.Spec.FailoverGracePeriod = &metav1.Duration{}
+
+			prepareCount.Store(0)
+			t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				prepareCount.Add(1)
+				return nil
+			}).AnyTimes()
+		})
+
+		It("should prepare the cluster environment again once for the replacement gateway", func() {
+			t.awaitGatewayNodeNames("worker-1")
+			Eventually(prepareCount.Load).Should(Equal(int32(1)))
+
+			t.setNodeReady("worker-1", corev1.ConditionFalse)
+
+			t.awaitGatewayNodeNames("worker-2")
+			Eventually(prepareCount.Load).Should(Equal(int32(2)))
+			Consistently(prepareCount.Load, 300*time.Millisecond).Should(Equal(int32(2)))
+		})
+
+		Context("and it was labeled by the user", func() {
+			BeforeEach(func() {
+				labelGateway(t.nodes[0], true)
+				t.nodes[0].Spec.Unschedulable = true
+			})
+
+			It("should not prepare the cluster environment again on every sync", func() {
+				t.awaitGatewayNodeNames("worker-2")
+				t.awaitGatewaysLabeledSuccessCondition()
+				Eventually(prepareCount.Load).Should(Equal(int32(1)))
+
+				t.setNodeReady("worker-2", corev1.ConditionTrue)
+
+				Consistently(prepareCount.Load, 300*time.Millisecond).Should(Equal(int32(1)))
+			})
+		})
+	})
+
 	When("updating the SubmarinerConfig status initially fails", func() {
 		BeforeEach(func() {
 			fake.FailOnAction(&t.configClient.Fake, "*", "update", nil, true)
@@ -472,8 +1133,8 @@ func testManagedClusterAddOn(t *configControllerTestDriver) {
 		Context("the SubmarinerConfig's Platform field is set to AWS", func() {
 			BeforeEach(func() {
 				t.config.Status.ManagedClusterInfo.Platform = aws
-				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv().Return(nil).AnyTimes()
-				t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv().Return(nil).MinTimes(1)
+				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).AnyTimes()
+				t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv(gomock.Any()).Return(nil).MinTimes(1)
 			})
 
 			It("should invoke the cloud provider to clean up", func() {
@@ -491,13 +1152,13 @@ func testManagedClusterAddOn(t *configControllerTestDriver) {
 
 		Context("the SubmarinerConfig's Platform field is set to GCP", func() {
 			BeforeEach(func() {
-				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv().Return(nil).AnyTimes()
+				t.cloudProvider.EXPECT().PrepareSubmarinerClusterEnv(gomock.Any()).Return(nil).AnyTimes()
 				t.config.Status.ManagedClusterInfo.Platform = gcp
 			})
 
 			Context("", func() {
 				BeforeEach(func() {
-					t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv().Return(nil).MinTimes(1)
+					t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv(gomock.Any()).Return(nil).MinTimes(1)
 				})
 
 				It("should invoke the cloud provider to clean up", func() {
@@ -511,7 +1172,7 @@ func testManagedClusterAddOn(t *configControllerTestDriver) {
 
 			Context("", func() {
 				BeforeEach(func() {
-					t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv().Return(nil).AnyTimes()
+					t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv(gomock.Any()).Return(nil).AnyTimes()
 				})
 
 				It("should not unlabel the gateway nodes", func() {
@@ -525,8 +1186,8 @@ func testManagedClusterAddOn(t *configControllerTestDriver) {
 				BeforeEach(func() {
 					waitCh = make(chan struct{})
 					gomock.InOrder(
-						t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv().Return(errors.New("fake error")).Times(1),
-						t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv().DoAndReturn(func() error {
+						t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv(gomock.Any()).Return(errors.New("fake error")).Times(1),
+						t.cloudProvider.EXPECT().CleanUpSubmarinerClusterEnv(gomock.Any()).DoAndReturn(func(_ context.Context) error {
 							<-waitCh
 
 							return nil
@@ -558,11 +1219,97 @@ type configControllerTestDriver struct {
 	nodes           []*corev1.Node
 	stop            context.CancelFunc
 	kubeClient      *kubeFake.Clientset
+	hubKubeClient   *kubeFake.Clientset
 	configClient    *configFake.Clientset
 	dynamicClient   *dynamicfake.FakeDynamicClient
 	cloudProvider   *cloudFake.MockProvider
+	checker         *cloudFake.MockPermissionsChecker
+	driftDetector   *cloudFake.MockDriftDetector
+	gatewayPlanner  *cloudFake.MockGatewayPlanner
+	cloudTimeouts   submarineragent.CloudTimeouts
+	stepLog         *cloudProvider.StepLog
 	providerFactory *cloudFake.MockProviderFactory
 	mockCtrl        *gomock.Controller
+	secretsMutex    sync.Mutex
+	watchedSecrets  []string
+}
+
+type permissionsCheckingProvider struct {
+	*cloudFake.MockProvider
+	*cloudFake.MockPermissionsChecker
+}
+
+type driftDetectingProvider struct {
+	*cloudFake.MockProvider
+	*cloudFake.MockDriftDetector
+}
+
+type checkingDriftDetectingProvider struct {
+	*cloudFake.MockProvider
+	*cloudFake.MockPermissionsChecker
+	*cloudFake.MockDriftDetector
+}
+
+type gatewayPlanningProvider struct {
+	*cloudFake.MockProvider
+	*cloudFake.MockGatewayPlanner
+}
+
+func testGatewayFailover(t *configControllerTestDriver) {
+	When("a gateway node labeled by the controller becomes NotReady", func() {
+		BeforeEach(func() {
+			t.config.Spec.FailoverGracePeriod = &metav1.Duration{}
+		})
+
+		It("should label a replacement and unlabel it", func() {
+			t.awaitGatewayNodeNames("worker-1")
+
+			t.setNodeReady("worker-1", corev1.ConditionFalse)
+
+			t.awaitGatewayNodeNames("worker-2")
+			t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
+				Type:   gatewayConditionType,
+				Status: metav1.ConditionTrue,
+				Reason: "Success",
+				Message: `1 node(s) ("worker-2") are labeled as gateways, newly selected "worker-2" (zone "unknown"), ` +
+					`replaced unavailable gateway(s) "worker-1" (NotReady)`,
+			})
+		})
+	})
+
+	When("a gateway node labeled by the user is cordoned", func() {
+		BeforeEach(func() {
+			t.config.Spec.FailoverGracePeriod = &metav1.Duration{}
+			labelGateway(t.nodes[0], true)
+			t.nodes[0].Spec.Unschedulable = true
+		})
+
+		It("should label a replacement and keep the user label", func() {
+			t.awaitGatewayNodeNames("worker-2")
+			t.awaitGatewaysLabeledSuccessCondition()
+
+			node, err := t.kubeClient.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+			Expect(err).To(Succeed())
+			Expect(node.Labels).To(HaveKeyWithValue("submariner.io/gateway", "true"))
+		})
+	})
+
+	When("a gateway node becomes NotReady within the grace period", func() {
+		It("should not replace it", func() {
+			t.awaitGatewayNodeNames("worker-1")
+
+			t.setNodeReady("worker-1", corev1.ConditionFalse)
+
+			Consistently(func() []string {
+				names := []string{}
+				for _, node := range t.getLabeledWorkerNodes() {
+					names = append(names, node.Name)
+				}
+
+				return names
+			}, 300*time.Millisecond).Should(Equal([]string{"worker-1"}))
+		})
+	})
 }
 
 func newConfigControllerTestDriver() *configControllerTestDriver {
@@ -571,6 +1318,7 @@ func newConfigControllerTestDriver() *configControllerTestDriver {
 	BeforeEach(func() {
 		t.mockCtrl = gomock.NewController(GinkgoT())
 		t.config = newSubmarinerConfig()
+		t.watchedSecrets = nil
 
 		t.nodes = []*corev1.Node{
 			newWorkerNode("worker-1"),
@@ -583,12 +1331,18 @@ func newConfigControllerTestDriver() *configControllerTestDriver {
 		}
 
 		t.kubeClient = kubeFake.NewSimpleClientset()
+		t.hubKubeClient = kubeFake.NewSimpleClientset()
 		t.configClient = configFake.NewSimpleClientset()
 		t.dynamicClient = dynamicfake.NewSimpleDynamicClient(k8sScheme.Scheme)
 
 		t.managedClusterAddOnTestBase.init()
 
 		t.cloudProvider = cloudFake.NewMockProvider(t.mockCtrl)
+		t.checker = nil
+		t.driftDetector = nil
+		t.gatewayPlanner = nil
+		t.cloudTimeouts = submarineragent.CloudTimeouts{}
+		t.stepLog = nil
 		t.providerFactory = cloudFake.NewMockProviderFactory(t.mockCtrl)
 	})
 
@@ -631,8 +1385,10 @@ func newConfigControllerTestDriver() *configControllerTestDriver {
 			NodeInformer:         kubeInformerFactory.Core().V1().Nodes(),
 			AddOnInformer:        addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
 			ConfigInformer:       configInformerFactory.Submarineraddon().V1alpha1().SubmarinerConfigs(),
+			SecretInformer:       t.newSecretInformer,
 			SubmarinerInformer:   dynInformerFactory.ForResource(submarinerv1a1.GroupVersion.WithResource("submariners")),
 			CloudProviderFactory: t.providerFactory,
+			CloudTimeouts:        t.cloudTimeouts,
 			Recorder:             events.NewLoggingEventRecorder("test"),
 			OnSyncDefer:          GinkgoRecover,
 		})
@@ -663,15 +1419,62 @@ func newConfigControllerTestDriver() *configControllerTestDriver {
 
 func (t *configControllerTestDriver) expectProviderFactoryGet() {
 	if t.config != nil && t.config.Status.ManagedClusterInfo.Platform != "" {
-		provider := t.cloudProvider
-		if t.config.Status.ManagedClusterInfo.Platform == "Other" {
+		var provider cloud.Provider = t.cloudProvider
+		if t.checker != nil {
+			provider = &permissionsCheckingProvider{MockProvider: t.cloudProvider, MockPermissionsChecker: t.checker}
+		}
+
+		if t.driftDetector != nil {
+			provider = &driftDetectingProvider{MockProvider: t.cloudProvider, MockDriftDetector: t.driftDetector}
+		}
+
+		if t.checker != nil && t.driftDetector != nil {
+			provider = &checkingDriftDetectingProvider{
+				MockProvider: t.cloudProvider, MockPermissionsChecker: t.checker, MockDriftDetector: t.driftDetector,
+			}
+		}
+
+		if t.gatewayPlanner != nil {
+			provider = &gatewayPlanningProvider{MockProvider: t.cloudProvider, MockGatewayPlanner: t.gatewayPlanner}
+		}
+
+		found := t.config.Status.ManagedClusterInfo.Platform != "Other"
+		if !found {
 			provider = nil
 		}
 
-		t.providerFactory.EXPECT().Get(eqSubmarinerConfig(t.config), gomock.Any()).Return(provider, provider != nil, nil).AnyTimes()
+		// The cloud provider operations run sequentially, each with the step log the provider was created with.
+		t.providerFactory.EXPECT().Get(gomock.Any(), eqSubmarinerConfig(t.config), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ *configv1alpha1.SubmarinerConfig, _ events.Recorder, stepLog *cloudProvider.StepLog,
+			) (cloud.Provider, bool, error) {
+				t.stepLog = stepLog
+				return provider, found, nil
+			}).AnyTimes()
 	}
 }
 
+// newSecretInformer returns an informer for the Secret with the given name, the fake client doesn't filter the secrets by name
+// so the names of the secrets are recorded instead.
+func (t *configControllerTestDriver) newSecretInformer(name string, stopCh <-chan struct{}) corev1informers.SecretInformer {
+	t.secretsMutex.Lock()
+	t.watchedSecrets = append(t.watchedSecrets, name)
+	t.secretsMutex.Unlock()
+
+	informerFactory := kubeInformers.NewSharedInformerFactoryWithOptions(t.hubKubeClient, 0, kubeInformers.WithNamespace(clusterName))
+	informer := informerFactory.Core().V1().Secrets()
+	informer.Informer()
+	informerFactory.Start(stopCh)
+
+	return informer
+}
+
+func (t *configControllerTestDriver) watchedSecretNames() []string {
+	t.secretsMutex.Lock()
+	defer t.secretsMutex.Unlock()
+
+	return slices.Clone(t.watchedSecrets)
+}
+
 func (t *configControllerTestDriver) awaitGatewaysLabeledSuccessCondition() {
 	t.awaitSubmarinerConfigStatusCondition(&metav1.Condition{
 		Type:   gatewayConditionType,
@@ -753,6 +1556,17 @@ func (t *configControllerTestDriver) awaitLabeledNodes() {
 	}, 2).Should(Equal(t.config.Spec.Gateways), "The expected number of worker nodes weren't labeled")
 }
 
+func (t *configControllerTestDriver) awaitGatewayNodeNames(expected ...string) {
+	Eventually(func() []string {
+		names := []string{}
+		for _, node := range t.getLabeledWorkerNodes() {
+			names = append(names, node.Name)
+		}
+
+		return names
+	}, 2).Should(ConsistOf(expected), "The expected worker nodes weren't labeled")
+}
+
 func (t *configControllerTestDriver) awaitGatewayAnnotationOnNodes(num int) {
 	Eventually(func() int {
 		return len(t.getAnnotatedGatewayNodes())
@@ -777,6 +1591,20 @@ func (t *configControllerTestDriver) ensureLabeledNodes() {
 	}, 300*time.Millisecond).Should(Equal(t.config.Spec.Gateways))
 }
 
+func (t *configControllerTestDriver) setNodeReady(name string, status corev1.ConditionStatus) {
+	node, err := t.kubeClient.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	Expect(err).To(Succeed())
+
+	node.Status.Conditions = []corev1.NodeCondition{{
+		Type:               corev1.NodeReady,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+	}}
+
+	_, err = t.kubeClient.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+	Expect(err).To(Succeed())
+}
+
 func (t *configControllerTestDriver) finalizeAddOn() {
 	err := finalizer.Remove(context.TODO(), resource.ForAddon(t.addOnClient.AddonV1alpha1().ManagedClusterAddOns(
 		t.addOn.Namespace)), t.addOn, constants.SubmarinerAddOnFinalizer)
@@ -801,6 +1629,13 @@ func newWorkerNode(name string) *corev1.Node {
 	}
 }
 
+func newWorkerNodeInZone(name, zone string) *corev1.Node {
+	node := newWorkerNode(name)
+	node.Labels["topology.kubernetes.io/zone"] = zone
+
+	return node
+}
+
 func newSubmarinerConfig() *configv1alpha1.SubmarinerConfig {
 	return &configv1alpha1.SubmarinerConfig{
 		ObjectMeta: metav1.ObjectMeta{
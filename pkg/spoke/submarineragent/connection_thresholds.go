@@ -0,0 +1,112 @@
+package submarineragent
+
+import (
+	"fmt"
+	"time"
+
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	submarinermv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	reasonHighLatency = "HighLatency"
+	reasonUnstable    = "Unstable"
+)
+
+// thresholdKey identifies a latency threshold of the connection to a remote cluster.
+type thresholdKey struct {
+	clusterID string
+	reason    string
+}
+
+// thresholdViolation describes a latency threshold exceeded by a connection.
+type thresholdViolation struct {
+	reason  string
+	message string
+}
+
+// checkThresholds returns the first latency threshold exceeded by the given connection for longer than the sustained
+// duration, if any. When a threshold is exceeded but not for long enough yet, it also returns the earliest remaining time, even
+// if another threshold is already violated.
+func (c *connectionsStatusController) checkThresholds(connection *submarinermv1.Connection,
+	thresholds *configv1alpha1.ConnectionThresholds, now time.Time,
+) (*thresholdViolation, time.Duration) {
+	if thresholds == nil || connection.LatencyRTT == nil {
+		// The thresholds aren't exceeded while they're not checked, they're exceeded again from the next check.
+		delete(c.exceededSince, thresholdKey{clusterID: connection.Endpoint.ClusterID, reason: reasonHighLatency})
+		delete(c.exceededSince, thresholdKey{clusterID: connection.Endpoint.ClusterID, reason: reasonUnstable})
+
+		return nil, 0
+	}
+
+	var sustainedFor time.Duration
+	if thresholds.SustainedFor != nil {
+		sustainedFor = thresholds.SustainedFor.Duration
+	}
+
+	checks := []struct {
+		reason    string
+		measured  string
+		threshold *metav1.Duration
+		format    string
+	}{
+		{
+			reasonHighLatency, connection.LatencyRTT.Average, thresholds.MaxAverageRTT,
+			"The connection between clusters %q and %q has a high latency (average RTT %s exceeds %s)",
+		},
+		{
+			reasonUnstable, connection.LatencyRTT.StdDev, thresholds.MaxRTTStdDev,
+			"The connection between clusters %q and %q is unstable (RTT standard deviation %s exceeds %s)",
+		},
+	}
+
+	var (
+		violation    *thresholdViolation
+		requeueAfter time.Duration
+	)
+
+	for _, check := range checks {
+		key := thresholdKey{clusterID: connection.Endpoint.ClusterID, reason: check.reason}
+
+		measured, err := time.ParseDuration(check.measured)
+		if check.threshold == nil || err != nil || measured <= check.threshold.Duration {
+			delete(c.exceededSince, key)
+			continue
+		}
+
+		since, found := c.exceededSince[key]
+		if !found {
+			since = now
+			c.exceededSince[key] = since
+		}
+
+		if remaining := sustainedFor - now.Sub(since); remaining > 0 {
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+
+			continue
+		}
+
+		if violation == nil {
+			violation = &thresholdViolation{
+				reason: check.reason,
+				message: fmt.Sprintf(check.format, c.clusterName, connection.Endpoint.ClusterID, check.measured,
+					check.threshold.Duration),
+			}
+		}
+	}
+
+	return violation, requeueAfter
+}
+
+// pruneThresholds forgets since when the latency thresholds have been exceeded by the connections to the remote clusters which
+// weren't checked, e.g. the clusters which left the cluster set or whose connection is no longer established.
+func (c *connectionsStatusController) pruneThresholds(checked map[string]bool) {
+	for key := range c.exceededSince {
+		if !checked[key.clusterID] {
+			delete(c.exceededSince, key)
+		}
+	}
+}
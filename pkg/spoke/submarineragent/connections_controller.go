@@ -3,15 +3,25 @@ package submarineragent
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/stolostron/submariner-addon/pkg/addon"
+	"github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	configclient "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/clientset/versioned"
+	configinformer "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions/submarinerconfig/v1alpha1"
+	configlister "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/listers/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/resource"
 	submarinerv1alpha1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
 	submarinermv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -23,26 +33,36 @@ import (
 
 const (
 	submarinerConnectionDegraded = "SubmarinerConnectionDegraded"
+	// rttPublishInterval is the minimum interval between the publications of the gateways status which only differ by their
+	// RTT statistics.
+	rttPublishInterval = time.Minute
 )
 
 // connectionsStatusController watches the status of submariner CR and reflect the status
-// to submariner-addon on the hub cluster.
+// to submariner-addon and the status of the gateways to the submariner config on the hub cluster.
 type connectionsStatusController struct {
 	addOnClient      addonclient.Interface
+	configClient     configclient.Interface
+	configLister     configlister.SubmarinerConfigLister
 	submarinerLister cache.GenericLister
 	clusterName      string
+	exceededSince    map[thresholdKey]time.Time
+	rttPublishedAt   time.Time
 	logger           log.Logger
 }
 
 // NewConnectionsStatusController returns an instance of submarinerAgentStatusController.
-func NewConnectionsStatusController(clusterName string, addOnClient addonclient.Interface, submarinerInformer informers.GenericInformer,
-	recorder events.Recorder,
+func NewConnectionsStatusController(clusterName string, addOnClient addonclient.Interface, configClient configclient.Interface,
+	configInformer configinformer.SubmarinerConfigInformer, submarinerInformer informers.GenericInformer, recorder events.Recorder,
 ) factory.Controller {
 	name := "ConnectionsStatusController"
 	c := &connectionsStatusController{
 		addOnClient:      addOnClient,
+		configClient:     configClient,
+		configLister:     configInformer.Lister(),
 		submarinerLister: submarinerInformer.Lister(),
 		clusterName:      clusterName,
+		exceededSince:    map[thresholdKey]time.Time{},
 		logger:           log.Logger{Logger: logf.Log.WithName(name)},
 	}
 
@@ -61,6 +81,8 @@ func (c *connectionsStatusController) sync(ctx context.Context, syncCtx factory.
 	runtimeSubmariner, err := c.submarinerLister.ByNamespace(namespace).Get(name)
 	if errors.IsNotFound(err) {
 		// submariner cr is not found, could be deleted, ignore it.
+		metrics.SetConnections(nil)
+
 		return nil
 	}
 
@@ -78,10 +100,31 @@ func (c *connectionsStatusController) sync(ctx context.Context, syncCtx factory.
 		return err
 	}
 
+	config, err := c.configLister.SubmarinerConfigs(c.clusterName).Get(constants.SubmarinerConfigName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	var (
+		thresholds        *configv1alpha1.ConnectionThresholds
+		publishedGateways []configv1alpha1.GatewayStatus
+	)
+
+	if err == nil {
+		thresholds = config.Spec.ConnectionThresholds
+		publishedGateways = config.Status.Gateways
+	}
+
 	// check submariner agent status and update submariner-addon status on the hub cluster
-	condition := c.checkSubmarinerConnections(submariner)
+	condition, requeueAfter := c.checkSubmarinerConnections(submariner, thresholds)
+	if requeueAfter > 0 {
+		// check the connections again once the thresholds have been exceeded for the sustained duration
+		syncCtx.Queue().AddAfter(syncCtx.QueueKey(), requeueAfter)
+	}
 
 	updatedStatus, updated, err := addon.UpdateStatus(ctx, c.addOnClient, c.clusterName, addon.UpdateConditionFn(condition))
+	metrics.RecordStatusUpdateConflict("ManagedClusterAddOn", err)
+
 	if err != nil {
 		return err
 	}
@@ -93,14 +136,144 @@ func (c *connectionsStatusController) sync(ctx context.Context, syncCtx factory.
 			updatedStatus.Conditions)
 	}
 
+	// publish the status of every gateway to the submariner config on the hub cluster
+	gateways := gatewayStatusesOf(submariner)
+
+	metrics.SetConnections(connectionCountsOf(gateways))
+
+	// The RTT statistics change with every probe and every update of the SubmarinerConfig status requeues the hub controllers,
+	// the changes of the RTT statistics alone are only published at an interval.
+	if !equality.Semantic.DeepEqual(comparableGateways(publishedGateways, false), comparableGateways(gateways, false)) &&
+		equality.Semantic.DeepEqual(comparableGateways(publishedGateways, true), comparableGateways(gateways, true)) {
+		if remaining := rttPublishInterval - time.Since(c.rttPublishedAt); remaining > 0 {
+			syncCtx.Queue().AddAfter(syncCtx.QueueKey(), remaining)
+			return nil
+		}
+	}
+
+	_, updated, err = submarinerconfig.UpdateStatus(ctx,
+		c.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(c.clusterName), constants.SubmarinerConfigName,
+		submarinerconfig.UpdateGatewaysFn(gateways))
+	metrics.RecordStatusUpdateConflict("SubmarinerConfig", err)
+
+	if err != nil {
+		return err
+	}
+
+	if updated {
+		c.rttPublishedAt = time.Now()
+		c.logger.Infof("Updated SubmarinerConfig gateways status: %s", resource.ToJSON(gateways))
+	}
+
 	return nil
 }
 
-func (c *connectionsStatusController) checkSubmarinerConnections(submariner *submarinerv1alpha1.Submariner) *metav1.Condition {
+// comparableGateways returns a copy of the given gateway statuses without their HA status transition times and, if requested,
+// without the RTT statistics of their connections.
+func comparableGateways(gateways []configv1alpha1.GatewayStatus, withoutLatency bool) []configv1alpha1.GatewayStatus {
+	if gateways == nil {
+		return nil
+	}
+
+	stripped := make([]configv1alpha1.GatewayStatus, len(gateways))
+
+	for i := range gateways {
+		gateways[i].DeepCopyInto(&stripped[i])
+		stripped[i].LastHAStatusTransitionTime = nil
+
+		if !withoutLatency {
+			continue
+		}
+
+		for j := range stripped[i].Connections {
+			stripped[i].Connections[j].LatencyRTT = nil
+		}
+	}
+
+	return stripped
+}
+
+// connectionCountsOf returns the number of connections of the active gateways, by connection status.
+func connectionCountsOf(gateways []configv1alpha1.GatewayStatus) map[string]int {
+	counts := map[string]int{}
+
+	for i := range gateways {
+		if gateways[i].HAStatus != string(submarinermv1.HAStatusActive) {
+			continue
+		}
+
+		for j := range gateways[i].Connections {
+			counts[gateways[i].Connections[j].Status]++
+		}
+	}
+
+	return counts
+}
+
+func gatewayStatusesOf(submariner *submarinerv1alpha1.Submariner) []configv1alpha1.GatewayStatus {
+	if submariner.Status.Gateways == nil {
+		return nil
+	}
+
+	gateways := []configv1alpha1.GatewayStatus{}
+
+	for i := range *submariner.Status.Gateways {
+		gateway := &(*submariner.Status.Gateways)[i]
+
+		status := configv1alpha1.GatewayStatus{
+			NodeName:      gateway.LocalEndpoint.Hostname,
+			HAStatus:      string(gateway.HAStatus),
+			PublicIP:      gateway.LocalEndpoint.PublicIP,
+			PrivateIP:     gateway.LocalEndpoint.PrivateIP,
+			CableDriver:   gateway.LocalEndpoint.Backend,
+			StatusFailure: gateway.StatusFailure,
+		}
+
+		for i := range gateway.Connections {
+			connection := &gateway.Connections[i]
+
+			connectionStatus := configv1alpha1.GatewayConnectionStatus{
+				ClusterID:     connection.Endpoint.ClusterID,
+				Hostname:      connection.Endpoint.Hostname,
+				Status:        string(connection.Status),
+				StatusMessage: connection.StatusMessage,
+				UsingIP:       connection.UsingIP,
+			}
+
+			if connection.LatencyRTT != nil {
+				connectionStatus.LatencyRTT = &configv1alpha1.LatencyRTTStats{
+					Last:    connection.LatencyRTT.Last,
+					Min:     connection.LatencyRTT.Min,
+					Average: connection.LatencyRTT.Average,
+					Max:     connection.LatencyRTT.Max,
+					StdDev:  connection.LatencyRTT.StdDev,
+				}
+			}
+
+			status.Connections = append(status.Connections, connectionStatus)
+		}
+
+		gateways = append(gateways, status)
+	}
+
+	sort.Slice(gateways, func(i, j int) bool {
+		return gateways[i].NodeName < gateways[j].NodeName
+	})
+
+	return gateways
+}
+
+func (c *connectionsStatusController) checkSubmarinerConnections(submariner *submarinerv1alpha1.Submariner,
+	thresholds *configv1alpha1.ConnectionThresholds,
+) (*metav1.Condition, time.Duration) {
 	condition := &metav1.Condition{
 		Type: submarinerConnectionDegraded,
 	}
 
+	now := time.Now()
+
+	var requeueAfter time.Duration
+
 	var gateways []submarinermv1.GatewayStatus
 	if submariner.Status.Gateways != nil {
 		gateways = *submariner.Status.Gateways
@@ -108,6 +281,9 @@ func (c *connectionsStatusController) checkSubmarinerConnections(submariner *sub
 
 	connectedMessages := []string{}
 	unconnectedMessages := []string{}
+	violationMessages := map[string][]string{}
+	checked := map[string]bool{}
+
 	for i := range gateways {
 		gateway := &gateways[i]
 		if gateway.HAStatus != submarinermv1.HAStatusActive {
@@ -124,32 +300,66 @@ func (c *connectionsStatusController) checkSubmarinerConnections(submariner *sub
 				continue
 			}
 
+			checked[connection.Endpoint.ClusterID] = true
+
+			violation, remaining := c.checkThresholds(connection, thresholds, now)
+			if remaining > 0 && (requeueAfter == 0 || remaining < requeueAfter) {
+				requeueAfter = remaining
+			}
+
+			if violation != nil {
+				violationMessages[violation.reason] = append(violationMessages[violation.reason], violation.message)
+				continue
+			}
+
 			connectedMessages = append(connectedMessages, fmt.Sprintf("The connection between clusters %q and %q is established",
 				c.clusterName, connection.Endpoint.ClusterID))
 		}
 	}
 
-	if len(connectedMessages) == 0 && len(unconnectedMessages) == 0 {
+	c.pruneThresholds(checked)
+
+	violations := []string{}
+	violations = append(violations, violationMessages[reasonHighLatency]...)
+	violations = append(violations, violationMessages[reasonUnstable]...)
+
+	if len(connectedMessages) == 0 && len(unconnectedMessages) == 0 && len(violations) == 0 {
 		condition.Status = metav1.ConditionTrue
 		condition.Reason = "ConnectionsNotEstablished"
 		condition.Message = "There are no connections on gateways"
 
-		return condition
+		return condition, requeueAfter
 	}
 
 	if len(unconnectedMessages) != 0 {
 		condition.Status = metav1.ConditionTrue
 		condition.Reason = "ConnectionsDegraded"
 
+		connectedMessages = append(connectedMessages, violations...)
 		connectedMessages = append(connectedMessages, unconnectedMessages...)
 		condition.Message = strings.Join(connectedMessages, "\n")
 
-		return condition
+		return condition, requeueAfter
+	}
+
+	// connected but slow links are told apart from down links, a high latency prevails over an unstable latency
+	if len(violations) != 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonUnstable
+
+		if len(violationMessages[reasonHighLatency]) != 0 {
+			condition.Reason = reasonHighLatency
+		}
+
+		connectedMessages = append(connectedMessages, violations...)
+		condition.Message = strings.Join(connectedMessages, "\n")
+
+		return condition, requeueAfter
 	}
 
 	condition.Status = metav1.ConditionFalse
 	condition.Reason = "ConnectionsEstablished"
 	condition.Message = strings.Join(connectedMessages, "\n")
 
-	return condition
+	return condition, requeueAfter
 }
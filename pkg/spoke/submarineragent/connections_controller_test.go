@@ -2,15 +2,21 @@ package submarineragent_test
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/openshift/library-go/pkg/operator/events"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	configFake "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/clientset/versioned/fake"
+	configInformers "github.com/stolostron/submariner-addon/pkg/client/submarinerconfig/informers/externalversions"
+	"github.com/stolostron/submariner-addon/pkg/constants"
 	"github.com/stolostron/submariner-addon/pkg/spoke/submarineragent"
 	fakereactor "github.com/submariner-io/admiral/pkg/fake"
 	"github.com/submariner-io/admiral/pkg/resource"
 	submarinerv1alpha1 "github.com/submariner-io/submariner-operator/api/v1alpha1"
 	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
@@ -49,6 +55,78 @@ var _ = Describe("Connections Status Controller", func() {
 		})
 	})
 
+	When("the submariner gateways report their status", func() {
+		It("should publish the status of every gateway to the SubmarinerConfig", func() {
+			Eventually(func() []configv1alpha1.GatewayStatus {
+				config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+					constants.SubmarinerConfigName, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+
+				for i := range config.Status.Gateways {
+					config.Status.Gateways[i].LastHAStatusTransitionTime = nil
+				}
+
+				return config.Status.Gateways
+			}).Should(Equal([]configv1alpha1.GatewayStatus{
+				{
+					NodeName:    "gateway-1",
+					HAStatus:    string(submv1.HAStatusActive),
+					PublicIP:    "1.2.3.4",
+					PrivateIP:   "10.0.0.1",
+					CableDriver: "libreswan",
+					Connections: []configv1alpha1.GatewayConnectionStatus{
+						{
+							ClusterID: "cluster1",
+							Status:    string(submv1.Connected),
+							LatencyRTT: &configv1alpha1.LatencyRTTStats{
+								Last:    "1ms",
+								Min:     "1ms",
+								Average: "2ms",
+								Max:     "3ms",
+								StdDev:  "1ms",
+							},
+						},
+						{
+							ClusterID: "cluster2",
+							Status:    string(submv1.Connected),
+						},
+					},
+				},
+				{
+					NodeName: "gateway-2",
+					HAStatus: string(submv1.HAStatusPassive),
+					Connections: []configv1alpha1.GatewayConnectionStatus{
+						{
+							ClusterID: "cluster1",
+							Status:    string(submv1.ConnectionError),
+						},
+					},
+				},
+			}))
+		})
+	})
+
+	When("only the RTT statistics of a gateway connection change", func() {
+		It("should not publish them again immediately", func() {
+			Eventually(t.publishedConnection).Should(HaveField("LatencyRTT.Average", "2ms"))
+
+			(*t.submariner.Status.Gateways)[0].Connections[0].LatencyRTT.Average = "5ms"
+			_, err := t.submarinerClient.Update(context.TODO(), resource.MustToUnstructured(t.submariner), metav1.UpdateOptions{})
+			Expect(err).To(Succeed())
+
+			Consistently(t.publishedConnection, 300*time.Millisecond).Should(HaveField("LatencyRTT.Average", "2ms"))
+
+			By("Changing the status of the gateway connection")
+
+			(*t.submariner.Status.Gateways)[0].Connections[0].Status = submv1.Connecting
+			_, err = t.submarinerClient.Update(context.TODO(), resource.MustToUnstructured(t.submariner), metav1.UpdateOptions{})
+			Expect(err).To(Succeed())
+
+			Eventually(t.publishedConnection).Should(And(HaveField("Status", string(submv1.Connecting)),
+				HaveField("LatencyRTT.Average", "5ms")))
+		})
+	})
+
 	When("an active gateway connection is in the process of connecting", func() {
 		BeforeEach(func() {
 			(*t.submariner.Status.Gateways)[0].Connections[0].Status = submv1.Connecting
@@ -69,6 +147,90 @@ var _ = Describe("Connections Status Controller", func() {
 		})
 	})
 
+	When("connection thresholds are configured", func() {
+		BeforeEach(func() {
+			t.config.Spec.ConnectionThresholds = &configv1alpha1.ConnectionThresholds{}
+		})
+
+		Context("and the average RTT of an active gateway connection exceeds the maximum", func() {
+			BeforeEach(func() {
+				t.config.Spec.ConnectionThresholds.MaxAverageRTT = &metav1.Duration{Duration: time.Millisecond}
+			})
+
+			It("should update the ManagedClusterAddOn status condition to high latency", func() {
+				t.awaitStatusCondition(metav1.ConditionTrue, "HighLatency")
+			})
+		})
+
+		Context("and the RTT standard deviation of an active gateway connection exceeds the maximum", func() {
+			BeforeEach(func() {
+				t.config.Spec.ConnectionThresholds.MaxRTTStdDev = &metav1.Duration{Duration: 500 * time.Microsecond}
+			})
+
+			It("should update the ManagedClusterAddOn status condition to unstable", func() {
+				t.awaitStatusCondition(metav1.ConditionTrue, "Unstable")
+			})
+		})
+
+		Context("and the RTT of the active gateway connections is within the thresholds", func() {
+			BeforeEach(func() {
+				t.config.Spec.ConnectionThresholds.MaxAverageRTT = &metav1.Duration{Duration: 10 * time.Millisecond}
+				t.config.Spec.ConnectionThresholds.MaxRTTStdDev = &metav1.Duration{Duration: 10 * time.Millisecond}
+			})
+
+			It("should update the ManagedClusterAddOn status condition to connections established", func() {
+				t.awaitConnectionsEstablishedStatusCondition()
+			})
+		})
+
+		Context("and a threshold is exceeded for less than the sustained duration", func() {
+			BeforeEach(func() {
+				t.config.Spec.ConnectionThresholds.MaxAverageRTT = &metav1.Duration{Duration: time.Millisecond}
+				t.config.Spec.ConnectionThresholds.SustainedFor = &metav1.Duration{Duration: time.Hour}
+			})
+
+			It("should update the ManagedClusterAddOn status condition to connections established", func() {
+				t.awaitConnectionsEstablishedStatusCondition()
+			})
+		})
+
+		Context("and a threshold is exceeded again after the RTT statistics of the connection were missing", func() {
+			BeforeEach(func() {
+				t.config.Spec.ConnectionThresholds.MaxAverageRTT = &metav1.Duration{Duration: time.Millisecond}
+				t.config.Spec.ConnectionThresholds.SustainedFor = &metav1.Duration{Duration: 500 * time.Millisecond}
+			})
+
+			It("should only report it once it's exceeded again for the sustained duration", func() {
+				t.awaitConnectionsEstablishedStatusCondition()
+
+				latencyRTT := (*t.submariner.Status.Gateways)[0].Connections[0].LatencyRTT
+				(*t.submariner.Status.Gateways)[0].Connections[0].LatencyRTT = nil
+				_, err := t.submarinerClient.Update(context.TODO(), resource.MustToUnstructured(t.submariner), metav1.UpdateOptions{})
+				Expect(err).To(Succeed())
+
+				time.Sleep(600 * time.Millisecond)
+
+				(*t.submariner.Status.Gateways)[0].Connections[0].LatencyRTT = latencyRTT
+				_, err = t.submarinerClient.Update(context.TODO(), resource.MustToUnstructured(t.submariner), metav1.UpdateOptions{})
+				Expect(err).To(Succeed())
+
+				Consistently(t.connectionDegradedReason, 300*time.Millisecond).Should(Equal("ConnectionsEstablished"))
+				t.awaitStatusCondition(metav1.ConditionTrue, "HighLatency")
+			})
+		})
+
+		Context("and an active gateway connection also has an error", func() {
+			BeforeEach(func() {
+				t.config.Spec.ConnectionThresholds.MaxAverageRTT = &metav1.Duration{Duration: time.Millisecond}
+				(*t.submariner.Status.Gateways)[0].Connections[1].Status = submv1.ConnectionError
+			})
+
+			It("should update the ManagedClusterAddOn status condition to degraded", func() {
+				t.awaitConnectionsDegradedStatusCondition()
+			})
+		})
+	})
+
 	When("the gateway status isn't present", func() {
 		BeforeEach(func() {
 			t.submariner.Status.Gateways = nil
@@ -126,6 +288,8 @@ type connStatusControllerTestDriver struct {
 	managedClusterAddOnTestBase
 	submariner       *submarinerv1alpha1.Submariner
 	submarinerClient dynamic.ResourceInterface
+	config           *configv1alpha1.SubmarinerConfig
+	configClient     *configFake.Clientset
 	stop             context.CancelFunc
 }
 
@@ -142,12 +306,25 @@ func newConnStatusControllerTestDriver() *connStatusControllerTestDriver {
 				Gateways: &[]submv1.GatewayStatus{
 					{
 						HAStatus: submv1.HAStatusActive,
+						LocalEndpoint: submv1.EndpointSpec{
+							Hostname:  "gateway-1",
+							PublicIP:  "1.2.3.4",
+							PrivateIP: "10.0.0.1",
+							Backend:   "libreswan",
+						},
 						Connections: []submv1.Connection{
 							{
 								Status: submv1.Connected,
 								Endpoint: submv1.EndpointSpec{
 									ClusterID: "cluster1",
 								},
+								LatencyRTT: &submv1.LatencyRTTSpec{
+									Last:    "1ms",
+									Min:     "1ms",
+									Average: "2ms",
+									Max:     "3ms",
+									StdDev:  "1ms",
+								},
 							},
 							{
 								Status: submv1.Connected,
@@ -159,6 +336,9 @@ func newConnStatusControllerTestDriver() *connStatusControllerTestDriver {
 					},
 					{
 						HAStatus: submv1.HAStatusPassive,
+						LocalEndpoint: submv1.EndpointSpec{
+							Hostname: "gateway-2",
+						},
 						Connections: []submv1.Connection{
 							{
 								Status: submv1.ConnectionError,
@@ -173,9 +353,18 @@ func newConnStatusControllerTestDriver() *connStatusControllerTestDriver {
 		}
 
 		t.managedClusterAddOnTestBase.init()
+
+		t.config = &configv1alpha1.SubmarinerConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.SubmarinerConfigName,
+				Namespace: clusterName,
+			},
+		}
 	})
 
 	JustBeforeEach(func() {
+		t.configClient = configFake.NewSimpleClientset(t.config)
+
 		submarinerClient, dynamicInformerFactory, submarinerInformer := newDynamicClientWithInformer(submarinerNS)
 		t.submarinerClient = submarinerClient
 
@@ -184,16 +373,21 @@ func newConnStatusControllerTestDriver() *connStatusControllerTestDriver {
 
 		t.managedClusterAddOnTestBase.run()
 
-		controller := submarineragent.NewConnectionsStatusController(clusterName, t.addOnClient, submarinerInformer,
+		configInformerFactory := configInformers.NewSharedInformerFactory(t.configClient, 0)
+
+		controller := submarineragent.NewConnectionsStatusController(clusterName, t.addOnClient, t.configClient,
+			configInformerFactory.Submarineraddon().V1alpha1().SubmarinerConfigs(), submarinerInformer,
 			events.NewLoggingEventRecorder("test"))
 
 		var ctx context.Context
 
 		ctx, t.stop = context.WithCancel(context.TODO())
 
+		configInformerFactory.Start(ctx.Done())
 		dynamicInformerFactory.Start(ctx.Done())
 
-		cache.WaitForCacheSync(ctx.Done(), submarinerInformer.Informer().HasSynced)
+		cache.WaitForCacheSync(ctx.Done(), submarinerInformer.Informer().HasSynced,
+			configInformerFactory.Submarineraddon().V1alpha1().SubmarinerConfigs().Informer().HasSynced)
 
 		go controller.Run(ctx, 1)
 	})
@@ -224,3 +418,28 @@ func (t *connStatusControllerTestDriver) awaitConnectionsNotEstablishedStatusCon
 func (t *connStatusControllerTestDriver) awaitConnectionsDegradedStatusCondition() {
 	t.awaitStatusCondition(metav1.ConditionTrue, "ConnectionsDegraded")
 }
+
+func (t *connStatusControllerTestDriver) connectionDegradedReason() string {
+	addOn, err := t.addOnClient.AddonV1alpha1().ManagedClusterAddOns(clusterName).Get(context.TODO(),
+		constants.SubmarinerAddOnName, metav1.GetOptions{})
+	Expect(err).To(Succeed())
+
+	condition := meta.FindStatusCondition(addOn.Status.Conditions, connectionDegradedType)
+	if condition == nil {
+		return ""
+	}
+
+	return condition.Reason
+}
+
+func (t *connStatusControllerTestDriver) publishedConnection() configv1alpha1.GatewayConnectionStatus {
+	config, err := t.configClient.SubmarineraddonV1alpha1().SubmarinerConfigs(clusterName).Get(context.TODO(),
+		constants.SubmarinerConfigName, metav1.GetOptions{})
+	Expect(err).To(Succeed())
+
+	if len(config.Status.Gateways) == 0 || len(config.Status.Gateways[0].Connections) == 0 {
+		return configv1alpha1.GatewayConnectionStatus{}
+	}
+
+	return config.Status.Gateways[0].Connections[0]
+}
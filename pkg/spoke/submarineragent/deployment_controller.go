@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/stolostron/submariner-addon/pkg/addon"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/constants"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/names"
 	"github.com/submariner-io/admiral/pkg/resource"
@@ -25,44 +29,63 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-const (
-	subscriptionName = "submariner"
-)
+const subscriptionName = "submariner"
+
+// OLMCheckInterval is the interval at which the controller checks whether Operator Lifecycle Manager was installed,
+// while it isn't.
+var OLMCheckInterval = 5 * time.Minute
 
 const submarinerAgentDegraded = "SubmarinerAgentDegraded"
 
+// SubscriptionInformerFunc returns the informer of the OLM subscriptions, started, or nil if Operator Lifecycle Manager
+// isn't installed on the managed cluster.
+type SubscriptionInformerFunc func() informers.GenericInformer
+
 // deploymentStatusController watches the status of submariner deployments and submariner daemonsets
 // on the managed cluster and reports the status to the submariner-addon on the hub cluster.
 type deploymentStatusController struct {
-	addOnClient        addonclient.Interface
-	daemonSetLister    appsv1lister.DaemonSetLister
-	deploymentLister   appsv1lister.DeploymentLister
-	subscriptionLister cache.GenericLister
-	submarinerLister   cache.GenericLister
-	clusterName        string
-	namespace          string
-	logger             log.Logger
+	addOnClient          addonclient.Interface
+	daemonSetLister      appsv1lister.DaemonSetLister
+	deploymentLister     appsv1lister.DeploymentLister
+	subscriptionInformer SubscriptionInformerFunc
+	subscriptionLister   cache.GenericLister
+	olmCheckedAt         time.Time
+	submarinerLister     cache.GenericLister
+	clusterName          string
+	namespace            string
+	logger               log.Logger
 }
 
-// NewDeploymentStatusController returns an instance of deploymentStatusController.
+// NewDeploymentStatusController returns an instance of deploymentStatusController. If Operator Lifecycle Manager isn't
+// available on the managed cluster yet, the subscriptions are watched once it's installed, which is checked every
+// OLMCheckInterval.
 func NewDeploymentStatusController(clusterName string, installationNamespace string, addOnClient addonclient.Interface,
 	daemonsetInformer appsv1informers.DaemonSetInformer, deploymentInformer appsv1informers.DeploymentInformer,
-	subscriptionInformer informers.GenericInformer, submarinerInformer informers.GenericInformer, recorder events.Recorder,
+	subscriptionInformer SubscriptionInformerFunc, submarinerInformer informers.GenericInformer, recorder events.Recorder,
 ) factory.Controller {
 	name := "DeploymentStatusController"
 	c := &deploymentStatusController{
-		addOnClient:        addOnClient,
-		daemonSetLister:    daemonsetInformer.Lister(),
-		deploymentLister:   deploymentInformer.Lister(),
-		subscriptionLister: subscriptionInformer.Lister(),
-		submarinerLister:   submarinerInformer.Lister(),
-		clusterName:        clusterName,
-		namespace:          installationNamespace,
-		logger:             log.Logger{Logger: logf.Log.WithName(name)},
+		addOnClient:          addOnClient,
+		daemonSetLister:      daemonsetInformer.Lister(),
+		deploymentLister:     deploymentInformer.Lister(),
+		subscriptionInformer: subscriptionInformer,
+		submarinerLister:     submarinerInformer.Lister(),
+		clusterName:          clusterName,
+		namespace:            installationNamespace,
+		logger:               log.Logger{Logger: logf.Log.WithName(name)},
+	}
+
+	watched := []factory.Informer{daemonsetInformer.Informer(), deploymentInformer.Informer()}
+
+	c.olmCheckedAt = time.Now()
+	if informer := subscriptionInformer(); informer != nil {
+		c.subscriptionLister = informer.Lister()
+		watched = append(watched, informer.Informer())
 	}
 
 	return factory.New().
-		WithInformers(subscriptionInformer.Informer(), daemonsetInformer.Informer(), deploymentInformer.Informer()).
+		WithInformers(watched...).
+		ResyncEvery(OLMCheckInterval).
 		WithInformersQueueKeyFunc(func(obj runtime.Object) string {
 			key, _ := cache.MetaNamespaceKeyFunc(obj)
 
@@ -76,41 +99,23 @@ func (c *deploymentStatusController) sync(ctx context.Context, syncCtx factory.S
 	degradedConditionReasons := []string{}
 	degradedConditionMessages := []string{}
 
-	runtimeSub, err := c.subscriptionLister.ByNamespace(c.namespace).Get(subscriptionName)
-	if errors.IsNotFound(err) {
-		// submariner subscription is not found, could be deleted, ignore it.
-		return nil
-	}
-
-	if err != nil {
-		return err
-	}
+	c.watchSubscriptions(syncCtx)
 
-	unstructuredSub, err := runtime.DefaultUnstructuredConverter.ToUnstructured(runtimeSub)
+	installedVersion, found, err := c.checkSubscription(&degradedConditionReasons, &degradedConditionMessages)
 	if err != nil {
 		return err
 	}
 
-	sub := &operatorsv1alpha1.Subscription{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredSub, &sub); err != nil {
-		return err
-	}
-
-	if sub.Status.InstalledCSV == "" {
-		startingCSV := sub.Spec.StartingCSV
-		if startingCSV == "" {
-			startingCSV = "default"
-		}
-
-		channel := sub.Spec.Channel
-		if channel == "" {
-			channel = "default"
+	if !found {
+		installedVersion, found, err = c.checkManifestsInstallation()
+		if err != nil {
+			return err
 		}
+	}
 
-		degradedConditionReasons = append(degradedConditionReasons, "CSVNotInstalled")
-		degradedConditionMessages = append(degradedConditionMessages,
-			fmt.Sprintf("The submariner-operator CSV (%s) is not installed from channel (%s) in catalog source (%s/%s)",
-				startingCSV, channel, sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource))
+	if !found {
+		// neither the submariner subscription nor a manifests-installed operator is found, could be deleted, ignore it.
+		return nil
 	}
 
 	err = c.checkDeployments(&degradedConditionReasons, &degradedConditionMessages)
@@ -132,7 +137,7 @@ func (c *deploymentStatusController) sync(ctx context.Context, syncCtx factory.S
 		Type:    submarinerAgentDegraded,
 		Status:  metav1.ConditionFalse,
 		Reason:  "SubmarinerAgentDeployed",
-		Message: fmt.Sprintf("Submariner (%s) is deployed on managed cluster.", sub.Status.InstalledCSV),
+		Message: fmt.Sprintf("Submariner (%s) is deployed on managed cluster.", installedVersion),
 	}
 
 	if len(degradedConditionReasons) != 0 {
@@ -143,6 +148,8 @@ func (c *deploymentStatusController) sync(ctx context.Context, syncCtx factory.S
 
 	// check submariner agent status and update submariner-addon status on the hub cluster
 	updatedStatus, updated, err := addon.UpdateStatus(ctx, c.addOnClient, c.clusterName, addon.UpdateConditionFn(&submarinerAgentCondition))
+	metrics.RecordStatusUpdateConflict("ManagedClusterAddOn", err)
+
 	if err != nil {
 		return err
 	}
@@ -157,6 +164,105 @@ func (c *deploymentStatusController) sync(ctx context.Context, syncCtx factory.S
 	return nil
 }
 
+// watchSubscriptions starts watching the subscriptions once Operator Lifecycle Manager is installed, if it wasn't when the
+// controller was created. The result of the check is kept until the next resync, which may run slightly less than
+// OLMCheckInterval after the previous check.
+func (c *deploymentStatusController) watchSubscriptions(syncCtx factory.SyncContext) {
+	if c.subscriptionLister != nil || time.Since(c.olmCheckedAt) < OLMCheckInterval*9/10 {
+		return
+	}
+
+	c.olmCheckedAt = time.Now()
+
+	informer := c.subscriptionInformer()
+	if informer == nil {
+		return
+	}
+
+	c.logger.Infof("Operator Lifecycle Manager was installed, watching the submariner subscription")
+
+	enqueue := func(interface{}) {
+		syncCtx.Queue().Add(factory.DefaultQueueKey)
+	}
+
+	_, _ = informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+		DeleteFunc: enqueue,
+	})
+
+	c.subscriptionLister = informer.Lister()
+}
+
+// checkSubscription checks the submariner subscription, if OLM is used, and returns the installed CSV.
+func (c *deploymentStatusController) checkSubscription(degradedConditionReasons, degradedConditionMessages *[]string,
+) (string, bool, error) {
+	if c.subscriptionLister == nil {
+		return "", false, nil
+	}
+
+	runtimeSub, err := c.subscriptionLister.ByNamespace(c.namespace).Get(subscriptionName)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	unstructuredSub, err := runtime.DefaultUnstructuredConverter.ToUnstructured(runtimeSub)
+	if err != nil {
+		return "", false, err
+	}
+
+	sub := &operatorsv1alpha1.Subscription{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredSub, &sub); err != nil {
+		return "", false, err
+	}
+
+	if sub.Status.InstalledCSV == "" {
+		startingCSV := sub.Spec.StartingCSV
+		if startingCSV == "" {
+			startingCSV = "default"
+		}
+
+		channel := sub.Spec.Channel
+		if channel == "" {
+			channel = "default"
+		}
+
+		*degradedConditionReasons = append(*degradedConditionReasons, "CSVNotInstalled")
+		*degradedConditionMessages = append(*degradedConditionMessages,
+			fmt.Sprintf("The submariner-operator CSV (%s) is not installed from channel (%s) in catalog source (%s/%s)",
+				startingCSV, channel, sub.Spec.CatalogSourceNamespace, sub.Spec.CatalogSource))
+	}
+
+	return sub.Status.InstalledCSV, true, nil
+}
+
+// checkManifestsInstallation checks whether the operator was installed from manifests, without a subscription, and returns
+// the operator image.
+func (c *deploymentStatusController) checkManifestsInstallation() (string, bool, error) {
+	deployment, err := c.deploymentLister.Deployments(c.namespace).Get(names.OperatorComponent)
+	if errors.IsNotFound(err) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if deployment.Labels[constants.InstallModeLabel] != configv1alpha1.InstallModeManifests {
+		return "", false, nil
+	}
+
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return "", true, nil
+	}
+
+	return deployment.Spec.Template.Spec.Containers[0].Image, true, nil
+}
+
 func (c *deploymentStatusController) checkDeployment(name, reasonName string, degradedConditionReasons,
 	degradedConditionMessages *[]string,
 ) error {
@@ -2,11 +2,15 @@ package submarineragent_test
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/openshift/library-go/pkg/operator/events"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	"github.com/stolostron/submariner-addon/pkg/constants"
 	"github.com/stolostron/submariner-addon/pkg/spoke/submarineragent"
 	fakereactor "github.com/submariner-io/admiral/pkg/fake"
 	"github.com/submariner-io/admiral/pkg/names"
@@ -43,6 +47,84 @@ var _ = Describe("Deployment Status Controller", func() {
 		})
 	})
 
+	When("the operator is installed from manifests without a subscription", func() {
+		BeforeEach(func() {
+			t.subscription = nil
+			t.operatorDeployment.Labels = map[string]string{constants.InstallModeLabel: configv1alpha1.InstallModeManifests}
+		})
+
+		It("should update the ManagedClusterAddOn status condition to deployed", func() {
+			t.awaitStatusConditionDeployed()
+		})
+
+		Context("and OLM isn't available on the cluster", func() {
+			BeforeEach(func() {
+				t.olmUnavailable = true
+			})
+
+			It("should update the ManagedClusterAddOn status condition to deployed", func() {
+				t.awaitStatusConditionDeployed()
+			})
+		})
+
+		Context("and no operator deployment replica is initially available", func() {
+			BeforeEach(func() {
+				t.operatorDeployment.Status.AvailableReplicas = 0
+			})
+
+			It("should eventually update the ManagedClusterAddOn status condition from degraded to deployed", func() {
+				t.awaitStatusCondition(metav1.ConditionTrue, "NoOperatorAvailable")
+
+				t.operatorDeployment.Status.AvailableReplicas = 1
+				t.updateDeployment(t.operatorDeployment)
+
+				t.awaitStatusConditionDeployed()
+			})
+		})
+	})
+
+	When("OLM is installed once the controller is running", func() {
+		BeforeEach(func() {
+			t.olmUnavailable = true
+			t.subscription.Status.InstalledCSV = ""
+
+			interval := submarineragent.OLMCheckInterval
+			submarineragent.OLMCheckInterval = time.Second
+
+			DeferCleanup(func() {
+				submarineragent.OLMCheckInterval = interval
+			})
+		})
+
+		It("should eventually check the submariner subscription", func() {
+			t.awaitNoManagedClusterAddOnStatusCondition(deploymentDegradedType)
+
+			t.olmInstalled.Store(true)
+
+			t.awaitStatusCondition(metav1.ConditionTrue, "CSVNotInstalled")
+
+			t.subscription.Status.InstalledCSV = "submariner-csv"
+			t.updateSubscription()
+
+			t.awaitStatusConditionDeployed()
+		})
+	})
+
+	When("OLM isn't available on the cluster", func() {
+		BeforeEach(func() {
+			t.olmUnavailable = true
+		})
+
+		It("should not check whether it was installed on every sync", func() {
+			t.awaitNoManagedClusterAddOnStatusCondition(deploymentDegradedType)
+
+			t.updateDeployment(t.operatorDeployment)
+			t.updateDeployment(t.operatorDeployment)
+
+			Consistently(t.olmChecks.Load).Should(Equal(int32(1)))
+		})
+	})
+
 	When("the submariner subscription CSV isn't installed", func() {
 		BeforeEach(func() {
 			t.subscription.Status.InstalledCSV = ""
@@ -353,6 +435,9 @@ type deploymentControllerTestDriver struct {
 	lighthouseAgentDeployment   *appsv1.Deployment
 	lighthouseCoreDNSDeployment *appsv1.Deployment
 	globalnetDaemonSet          *appsv1.DaemonSet
+	olmUnavailable              bool
+	olmInstalled                atomic.Bool
+	olmChecks                   atomic.Int32
 	stop                        context.CancelFunc
 }
 
@@ -372,6 +457,7 @@ func newDeploymentControllerTestDriver() *deploymentControllerTestDriver {
 		t.lighthouseAgentDeployment = newLighthouseAgentDeployment()
 		t.lighthouseCoreDNSDeployment = newLighthouseCoreDNSDeployment()
 		t.globalnetDaemonSet = nil
+		t.olmUnavailable = false
 	})
 
 	JustBeforeEach(func() {
@@ -421,9 +507,20 @@ func newDeploymentControllerTestDriver() *deploymentControllerTestDriver {
 
 		t.managedClusterAddOnTestBase.run()
 
+		t.olmInstalled.Store(!t.olmUnavailable)
+		t.olmChecks.Store(0)
+
 		controller := submarineragent.NewDeploymentStatusController(clusterName, submarinerNS, t.addOnClient,
 			kubeInformerFactory.Apps().V1().DaemonSets(), kubeInformerFactory.Apps().V1().Deployments(),
-			subscriptionInformer, submarinerInformer, events.NewLoggingEventRecorder("test"))
+			func() kubeInformers.GenericInformer {
+				t.olmChecks.Add(1)
+
+				if !t.olmInstalled.Load() {
+					return nil
+				}
+
+				return subscriptionInformer
+			}, submarinerInformer, events.NewLoggingEventRecorder("test"))
 
 		var ctx context.Context
 
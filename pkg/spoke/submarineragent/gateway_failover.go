@@ -0,0 +1,158 @@
+package submarineragent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	operatorhelpers "github.com/openshift/library-go/pkg/operator/v1helpers"
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const defaultFailoverGracePeriod = 5 * time.Minute
+
+// gatewayAvailability splits the gateway nodes according to their availability.
+type gatewayAvailability struct {
+	// usable are the available gateways and the unavailable ones still within the grace period.
+	usable []*corev1.Node
+	// failedOver are the unavailable gateways past the grace period, which must be replaced.
+	failedOver []*corev1.Node
+	// pending describes the unavailable gateways still within the grace period.
+	pending []string
+	// requeueAfter is the time left until the grace period of the first pending gateway expires.
+	requeueAfter time.Duration
+}
+
+func failoverGracePeriod(config *configv1alpha1.SubmarinerConfig) time.Duration {
+	if config.Spec.FailoverGracePeriod == nil {
+		return defaultFailoverGracePeriod
+	}
+
+	return config.Spec.FailoverGracePeriod.Duration
+}
+
+// isNodeAvailable returns whether the node is Ready and schedulable. A node without a Ready condition is considered
+// available.
+func isNodeAvailable(node *corev1.Node) bool {
+	return unavailabilityReason(node) == ""
+}
+
+func unavailabilityReason(node *corev1.Node) string {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady && node.Status.Conditions[i].Status != corev1.ConditionTrue {
+			return "NotReady"
+		}
+	}
+
+	if node.Spec.Unschedulable {
+		return "cordoned"
+	}
+
+	return ""
+}
+
+// unavailableSince returns since when the node is unavailable, based on its Ready condition or its unschedulable taint,
+// falling back to the time the controller first saw it unavailable.
+func (c *submarinerConfigController) unavailableSince(node *corev1.Node, reason string) time.Time {
+	var since time.Time
+
+	if reason == "NotReady" {
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == corev1.NodeReady {
+				since = node.Status.Conditions[i].LastTransitionTime.Time
+			}
+		}
+	} else {
+		for i := range node.Spec.Taints {
+			if node.Spec.Taints[i].Key == corev1.TaintNodeUnschedulable && node.Spec.Taints[i].TimeAdded != nil {
+				since = node.Spec.Taints[i].TimeAdded.Time
+			}
+		}
+	}
+
+	if !since.IsZero() {
+		return since
+	}
+
+	seen, found := c.unavailableNodes[node.Name]
+	if !found {
+		seen = time.Now()
+		c.unavailableNodes[node.Name] = seen
+	}
+
+	return seen
+}
+
+func (c *submarinerConfigController) checkGatewayAvailability(config *configv1alpha1.SubmarinerConfig,
+	gateways []*corev1.Node,
+) *gatewayAvailability {
+	gracePeriod := failoverGracePeriod(config)
+	availability := &gatewayAvailability{}
+
+	for _, gateway := range gateways {
+		reason := unavailabilityReason(gateway)
+		if reason == "" {
+			delete(c.unavailableNodes, gateway.Name)
+			availability.usable = append(availability.usable, gateway)
+
+			continue
+		}
+
+		remaining := gracePeriod - time.Since(c.unavailableSince(gateway, reason))
+		if remaining <= 0 {
+			availability.failedOver = append(availability.failedOver, gateway)
+			continue
+		}
+
+		availability.usable = append(availability.usable, gateway)
+		availability.pending = append(availability.pending, fmt.Sprintf("%q (%s, replaced in %s)", gateway.Name, reason,
+			remaining.Round(time.Second)))
+
+		if availability.requeueAfter == 0 || remaining < availability.requeueAfter {
+			availability.requeueAfter = remaining
+		}
+	}
+
+	return availability
+}
+
+// failOverGateways unlabels the unavailable gateways which were labeled by the controller, now that the given gateways
+// replace them. The gateways labeled by the user are left as is. It returns the description of the replaced gateways.
+func (c *submarinerConfigController) failOverGateways(ctx context.Context, recorder events.Recorder,
+	failedOver []*corev1.Node, gatewayNames []string,
+) ([]string, error) {
+	errs := []error{}
+
+	for _, gateway := range failedOver {
+		reason := unavailabilityReason(gateway)
+
+		if _, labeledBySubmariner := gateway.Annotations[gatewayLabeledBySubmariner]; !labeledBySubmariner {
+			continue
+		}
+
+		if err := c.unlabelNode(ctx, gateway); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		delete(c.unavailableNodes, gateway.Name)
+
+		recorder.Eventf("SubmarinerGatewayFailedOver", "Unlabeled gateway node %q as it is %s, the gateways are now %q",
+			gateway.Name, reason, strings.Join(gatewayNames, ","))
+	}
+
+	return describeUnavailable(failedOver), operatorhelpers.NewMultiLineAggregate(errs)
+}
+
+func describeUnavailable(gateways []*corev1.Node) []string {
+	descriptions := []string{}
+
+	for _, gateway := range gateways {
+		descriptions = append(descriptions, fmt.Sprintf("%q (%s)", gateway.Name, unavailabilityReason(gateway)))
+	}
+
+	return descriptions
+}
@@ -0,0 +1,227 @@
+package submarineragent
+
+import (
+	"fmt"
+	"sort"
+
+	configv1alpha1 "github.com/stolostron/submariner-addon/pkg/apis/submarinerconfig/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	defaultZoneTopologyKey = "topology.kubernetes.io/zone"
+	unknownZone            = "unknown"
+)
+
+// gatewaySelector selects the nodes to label and unlabel as gateways according to the gateway selection policy.
+type gatewaySelector struct {
+	nodeSelector    labels.Selector
+	excludeSelector labels.Selector
+	zoneKey         string
+	spread          bool
+	preferred       map[string]int
+}
+
+func newGatewaySelector(config *configv1alpha1.SubmarinerConfig) (*gatewaySelector, error) {
+	s := &gatewaySelector{
+		nodeSelector:    labels.Everything(),
+		excludeSelector: labels.Nothing(),
+		zoneKey:         defaultZoneTopologyKey,
+		preferred:       map[string]int{},
+	}
+
+	policy := config.Spec.SelectionPolicy
+	if policy == nil {
+		return s, nil
+	}
+
+	var err error
+
+	if policy.NodeSelector != nil {
+		s.nodeSelector, err = metav1.LabelSelectorAsSelector(policy.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node selector: %w", err)
+		}
+	}
+
+	if policy.ExcludeSelector != nil {
+		s.excludeSelector, err = metav1.LabelSelectorAsSelector(policy.ExcludeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude selector: %w", err)
+		}
+	}
+
+	if policy.ZoneTopologyKey != "" {
+		s.zoneKey = policy.ZoneTopologyKey
+	}
+
+	s.spread = policy.SpreadAcrossZones
+
+	for i, name := range policy.PreferredNodes {
+		if _, found := s.preferred[name]; !found {
+			s.preferred[name] = i
+		}
+	}
+
+	return s, nil
+}
+
+// isEligible returns whether the node matches the node selector and doesn't match the exclusion selector.
+func (s *gatewaySelector) isEligible(node *corev1.Node) bool {
+	nodeLabels := labels.Set(node.Labels)
+
+	return s.nodeSelector.Matches(nodeLabels) && !s.excludeSelector.Matches(nodeLabels)
+}
+
+func (s *gatewaySelector) zoneOf(node *corev1.Node) string {
+	zone := node.Labels[s.zoneKey]
+	if zone == "" {
+		return unknownZone
+	}
+
+	return zone
+}
+
+// rankOf returns the preference rank of the node, the lower the more preferred.
+func (s *gatewaySelector) rankOf(node *corev1.Node) int {
+	if rank, found := s.preferred[node.Name]; found {
+		return rank
+	}
+
+	return len(s.preferred)
+}
+
+func (s *gatewaySelector) describe(node *corev1.Node) string {
+	if _, found := s.preferred[node.Name]; found {
+		return fmt.Sprintf("%q (preferred node in zone %q)", node.Name, s.zoneOf(node))
+	}
+
+	return fmt.Sprintf("%q (zone %q)", node.Name, s.zoneOf(node))
+}
+
+// selectGateways selects the given number of gateways among the candidates, preferring the preferred nodes and then the
+// zones with the fewest current gateways. If the desired number can't be satisfied, no node is selected and the reason
+// is returned.
+func (s *gatewaySelector) selectGateways(candidates, current []*corev1.Node, count int) ([]*corev1.Node, []string, string) {
+	zoneCounts := map[string]int{}
+	for _, node := range current {
+		zoneCounts[s.zoneOf(node)]++
+	}
+
+	remaining := []*corev1.Node{}
+
+	for _, node := range candidates {
+		if s.isEligible(node) && isNodeAvailable(node) {
+			remaining = append(remaining, node)
+		}
+	}
+
+	eligible := len(remaining)
+	selected := []*corev1.Node{}
+	reasons := []string{}
+
+	for len(selected) < count {
+		best := -1
+
+		for i, node := range remaining {
+			if s.spread && zoneCounts[s.zoneOf(node)] > 0 {
+				continue
+			}
+
+			if best < 0 || s.isBetterGateway(node, remaining[best], zoneCounts) {
+				best = i
+			}
+		}
+
+		if best < 0 {
+			break
+		}
+
+		node := remaining[best]
+		remaining = append(remaining[:best], remaining[best+1:]...)
+
+		zoneCounts[s.zoneOf(node)]++
+		selected = append(selected, node)
+		reasons = append(reasons, s.describe(node))
+	}
+
+	if len(selected) < count {
+		reason := fmt.Sprintf("%d available node(s) match the gateway selection policy", eligible)
+		if s.spread {
+			reason += fmt.Sprintf(", only %d of them can be selected in zones without a gateway", len(selected))
+		}
+
+		return nil, nil, reason
+	}
+
+	return selected, reasons, ""
+}
+
+func (s *gatewaySelector) isBetterGateway(node, other *corev1.Node, zoneCounts map[string]int) bool {
+	if s.rankOf(node) != s.rankOf(other) {
+		return s.rankOf(node) < s.rankOf(other)
+	}
+
+	if zoneCounts[s.zoneOf(node)] != zoneCounts[s.zoneOf(other)] {
+		return zoneCounts[s.zoneOf(node)] < zoneCounts[s.zoneOf(other)]
+	}
+
+	return node.Name < other.Name
+}
+
+// selectForRemoval selects the given number of gateways to unlabel, starting with the gateways which no longer match the
+// selection policy, then the unavailable ones, then the least preferred ones and then the ones in the zones with the
+// most gateways.
+func (s *gatewaySelector) selectForRemoval(current []*corev1.Node, count int) []*corev1.Node {
+	zoneCounts := map[string]int{}
+	for _, node := range current {
+		zoneCounts[s.zoneOf(node)]++
+	}
+
+	remaining := append([]*corev1.Node{}, current...)
+	selected := []*corev1.Node{}
+
+	for len(selected) < count && len(remaining) > 0 {
+		worst := 0
+
+		for i := 1; i < len(remaining); i++ {
+			if s.isWorseGateway(remaining[i], remaining[worst], zoneCounts) {
+				worst = i
+			}
+		}
+
+		node := remaining[worst]
+		remaining = append(remaining[:worst], remaining[worst+1:]...)
+
+		zoneCounts[s.zoneOf(node)]--
+		selected = append(selected, node)
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	return selected
+}
+
+func (s *gatewaySelector) isWorseGateway(node, other *corev1.Node, zoneCounts map[string]int) bool {
+	if s.isEligible(node) != s.isEligible(other) {
+		return !s.isEligible(node)
+	}
+
+	if isNodeAvailable(node) != isNodeAvailable(other) {
+		return !isNodeAvailable(node)
+	}
+
+	if s.rankOf(node) != s.rankOf(other) {
+		return s.rankOf(node) > s.rankOf(other)
+	}
+
+	if zoneCounts[s.zoneOf(node)] != zoneCounts[s.zoneOf(other)] {
+		return zoneCounts[s.zoneOf(node)] > zoneCounts[s.zoneOf(other)]
+	}
+
+	return node.Name > other.Name
+}
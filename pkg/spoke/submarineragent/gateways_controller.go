@@ -9,6 +9,7 @@ import (
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/stolostron/submariner-addon/pkg/addon"
+	"github.com/stolostron/submariner-addon/pkg/metrics"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -92,6 +93,8 @@ func (c *gatewaysStatusController) sync(ctx context.Context, syncCtx factory.Syn
 
 	// check submariner agent status and update submariner-addon status on the hub cluster
 	updatedStatus, updated, err := addon.UpdateStatus(ctx, c.addOnClient, c.clusterName, addon.UpdateConditionFn(&gatewayNodeCondtion))
+	metrics.RecordStatusUpdateConflict("ManagedClusterAddOn", err)
+
 	if err != nil {
 		return err
 	}
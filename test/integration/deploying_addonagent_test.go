@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/component-base/metrics/legacyregistry"
 )
 
 const (
@@ -46,7 +47,10 @@ var _ = Describe("Submariner addon agent", func() {
 	When("the submariner spoke agent is run on a managed cluster", func() {
 		submarinerGVR, _ := schema.ParseResourceArg("submariners.v1alpha1.submariner.io")
 
-		var installationNamespace string
+		var (
+			installationNamespace string
+			agentOptions          *spoke.AgentOptions
+		)
 
 		BeforeEach(func() {
 			By("Create the ManagedCluster's namespace")
@@ -68,15 +72,14 @@ var _ = Describe("Submariner addon agent", func() {
 
 			ctx, cancel := context.WithCancel(context.Background())
 
+			agentOptions = spoke.NewAgentOptions()
+			agentOptions.InstallationNamespace = installationNamespace
+			agentOptions.HubRestConfig = cfg
+			agentOptions.ClusterName = managedClusterName
+
 			go func() {
 				defer GinkgoRecover()
 
-				agentOptions := spoke.AgentOptions{
-					InstallationNamespace: installationNamespace,
-					HubRestConfig:         cfg,
-					ClusterName:           managedClusterName,
-				}
-
 				err := agentOptions.RunAgent(ctx, &controllercmd.ControllerContext{
 					KubeConfig:    cfg,
 					EventRecorder: util.NewIntegrationTestEventRecorder("submariner-addon-agent-test"),
@@ -109,5 +112,64 @@ var _ = Describe("Submariner addon agent", func() {
 				return meta.IsStatusConditionTrue(addOn.Status.Conditions, "SubmarinerConnectionDegraded")
 			}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
 		})
+
+		It("should report healthy once its informers have synced and the hub cluster has been reached", func() {
+			Eventually(func() error {
+				for _, check := range agentOptions.ReadinessChecks() {
+					if err := check.Check(nil); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}, eventuallyTimeout, eventuallyInterval).Should(Succeed())
+		})
+
+		It("should export the connections reported in the Submariner resource status", func() {
+			By("Create Submariner resource on managed cluster")
+
+			submariner, err := dynamicClient.Resource(*submarinerGVR).Namespace(installationNamespace).Create(context.Background(),
+				util.NewSubmariner(submarinerCRName), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Update the Submariner status with the gateway connections")
+
+			util.SetSubmarinerDeployedStatus(submariner)
+			util.SetSubmarinerGatewayConnections(submariner, map[string]string{
+				"cluster-a": "connected",
+				"cluster-b": "connected",
+				"cluster-c": "error",
+			})
+			_, err = dynamicClient.Resource(*submarinerGVR).Namespace(installationNamespace).UpdateStatus(context.Background(),
+				submariner, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() map[string]float64 {
+				return connectionsMetric()
+			}, eventuallyTimeout, eventuallyInterval).Should(Equal(map[string]float64{"connected": 2, "error": 1}))
+		})
 	})
 })
+
+func connectionsMetric() map[string]float64 {
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	values := map[string]float64{}
+
+	for _, family := range families {
+		if family.GetName() != "open_cluster_management_submariner_addon_agent_connections" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "status" {
+					values[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	return values
+}
@@ -222,25 +222,28 @@ func startControllerManager() func() {
 
 	return func() {
 		stop()
+		deleteClusterManagementAddOn()
+	}
+}
 
-		err := admutil.Update(context.Background(), resource.ForClusterAddon(addOnClient.AddonV1alpha1().ClusterManagementAddOns()),
-			&addonv1alpha1.ClusterManagementAddOn{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: constants.SubmarinerAddOnName,
-				},
-			}, func(existing *addonv1alpha1.ClusterManagementAddOn) (*addonv1alpha1.ClusterManagementAddOn, error) {
-				existing.Finalizers = nil
-				return existing, nil
-			})
-		if !apierrors.IsNotFound(err) {
-			Expect(err).NotTo(HaveOccurred())
-		}
-
-		err = addOnClient.AddonV1alpha1().ClusterManagementAddOns().Delete(context.Background(), constants.SubmarinerAddOnName,
-			metav1.DeleteOptions{})
-		if !apierrors.IsNotFound(err) {
-			Expect(err).NotTo(HaveOccurred())
-		}
+func deleteClusterManagementAddOn() {
+	err := admutil.Update(context.Background(), resource.ForClusterAddon(addOnClient.AddonV1alpha1().ClusterManagementAddOns()),
+		&addonv1alpha1.ClusterManagementAddOn{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: constants.SubmarinerAddOnName,
+			},
+		}, func(existing *addonv1alpha1.ClusterManagementAddOn) (*addonv1alpha1.ClusterManagementAddOn, error) {
+			existing.Finalizers = nil
+			return existing, nil
+		})
+	if !apierrors.IsNotFound(err) {
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	err = addOnClient.AddonV1alpha1().ClusterManagementAddOns().Delete(context.Background(), constants.SubmarinerAddOnName,
+		metav1.DeleteOptions{})
+	if !apierrors.IsNotFound(err) {
+		Expect(err).NotTo(HaveOccurred())
 	}
 }
 
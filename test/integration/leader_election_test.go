@@ -0,0 +1,150 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	leaderelectionconverter "github.com/openshift/library-go/pkg/config/leaderelection"
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/stolostron/submariner-addon/pkg/hub"
+	"github.com/stolostron/submariner-addon/pkg/hub/submarineragent"
+	"github.com/stolostron/submariner-addon/test/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/leaderelection"
+)
+
+const (
+	leaderIdentity  = "replica-1"
+	standbyIdentity = "replica-2"
+)
+
+var _ = Describe("Hub controller leader election", func() {
+	var (
+		leaderElection     configv1.LeaderElection
+		managedClusterName string
+		releaseOnCancel    bool
+		stopLeader         context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		managedClusterName = fmt.Sprintf("cluster-%s", rand.String(6))
+
+		leaderElection = hub.LeaderElection()
+		leaderElection.Namespace = "default"
+		leaderElection.Name = fmt.Sprintf("%s-lock-%s", hub.ControllerName, rand.String(6))
+		leaderElection.LeaseDuration = metav1.Duration{Duration: 6 * time.Second}
+		leaderElection.RenewDeadline = metav1.Duration{Duration: 4 * time.Second}
+		leaderElection.RetryPeriod = metav1.Duration{Duration: time.Second}
+
+		createClusterManagementAddOn(context.Background())
+		DeferCleanup(deleteClusterManagementAddOn)
+	})
+
+	JustBeforeEach(func() {
+		By(fmt.Sprintf("Start the %q replica and await its leadership", leaderIdentity))
+
+		stopLeader = startControllerManagerReplica(leaderIdentity, leaderElection, releaseOnCancel)
+		DeferCleanup(stopLeader)
+
+		Eventually(func() string {
+			return leaseHolder(leaderElection)
+		}, eventuallyTimeout, eventuallyInterval).Should(Equal(leaderIdentity))
+
+		By(fmt.Sprintf("Start the %q replica on standby", standbyIdentity))
+
+		DeferCleanup(startControllerManagerReplica(standbyIdentity, leaderElection, true))
+
+		Consistently(func() string {
+			return leaseHolder(leaderElection)
+		}, 3*time.Second, 500*time.Millisecond).Should(Equal(leaderIdentity))
+
+		By("Deploy a ManagedCluster with the submariner ManagedClusterAddOn")
+
+		managedClusterSetName, brokerNamespace := deployManagedClusterSet()
+		deployManagedClusterWithAddOn(managedClusterSetName, managedClusterName, brokerNamespace)
+
+		By("Await the first ManifestWork before stopping the leader mid-reconcile")
+
+		Eventually(func() bool {
+			return util.CheckManifestWorks(workClient, managedClusterName, true, submarineragent.OperatorManifestWorkName)
+		}, eventuallyTimeout, eventuallyInterval).Should(BeTrue())
+
+		stopLeader()
+	})
+
+	testHandOver := func() {
+		It("should hand over to the standby replica, which completes the deployment without re-applying the ManifestWorks", func() {
+			Eventually(func() string {
+				return leaseHolder(leaderElection)
+			}, eventuallyTimeout, eventuallyInterval).Should(Equal(standbyIdentity))
+
+			awaitSubmarinerManifestWorks(managedClusterName)
+
+			for _, name := range []string{submarineragent.OperatorManifestWorkName, submarineragent.SubmarinerCRManifestWorkName} {
+				work, err := workClient.WorkV1().ManifestWorks(managedClusterName).Get(context.Background(), name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(work.Generation).To(Equal(int64(1)), "ManifestWork %q was updated", name)
+			}
+		})
+	}
+
+	When("the leader shuts down and releases its lease", func() {
+		BeforeEach(func() {
+			releaseOnCancel = true
+		})
+
+		testHandOver()
+	})
+
+	When("the leader is killed without releasing its lease", func() {
+		BeforeEach(func() {
+			releaseOnCancel = false
+		})
+
+		testHandOver()
+	})
+})
+
+// startControllerManagerReplica runs a replica of the hub controller manager with the given identity, as the hub command does
+// with the leader election configured by the controllercmd package. Losing the leadership stops the controllers of the
+// replica rather than exiting the process.
+func startControllerManagerReplica(identity string, leaderElection configv1.LeaderElection, releaseOnCancel bool) context.CancelFunc {
+	ctx, stop := context.WithCancel(context.Background())
+
+	config, err := leaderelectionconverter.ToLeaderElectionWithLease(cfg, leaderElection, hub.ControllerName, identity)
+	Expect(err).NotTo(HaveOccurred())
+
+	config.ReleaseOnCancel = releaseOnCancel
+	config.Callbacks = leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			defer GinkgoRecover()
+
+			addOnOptions := hub.AddOnOptions{AgentImage: "test"}
+			err := addOnOptions.RunControllerManager(ctx, &controllercmd.ControllerContext{
+				KubeConfig:    cfg,
+				EventRecorder: util.NewIntegrationTestEventRecorder(identity),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		},
+		OnStoppedLeading: func() {},
+	}
+
+	go leaderelection.RunOrDie(ctx, config)
+
+	return stop
+}
+
+func leaseHolder(leaderElection configv1.LeaderElection) string {
+	lease, err := kubeClient.CoordinationV1().Leases(leaderElection.Namespace).Get(context.Background(), leaderElection.Name,
+		metav1.GetOptions{})
+	if err != nil || lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+
+	return *lease.Spec.HolderIdentity
+}
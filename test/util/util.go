@@ -239,6 +239,48 @@ func SetSubmarinerDeployedStatus(submariner *unstructured.Unstructured) {
 	}
 }
 
+// SetSubmarinerGatewayConnections sets an active gateway in the status of the given Submariner, connected to the given remote
+// clusters with the given connection statuses.
+func SetSubmarinerGatewayConnections(submariner *unstructured.Unstructured, connectionStatuses map[string]string) {
+	connections := []interface{}{}
+	for clusterID, status := range connectionStatuses {
+		connections = append(connections, map[string]interface{}{
+			"endpoint":      newEndpoint(clusterID, clusterID+"-gateway"),
+			"status":        status,
+			"statusMessage": "",
+		})
+	}
+
+	status, _ := submariner.Object["status"].(map[string]interface{})
+	if status == nil {
+		status = map[string]interface{}{}
+		submariner.Object["status"] = status
+	}
+
+	status["gateways"] = []interface{}{
+		map[string]interface{}{
+			"connections":   connections,
+			"haStatus":      "active",
+			"localEndpoint": newEndpoint("test", "test-gateway"),
+			"statusFailure": "",
+			"version":       "",
+		},
+	}
+}
+
+func newEndpoint(clusterID, hostname string) map[string]interface{} {
+	return map[string]interface{}{
+		"backend":     "libreswan",
+		"cable_name":  "submariner-cable-" + hostname,
+		"cluster_id":  clusterID,
+		"hostname":    hostname,
+		"nat_enabled": false,
+		"private_ip":  "10.0.0.1",
+		"public_ip":   "",
+		"subnets":     []interface{}{"10.0.0.0/16"},
+	}
+}
+
 func NewIntegrationTestEventRecorder(comp string) events.Recorder {
 	return &IntegrationTestEventRecorder{component: comp}
 }